@@ -60,6 +60,10 @@ ok  	testdata/scenarios/pass.yaml	0.000s
                     - scenarigo/v1.0.0
                   body:
                     message: request
+                curl:
+                  curl -X POST 'http://127.0.0.1:12345/echo' \
+                    -H 'User-Agent: scenarigo/v1.0.0' \
+                    --data-binary $'{"message": "request"}\n'
                 response:
                   status: 200 OK
                   statusCode: 200
@@ -121,6 +125,10 @@ FAIL
                     - scenarigo/v1.0.0
                   body:
                     message: request
+                curl:
+                  curl -X POST 'http://127.0.0.1:12345/echo' \
+                    -H 'User-Agent: scenarigo/v1.0.0' \
+                    --data-binary $'{"message": "request"}\n'
                 response:
                   status: 200 OK
                   statusCode: 200
@@ -168,6 +176,10 @@ Failed tests:
                     - scenarigo/v1.0.0
                   body:
                     message: request
+                curl:
+                  curl -X POST 'http://127.0.0.1:12345/echo' \
+                    -H 'User-Agent: scenarigo/v1.0.0' \
+                    --data-binary $'{"message": "request"}\n'
                 response:
                   status: 200 OK
                   statusCode: 200
@@ -194,6 +206,7 @@ FAIL
 			expectReports: []string{
 				"./testdata/report.json",
 				"./testdata/junit.xml",
+				"./testdata/report.har",
 			},
 		},
 		"failed to create reports": {
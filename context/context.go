@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/goccy/go-yaml/ast"
+	"github.com/scenarigo/scenarigo/internal/har"
+	"github.com/scenarigo/scenarigo/internal/netutil"
 	"github.com/scenarigo/scenarigo/reporter"
 )
 
@@ -17,6 +19,10 @@ type (
 	keyVars             struct{}
 	keySecrets          struct{}
 	keySteps            struct{}
+	keyCookies          struct{}
+	keyHARRecorder      struct{}
+	keyResolver         struct{}
+	keyArtifactsDir     struct{}
 	keyRequest          struct{}
 	keyResponse         struct{}
 	keyYAMLNode         struct{}
@@ -170,6 +176,10 @@ func (c *Context) WithSecrets(s any) *Context {
 	secrets, _ := c.ctx.Value(keySecrets{}).(*Secrets)
 	secrets = secrets.Append(s)
 	reporter.SetLogReplacer(c.reporter, secrets)
+	if rec := c.HARRecorder(); rec != nil {
+		// the secrets are masked when the HAR file is written
+		rec.AddReplacer(secrets)
+	}
 	return newContext(
 		context.WithValue(c.ctx, keySecrets{}, secrets),
 		c.reqCtx,
@@ -207,6 +217,90 @@ func (c *Context) Steps() *Steps {
 	return nil
 }
 
+// WithCookies returns a copy of c with cookies.
+func (c *Context) WithCookies(cookies *Cookies) *Context {
+	if cookies == nil {
+		return c
+	}
+	return newContext(
+		context.WithValue(c.ctx, keyCookies{}, cookies),
+		c.reqCtx,
+		c.reporter,
+	)
+}
+
+// Cookies returns the cookie jar.
+func (c *Context) Cookies() *Cookies {
+	v, ok := c.ctx.Value(keyCookies{}).(*Cookies)
+	if ok {
+		return v
+	}
+	return nil
+}
+
+// WithHARRecorder returns a copy of c with the recorder of the HTTP exchanges.
+func (c *Context) WithHARRecorder(r *har.Recorder) *Context {
+	if r == nil {
+		return c
+	}
+	return newContext(
+		context.WithValue(c.ctx, keyHARRecorder{}, r),
+		c.reqCtx,
+		c.reporter,
+	)
+}
+
+// HARRecorder returns the recorder of the HTTP exchanges.
+func (c *Context) HARRecorder() *har.Recorder {
+	v, ok := c.ctx.Value(keyHARRecorder{}).(*har.Recorder)
+	if ok {
+		return v
+	}
+	return nil
+}
+
+// WithResolver returns a copy of c with the resolver which overrides the addresses to connect.
+func (c *Context) WithResolver(r netutil.Resolver) *Context {
+	if len(r) == 0 {
+		return c
+	}
+	return newContext(
+		context.WithValue(c.ctx, keyResolver{}, r),
+		c.reqCtx,
+		c.reporter,
+	)
+}
+
+// Resolver returns the resolver which overrides the addresses to connect.
+func (c *Context) Resolver() netutil.Resolver {
+	v, ok := c.ctx.Value(keyResolver{}).(netutil.Resolver)
+	if ok {
+		return v
+	}
+	return nil
+}
+
+// WithArtifactsDir returns a copy of c with the directory to save the artifacts (e.g., response bodies) of the run.
+func (c *Context) WithArtifactsDir(path string) *Context {
+	if path == "" {
+		return c
+	}
+	return newContext(
+		context.WithValue(c.ctx, keyArtifactsDir{}, path),
+		c.reqCtx,
+		c.reporter,
+	)
+}
+
+// ArtifactsDir returns the directory to save the artifacts of the run.
+func (c *Context) ArtifactsDir() string {
+	path, ok := c.ctx.Value(keyArtifactsDir{}).(string)
+	if ok {
+		return path
+	}
+	return ""
+}
+
 // WithRequest returns a copy of c with request.
 func (c *Context) WithRequest(req interface{}) *Context {
 	if req == nil {
@@ -0,0 +1,75 @@
+package context
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+)
+
+// Cookies represents a cookie jar shared across the steps of a scenario.
+type Cookies struct {
+	mu   sync.Mutex
+	jar  *cookiejar.Jar
+	urls []*url.URL // URLs which set cookies, in the order of last update
+}
+
+// NewCookies returns a new empty cookie jar.
+func NewCookies() *Cookies {
+	jar, _ := cookiejar.New(nil) // never returns an error
+	return &Cookies{
+		jar: jar,
+	}
+}
+
+// SetCookies implements http.CookieJar interface.
+func (c *Cookies) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	if len(cookies) == 0 {
+		return
+	}
+	c.jar.SetCookies(u, cookies)
+
+	key := *u
+	key.RawQuery = ""
+	key.Fragment = ""
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, v := range c.urls {
+		if v.String() == key.String() {
+			c.urls = append(c.urls[:i], c.urls[i+1:]...)
+			break
+		}
+	}
+	c.urls = append(c.urls, &key)
+}
+
+// Cookies implements http.CookieJar interface.
+func (c *Cookies) Cookies(u *url.URL) []*http.Cookie {
+	return c.jar.Cookies(u)
+}
+
+// Get returns the value of the cookie with the given name.
+// If cookies with the same name are set by different URLs, the most recently set one is returned.
+func (c *Cookies) Get(name string) (string, bool) {
+	c.mu.Lock()
+	urls := make([]*url.URL, len(c.urls))
+	copy(urls, c.urls)
+	c.mu.Unlock()
+	for i := len(urls) - 1; i >= 0; i-- {
+		for _, cookie := range c.jar.Cookies(urls[i]) {
+			if cookie.Name == name {
+				return cookie.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ExtractByKey implements query.KeyExtractor interface.
+func (c *Cookies) ExtractByKey(key string) (interface{}, bool) {
+	v, ok := c.Get(key)
+	if ok {
+		return v, true
+	}
+	return nil, false
+}
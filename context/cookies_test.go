@@ -0,0 +1,63 @@
+package context
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCookies(t *testing.T) {
+	cookies := NewCookies()
+	foo, err := url.Parse("http://foo.example.com/login?q=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bar, err := url.Parse("http://bar.example.com/api/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cookies.SetCookies(foo, []*http.Cookie{
+		{Name: "session_id", Value: "foo"},
+		{Name: "lang", Value: "en"},
+	})
+	cookies.SetCookies(bar, []*http.Cookie{
+		{Name: "session_id", Value: "bar", Path: "/api"},
+	})
+	t.Run("http.CookieJar", func(t *testing.T) {
+		got := cookies.Cookies(foo)
+		if len(got) != 2 {
+			t.Fatalf("expect 2 cookies but got %d", len(got))
+		}
+	})
+	t.Run("get the most recently set one", func(t *testing.T) {
+		v, ok := cookies.Get("session_id")
+		if !ok {
+			t.Fatal("not found")
+		}
+		if got, expect := v, "bar"; got != expect {
+			t.Errorf("expect %q but got %q", expect, got)
+		}
+	})
+	t.Run("ExtractByKey", func(t *testing.T) {
+		if v, ok := cookies.ExtractByKey("lang"); !ok {
+			t.Fatal("not found")
+		} else if got, expect := v, "en"; got != expect {
+			t.Errorf("expect %q but got %q", expect, got)
+		}
+		if _, ok := cookies.ExtractByKey("baz"); ok {
+			t.Fatal("found")
+		}
+	})
+	t.Run("deleted", func(t *testing.T) {
+		cookies.SetCookies(bar, []*http.Cookie{
+			{Name: "session_id", Path: "/api", MaxAge: -1},
+		})
+		v, ok := cookies.Get("session_id")
+		if !ok {
+			t.Fatal("not found")
+		}
+		if got, expect := v, "foo"; got != expect {
+			t.Errorf("expect %q but got %q", expect, got)
+		}
+	})
+}
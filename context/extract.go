@@ -6,6 +6,7 @@ const (
 	nameVars     = "vars"
 	nameSecrets  = "secrets"
 	nameSteps    = "steps"
+	nameCookies  = "cookies"
 	nameRequest  = "request"
 	nameResponse = "response"
 	nameEnv      = "env"
@@ -37,6 +38,11 @@ func (c *Context) ExtractByKey(key string) (interface{}, bool) {
 		if v != nil {
 			return v, true
 		}
+	case nameCookies:
+		v := c.Cookies()
+		if v != nil {
+			return v, true
+		}
 	case nameRequest:
 		v := c.Request()
 		if v != nil {
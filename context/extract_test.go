@@ -1,6 +1,8 @@
 package context
 
 import (
+	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -54,6 +56,17 @@ func TestContext_ExtractKey(t *testing.T) {
 			query:  "steps.foo.result",
 			expect: "passed",
 		},
+		"cookies": {
+			ctx: func(ctx *Context) *Context {
+				cookies := NewCookies()
+				cookies.SetCookies(&url.URL{Scheme: "http", Host: "example.com"}, []*http.Cookie{
+					{Name: "session_id", Value: "xxx"},
+				})
+				return ctx.WithCookies(cookies)
+			},
+			query:  "cookies.session_id",
+			expect: "xxx",
+		},
 		"request": {
 			ctx: func(ctx *Context) *Context {
 				return ctx.WithRequest(vars)
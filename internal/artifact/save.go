@@ -0,0 +1,93 @@
+// Package artifact provides functions to save the artifacts of a test run.
+package artifact
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/reporter"
+)
+
+// ReportAttributeName is the name of the report attribute which has the path of the saved file.
+const ReportAttributeName = "artifact"
+
+// SaveOption represents an option to save the response to a file in the artifacts directory.
+type SaveOption struct {
+	// Path is the path of the file relative to the artifacts directory.
+	Path string `yaml:"path,omitempty"`
+	// Value is the value to save instead of the raw response body (e.g., '{{response.body.items}}').
+	// Strings and byte slices are saved as they are, and the other values are encoded as JSON.
+	Value interface{} `yaml:"value,omitempty"`
+}
+
+// Save saves raw or the value of the option to the file and adds the path of the file to the test report.
+// It returns the path of the saved file, or an empty string if the option is nil.
+// It must be called with the context which has the response to execute the template of the value.
+func (o *SaveOption) Save(ctx *context.Context, raw []byte) (string, error) {
+	if o == nil {
+		return "", nil
+	}
+	path, err := o.path(ctx)
+	if err != nil {
+		return "", err
+	}
+	b := raw
+	if o.Value != nil {
+		v, err := ctx.ExecuteTemplate(o.Value)
+		if err != nil {
+			return "", errors.WrapPath(err, "value", "failed to execute template")
+		}
+		b, err = encode(v)
+		if err != nil {
+			return "", errors.WrapPath(err, "value", "failed to encode value")
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", errors.Errorf("failed to create directory: %s", err)
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return "", errors.Errorf("failed to save response: %s", err)
+	}
+	ctx.Reporter().Logf("saved response to %s", path)
+	reporter.AddAttribute(ctx.Reporter(), ReportAttributeName, path)
+	return path, nil
+}
+
+// path returns the path of the file in the artifacts directory.
+func (o *SaveOption) path(ctx *context.Context) (string, error) {
+	if o.Path == "" {
+		return "", errors.ErrorPath("path", "path is required")
+	}
+	x, err := ctx.ExecuteTemplate(o.Path)
+	if err != nil {
+		return "", errors.WrapPath(err, "path", "failed to execute template")
+	}
+	p, ok := x.(string)
+	if !ok {
+		return "", errors.ErrorPathf("path", "path must be string but got %T", x)
+	}
+	// prevent writing files outside of the artifacts directory
+	if !filepath.IsLocal(p) {
+		return "", errors.ErrorPathf("path", "path must be a relative path in the artifacts directory but got %q", p)
+	}
+	return filepath.Join(ctx.ArtifactsDir(), p), nil
+}
+
+func encode(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case proto.Message:
+		return protojson.Marshal(v)
+	default:
+		return json.Marshal(v)
+	}
+}
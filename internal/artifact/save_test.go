@@ -0,0 +1,168 @@
+package artifact
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/reporter"
+)
+
+func TestSaveOption_Save(t *testing.T) {
+	raw := []byte(`{"items":["a","b"]}`)
+	vars := map[string]interface{}{
+		"name": "items",
+		"response": map[string]interface{}{
+			"items": []interface{}{"a", "b"},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			opt        *SaveOption
+			expectPath string
+			expect     string
+		}{
+			"raw": {
+				opt: &SaveOption{
+					Path: "response.json",
+				},
+				expectPath: "response.json",
+				expect:     string(raw),
+			},
+			"template path": {
+				opt: &SaveOption{
+					Path: "{{vars.name}}/response.json",
+				},
+				expectPath: "items/response.json",
+				expect:     string(raw),
+			},
+			"string value": {
+				opt: &SaveOption{
+					Path:  "item.txt",
+					Value: "{{vars.response.items[0]}}",
+				},
+				expectPath: "item.txt",
+				expect:     "a",
+			},
+			"JSON value": {
+				opt: &SaveOption{
+					Path:  "items.json",
+					Value: "{{vars.response.items}}",
+				},
+				expectPath: "items.json",
+				expect:     `["a","b"]`,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				dir := t.TempDir()
+				var (
+					report *reporter.TestReport
+					err    error
+				)
+				ok := reporter.Run(func(rptr reporter.Reporter) {
+					rptr.Run("file.yaml", func(rptr reporter.Reporter) {
+						rptr.Run("scenario", func(rptr reporter.Reporter) {
+							rptr.Run("step", func(rptr reporter.Reporter) {
+								ctx := context.New(rptr).WithArtifactsDir(dir).WithVars(vars)
+								path, err := test.opt.Save(ctx, raw)
+								if err != nil {
+									rptr.Fatalf("unexpected error: %s", err)
+								}
+								if got, expect := path, filepath.Join(dir, test.expectPath); got != expect {
+									rptr.Errorf("expect path %q but got %q", expect, got)
+								}
+							})
+						})
+					})
+					report, err = reporter.GenerateTestReport(rptr)
+				}, reporter.WithWriter(io.Discard))
+				if !ok {
+					t.Fatal("failed to save")
+				}
+				if err != nil {
+					t.Fatalf("failed to generate report: %s", err)
+				}
+				path := filepath.Join(dir, test.expectPath)
+				b, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("failed to read file: %s", err)
+				}
+				if got, expect := string(b), test.expect; got != expect {
+					t.Errorf("expect %q but got %q", expect, got)
+				}
+				if diff := cmp.Diff([]reporter.ReportAttribute{
+					{Name: ReportAttributeName, Value: path},
+				}, report.Files[0].Scenarios[0].Steps[0].Attributes); diff != "" {
+					t.Errorf("differs (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var opt *SaveOption
+		path, err := opt.Save(context.FromT(t), raw)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if path != "" {
+			t.Errorf("unexpected path: %q", path)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			opt    *SaveOption
+			expect string
+		}{
+			"no path": {
+				opt:    &SaveOption{},
+				expect: ".path: path is required",
+			},
+			"not string path": {
+				opt: &SaveOption{
+					Path: "{{1}}",
+				},
+				expect: ".path: path must be string but got int64",
+			},
+			"absolute path": {
+				opt: &SaveOption{
+					Path: "/tmp/response.json",
+				},
+				expect: `.path: path must be a relative path in the artifacts directory but got "/tmp/response.json"`,
+			},
+			"outside of the directory": {
+				opt: &SaveOption{
+					Path: "../response.json",
+				},
+				expect: `.path: path must be a relative path in the artifacts directory but got "../response.json"`,
+			},
+			"invalid value": {
+				opt: &SaveOption{
+					Path:  "response.json",
+					Value: "{{vars.unknown}}",
+				},
+				expect: ".value: failed to execute template",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				ctx := context.FromT(t).WithArtifactsDir(t.TempDir()).WithVars(vars)
+				_, err := test.opt.Save(ctx, raw)
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if !strings.Contains(err.Error(), test.expect) {
+					t.Errorf("expect error %q but got %q", test.expect, err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,31 @@
+// Package fileutil provides functions to load the files referred from the scenarios.
+package fileutil
+
+import (
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/internal/filepathutil"
+	"github.com/scenarigo/scenarigo/internal/yamlutil"
+)
+
+// Load executes the template of path and loads the file whose path is relative to the scenario file.
+// JSON and YAML files are decoded into a value, and the other files are returned as a string.
+func Load(ctx *context.Context, path string, opts ...yaml.DecodeOption) (interface{}, error) {
+	x, err := ctx.ExecuteTemplate(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute template")
+	}
+	p, ok := x.(string)
+	if !ok {
+		return nil, errors.Errorf("path must be string but got %T", x)
+	}
+	v, err := yamlutil.ReadFile(filepathutil.From(filepath.Dir(ctx.ScenarioFilepath()), p), opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read file")
+	}
+	return v, nil
+}
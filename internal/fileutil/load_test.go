@@ -0,0 +1,93 @@
+package fileutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/scenarigo/scenarigo/context"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "body.yaml"), []byte("name: '{{vars.name}}'\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+	ctx := context.FromT(t).
+		WithScenarioFilepath(filepath.Join(dir, "scenario.yaml")).
+		WithVars(map[string]interface{}{"file": "body.yaml"})
+
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			path   string
+			opts   []yaml.DecodeOption
+			expect interface{}
+		}{
+			"relative to the scenario file": {
+				path: "body.yaml",
+				expect: map[string]interface{}{
+					"name": "{{vars.name}}",
+				},
+			},
+			"template path": {
+				path: "{{vars.file}}",
+				opts: []yaml.DecodeOption{yaml.UseOrderedMap()},
+				expect: yaml.MapSlice{
+					{Key: "name", Value: "{{vars.name}}"},
+				},
+			},
+			"absolute path": {
+				path: filepath.Join(dir, "body.yaml"),
+				expect: map[string]interface{}{
+					"name": "{{vars.name}}",
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				got, err := Load(ctx, test.path, test.opts...)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if diff := cmp.Diff(test.expect, got); diff != "" {
+					t.Errorf("differs (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			path   string
+			expect string
+		}{
+			"invalid template": {
+				path:   "{{",
+				expect: "failed to execute template",
+			},
+			"not string path": {
+				path:   "{{1}}",
+				expect: "path must be string but got int64",
+			},
+			"not found": {
+				path:   "not-found.yaml",
+				expect: "failed to read file",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := Load(ctx, test.path)
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if !strings.Contains(err.Error(), test.expect) {
+					t.Errorf("expect error %q but got %q", test.expect, err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,240 @@
+// Package har provides the HTTP Archive (HAR) 1.2 format to export the HTTP exchanges.
+// See http://www.softwareishard.com/blog/har-12-spec/ for the specification.
+package har
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/scenarigo/scenarigo/version"
+)
+
+// Version is the version of the HAR format.
+const Version = "1.2"
+
+// HAR represents the root of a HAR file.
+type HAR struct {
+	Log *Log `json:"log"`
+}
+
+// Log represents the exported data.
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+// Creator represents the application which created the log.
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Entry represents an HTTP exchange.
+type Entry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	// Time is the total elapsed time of the request in milliseconds.
+	Time     float64  `json:"time"`
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+	Cache    Cache    `json:"cache"`
+	Timings  Timings  `json:"timings"`
+	// Comment is the name of the test step which sent the request.
+	Comment string `json:"comment,omitempty"`
+}
+
+// Request represents an HTTP request.
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+// Response represents an HTTP response.
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+	// Error is the reason why the request failed (a custom field used by browsers).
+	Error string `json:"_error,omitempty"`
+}
+
+// Cookie represents a cookie.
+type Cookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// NameValue represents a pair of name and value such as a header and a query parameter.
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData represents the body of a request.
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	// Encoding is "base64" if Text is encoded since the body is not a valid UTF-8 text (a custom field like the one of Content).
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Content represents the body of a response.
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	// Encoding is "base64" if Text is encoded since the body is not a valid UTF-8 text.
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Cache represents the cache usage. It is always empty since the requests are never cached.
+type Cache struct{}
+
+// Timings represents the time spent on each phase of a request in milliseconds.
+// -1 means that the phase is not applicable or unknown.
+type Timings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// Milliseconds returns d in milliseconds as the HAR format uses.
+func Milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Replacer replaces the secrets in a string.
+type Replacer interface {
+	ReplaceAll(string) string
+}
+
+// Recorder records the HTTP exchanges during a run. It is safe for concurrent use.
+// The secrets in the entries are replaced when the entries are exported
+// since the secrets can be added after the exchanges are recorded (e.g., bound from the response).
+type Recorder struct {
+	m         sync.Mutex
+	entries   []Entry
+	replacers []Replacer
+}
+
+// NewRecorder returns a new recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Add adds an entry.
+func (r *Recorder) Add(e Entry) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.entries = append(r.entries, e)
+}
+
+// AddReplacer adds a replacer to mask the secrets in the entries.
+func (r *Recorder) AddReplacer(rep Replacer) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.replacers = append(r.replacers, rep)
+}
+
+// HAR returns the recorded entries as HAR. The entries are sorted by the started time.
+func (r *Recorder) HAR() *HAR {
+	r.m.Lock()
+	entries := make([]Entry, len(r.entries))
+	for i, e := range r.entries {
+		entries[i] = e.mask(r.replacers)
+	}
+	r.m.Unlock()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+	})
+	return &HAR{
+		Log: &Log{
+			Version: Version,
+			Creator: Creator{
+				Name:    "scenarigo",
+				Version: version.String(),
+			},
+			Entries: entries,
+		},
+	}
+}
+
+// Write writes the recorded entries to w as a HAR file.
+func (r *Recorder) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.HAR())
+}
+
+// mask returns a copy of e whose secrets are replaced.
+// The base64 encoded bodies are not replaced.
+func (e Entry) mask(replacers []Replacer) Entry {
+	if len(replacers) == 0 {
+		return e
+	}
+	mask := func(s string) string {
+		for _, rep := range replacers {
+			s = rep.ReplaceAll(s)
+		}
+		return s
+	}
+	e.Request.URL = mask(e.Request.URL)
+	e.Request.Cookies = maskCookies(e.Request.Cookies, mask)
+	e.Request.Headers = maskNameValues(e.Request.Headers, mask)
+	e.Request.QueryString = maskNameValues(e.Request.QueryString, mask)
+	if d := e.Request.PostData; d != nil && d.Encoding == "" {
+		e.Request.PostData = &PostData{
+			MimeType: d.MimeType,
+			Text:     mask(d.Text),
+		}
+	}
+	e.Response.Cookies = maskCookies(e.Response.Cookies, mask)
+	e.Response.Headers = maskNameValues(e.Response.Headers, mask)
+	if e.Response.Content.Encoding == "" {
+		e.Response.Content.Text = mask(e.Response.Content.Text)
+	}
+	e.Response.Error = mask(e.Response.Error)
+	return e
+}
+
+func maskCookies(cookies []Cookie, mask func(string) string) []Cookie {
+	if cookies == nil {
+		return nil
+	}
+	masked := make([]Cookie, len(cookies))
+	for i, c := range cookies {
+		masked[i] = Cookie{Name: c.Name, Value: mask(c.Value)}
+	}
+	return masked
+}
+
+func maskNameValues(nvs []NameValue, mask func(string) string) []NameValue {
+	if nvs == nil {
+		return nil
+	}
+	masked := make([]NameValue, len(nvs))
+	for i, nv := range nvs {
+		masked[i] = NameValue{Name: mask(nv.Name), Value: mask(nv.Value)}
+	}
+	return masked
+}
@@ -0,0 +1,98 @@
+package har
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/scenarigo/scenarigo/version"
+)
+
+func TestRecorder(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rec := NewRecorder()
+	rec.Add(Entry{StartedDateTime: now.Add(time.Second), Comment: "second"})
+	rec.Add(Entry{StartedDateTime: now, Comment: "first"})
+
+	h := rec.HAR()
+	if got, expect := h.Log.Version, Version; got != expect {
+		t.Errorf("expect version %q but got %q", expect, got)
+	}
+	if diff := cmp.Diff(Creator{Name: "scenarigo", Version: version.String()}, h.Log.Creator); diff != "" {
+		t.Errorf("creator differs (-want +got):\n%s", diff)
+	}
+	comments := make([]string, len(h.Log.Entries))
+	for i, e := range h.Log.Entries {
+		comments[i] = e.Comment
+	}
+	if diff := cmp.Diff([]string{"first", "second"}, comments); diff != "" {
+		t.Errorf("entries are not sorted (-want +got):\n%s", diff)
+	}
+
+	var buf bytes.Buffer
+	if err := rec.Write(&buf); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+	var got HAR
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal: %s", err)
+	}
+	if diff := cmp.Diff(h, &got); diff != "" {
+		t.Errorf("differs (-want +got):\n%s", diff)
+	}
+}
+
+type replacer map[string]string
+
+func (r replacer) ReplaceAll(s string) string {
+	for old, v := range r {
+		s = strings.ReplaceAll(s, old, v)
+	}
+	return s
+}
+
+func TestRecorder_AddReplacer(t *testing.T) {
+	rec := NewRecorder()
+	rec.Add(Entry{
+		Request: Request{
+			URL:         "http://localhost/?token=SECRET",
+			Headers:     []NameValue{{Name: "Authorization", Value: "Bearer SECRET"}},
+			QueryString: []NameValue{{Name: "token", Value: "SECRET"}},
+			PostData:    &PostData{MimeType: "text/plain", Text: "SECRET"},
+		},
+		Response: Response{
+			Cookies: []Cookie{{Name: "session", Value: "SECRET"}},
+			Content: Content{Text: "U0VDUkVU", Encoding: "base64"},
+			Error:   "SECRET",
+		},
+	})
+	// the replacer added after the entry is also applied
+	rec.AddReplacer(replacer{"SECRET": "{{secrets.token}}"})
+
+	expect := Entry{
+		Request: Request{
+			URL:         "http://localhost/?token={{secrets.token}}",
+			Headers:     []NameValue{{Name: "Authorization", Value: "Bearer {{secrets.token}}"}},
+			QueryString: []NameValue{{Name: "token", Value: "{{secrets.token}}"}},
+			PostData:    &PostData{MimeType: "text/plain", Text: "{{secrets.token}}"},
+		},
+		Response: Response{
+			Cookies: []Cookie{{Name: "session", Value: "{{secrets.token}}"}},
+			Content: Content{Text: "U0VDUkVU", Encoding: "base64"},
+			Error:   "{{secrets.token}}",
+		},
+	}
+	if diff := cmp.Diff([]Entry{expect}, rec.HAR().Log.Entries); diff != "" {
+		t.Errorf("differs (-want +got):\n%s", diff)
+	}
+}
+
+func TestMilliseconds(t *testing.T) {
+	if got, expect := Milliseconds(1500*time.Microsecond), 1.5; got != expect {
+		t.Errorf("expect %v but got %v", expect, got)
+	}
+}
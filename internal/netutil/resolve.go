@@ -0,0 +1,78 @@
+// Package netutil provides utilities for network connections.
+package netutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// DialFunc is a function to connect to the address on the named network.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Resolver overrides the addresses to connect like the --resolve option of curl.
+// It maps "host:port" to the address to connect instead.
+// Since only the destination of the connection is changed, the host is still used as the Host header and the server name of TLS.
+type Resolver map[string]string
+
+// NewResolver returns a resolver from the overrides which map "host:port" to "address" or "address:port".
+// If the address doesn't have a port, the port of the host is used.
+func NewResolver(overrides map[string]string) (Resolver, error) {
+	hosts := make([]string, 0, len(overrides))
+	for h := range overrides {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+	r := make(Resolver, len(overrides))
+	for _, h := range hosts {
+		host, port, err := net.SplitHostPort(h)
+		if err != nil || host == "" || port == "" {
+			return nil, fmt.Errorf("invalid host %q: must be host:port", h)
+		}
+		addr := overrides[h]
+		if addr == "" {
+			return nil, fmt.Errorf("address of %q is empty", h)
+		}
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(strings.Trim(addr, "[]"), port)
+		}
+		r[strings.ToLower(h)] = addr
+	}
+	return r, nil
+}
+
+// Resolve returns the address to connect instead of addr ("host:port").
+func (r Resolver) Resolve(addr string) (string, bool) {
+	if len(r) == 0 {
+		return "", false
+	}
+	a, ok := r[strings.ToLower(addr)]
+	return a, ok
+}
+
+// String returns the overrides as "host:port=address" joined with commas in the order of the hosts.
+// It can be used as a key to identify the resolver.
+func (r Resolver) String() string {
+	overrides := make([]string, 0, len(r))
+	for h, a := range r {
+		overrides = append(overrides, h+"="+a)
+	}
+	sort.Strings(overrides)
+	return strings.Join(overrides, ",")
+}
+
+// DialContext returns a dial function which connects to the overridden address instead of the original one.
+// The other addresses are connected by dial as they are.
+func (r Resolver) DialContext(dial DialFunc) DialFunc {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if a, ok := r.Resolve(addr); ok {
+			addr = a
+		}
+		return dial(ctx, network, addr)
+	}
+}
@@ -0,0 +1,103 @@
+package netutil
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewResolver(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		tests := map[string]struct {
+			overrides map[string]string
+			expect    Resolver
+		}{
+			"empty": {
+				expect: Resolver{},
+			},
+			"address with port": {
+				overrides: map[string]string{"api.example.com:443": "127.0.0.1:8443"},
+				expect:    Resolver{"api.example.com:443": "127.0.0.1:8443"},
+			},
+			"address without port": {
+				overrides: map[string]string{"API.example.com:443": "127.0.0.1"},
+				expect:    Resolver{"api.example.com:443": "127.0.0.1:443"},
+			},
+			"IPv6": {
+				overrides: map[string]string{
+					"a.example.com:80": "::1",
+					"b.example.com:80": "[::1]",
+					"[::2]:80":         "[::1]:8080",
+				},
+				expect: Resolver{
+					"a.example.com:80": "[::1]:80",
+					"b.example.com:80": "[::1]:80",
+					"[::2]:80":         "[::1]:8080",
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				r, err := NewResolver(test.overrides)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if diff := cmp.Diff(test.expect, r); diff != "" {
+					t.Errorf("differs (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+	t.Run("ng", func(t *testing.T) {
+		tests := map[string]struct {
+			overrides map[string]string
+			expect    string
+		}{
+			"no port": {
+				overrides: map[string]string{"api.example.com": "127.0.0.1"},
+				expect:    `invalid host "api.example.com": must be host:port`,
+			},
+			"no host": {
+				overrides: map[string]string{":443": "127.0.0.1"},
+				expect:    `invalid host ":443": must be host:port`,
+			},
+			"empty address": {
+				overrides: map[string]string{"api.example.com:443": ""},
+				expect:    `address of "api.example.com:443" is empty`,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := NewResolver(test.overrides)
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if got := err.Error(); got != test.expect {
+					t.Errorf("expect %q but got %q", test.expect, got)
+				}
+			})
+		}
+	})
+}
+
+func TestResolver_DialContext(t *testing.T) {
+	r := Resolver{"api.example.com:443": "127.0.0.1:8443"}
+	var dialed []string
+	dial := r.DialContext(func(_ context.Context, _, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		return nil, nil //nolint:nilnil
+	})
+	for _, addr := range []string{"api.example.com:443", "API.EXAMPLE.COM:443", "api.example.com:80", "other.example.com:443"} {
+		if _, err := dial(context.Background(), "tcp", addr); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if diff := cmp.Diff([]string{"127.0.0.1:8443", "127.0.0.1:8443", "api.example.com:80", "other.example.com:443"}, dialed); diff != "" {
+		t.Errorf("differs (-want +got):\n%s", diff)
+	}
+	if _, ok := Resolver(nil).Resolve("api.example.com:443"); ok {
+		t.Error("nil resolver resolved the address")
+	}
+}
@@ -8,9 +8,16 @@ import (
 
 	query "github.com/zoncoen/query-go"
 	yamlextractor "github.com/zoncoen/query-go/extractor/yaml"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/dynamicpb"
+
+	grpcproto "github.com/scenarigo/scenarigo/protocol/grpc/proto"
 )
 
+const anyFullName protoreflect.FullName = "google.protobuf.Any"
+
 var (
 	m    sync.RWMutex
 	opts = []query.Option{}
@@ -33,15 +40,30 @@ func Options() []query.Option {
 	)
 }
 
+// TypeResolvable is the interface implemented by the messages which have the resolver
+// to resolve the types of google.protobuf.Any values in them (e.g., the messages of the grpc protocol).
+type TypeResolvable interface {
+	proto.Message
+	TypeResolver() protoregistry.MessageTypeResolver
+}
+
 func dynamicpbExtractFunc() func(query.ExtractFunc) query.ExtractFunc {
 	return func(f query.ExtractFunc) query.ExtractFunc {
 		return func(in reflect.Value) (reflect.Value, bool) {
 			v := in
 			if v.IsValid() && v.CanInterface() {
-				if msg, ok := v.Interface().(*dynamicpb.Message); ok {
-					return f(reflect.ValueOf(&keyExtractor{
-						v: msg,
-					}))
+				switch msg := v.Interface().(type) {
+				case *anyMessage, *keyExtractor:
+					// already wrapped
+				case TypeResolvable:
+					// unwrap to get the concrete message type
+					if w, ok := wrapMessage(msg.ProtoReflect().Interface(), msg.TypeResolver()); ok {
+						return f(reflect.ValueOf(w))
+					}
+				case proto.Message:
+					if w, ok := wrapMessage(msg, nil); ok {
+						return f(reflect.ValueOf(w))
+					}
 				}
 			}
 			return f(in)
@@ -49,8 +71,41 @@ func dynamicpbExtractFunc() func(query.ExtractFunc) query.ExtractFunc {
 	}
 }
 
+// wrapMessage wraps msg to extract the fields by the key.
+// It returns false if msg can be extracted by the default extract functions.
+func wrapMessage(msg proto.Message, resolver protoregistry.MessageTypeResolver) (any, bool) {
+	if msg.ProtoReflect().Descriptor().FullName() == anyFullName {
+		return newAnyMessage(msg, resolver), true
+	}
+	if m, ok := msg.(*dynamicpb.Message); ok {
+		return &keyExtractor{
+			Message:  m,
+			resolver: resolver,
+		}, true
+	}
+	return nil, false
+}
+
+// keyExtractor is a dynamic message that can extract the fields by the key.
+// The resolver is used to resolve the types of google.protobuf.Any values in the message and its nested messages.
 type keyExtractor struct {
-	v *dynamicpb.Message
+	*dynamicpb.Message
+	resolver protoregistry.MessageTypeResolver
+}
+
+func (e *keyExtractor) get(f protoreflect.FieldDescriptor) any {
+	v := e.Get(f).Interface()
+	if msg, ok := v.(protoreflect.Message); ok && !f.IsList() && !f.IsMap() {
+		resolver := e.resolver
+		if resolver == nil {
+			// resolve the type of the value from the descriptors which the message depends on
+			resolver = grpcproto.NewTypeResolver(e.Descriptor().ParentFile())
+		}
+		if w, ok := wrapMessage(msg.Interface(), resolver); ok {
+			return w
+		}
+	}
+	return v
 }
 
 // ExtractByKey implements the query.KeyExtractorContext interface.
@@ -59,7 +114,7 @@ func (e *keyExtractor) ExtractByKey(ctx context.Context, key string) (interface{
 	if ci {
 		key = strings.ToLower(key)
 	}
-	fields := e.v.Descriptor().Fields()
+	fields := e.Descriptor().Fields()
 	for i := range fields.Len() {
 		f := fields.Get(i)
 		{
@@ -68,7 +123,7 @@ func (e *keyExtractor) ExtractByKey(ctx context.Context, key string) (interface{
 				name = strings.ToLower(name)
 			}
 			if name == key {
-				return e.v.Get(f).Interface(), true
+				return e.get(f), true
 			}
 		}
 		{
@@ -77,7 +132,7 @@ func (e *keyExtractor) ExtractByKey(ctx context.Context, key string) (interface{
 				name = strings.ToLower(name)
 			}
 			if name == key {
-				return e.v.Get(f).Interface(), true
+				return e.get(f), true
 			}
 		}
 		if f.HasJSONName() {
@@ -86,13 +141,60 @@ func (e *keyExtractor) ExtractByKey(ctx context.Context, key string) (interface{
 				name = strings.ToLower(name)
 			}
 			if name == key {
-				return e.v.Get(f).Interface(), true
+				return e.get(f), true
 			}
 		}
 	}
 	return nil, false
 }
 
+// anyMessage is a google.protobuf.Any message that can extract the fields of the packed message.
+// The type URL can be extracted by the "@type" key like the JSON representation.
+type anyMessage struct {
+	proto.Message
+	resolver protoregistry.MessageTypeResolver
+}
+
+func newAnyMessage(msg proto.Message, resolver protoregistry.MessageTypeResolver) *anyMessage {
+	if resolver == nil {
+		resolver = protoregistry.GlobalTypes
+	}
+	return &anyMessage{
+		Message:  msg,
+		resolver: resolver,
+	}
+}
+
+// ExtractByKey implements the query.KeyExtractorContext interface.
+func (m *anyMessage) ExtractByKey(ctx context.Context, key string) (interface{}, bool) {
+	fields := m.ProtoReflect().Descriptor().Fields()
+	typeURL := m.ProtoReflect().Get(fields.ByName("type_url")).String()
+	if key == "@type" {
+		return typeURL, true
+	}
+	mt, err := m.resolver.FindMessageByURL(typeURL)
+	if err != nil {
+		return nil, false
+	}
+	v := mt.New().Interface()
+	if err := proto.Unmarshal(m.ProtoReflect().Get(fields.ByName("value")).Bytes(), v); err != nil {
+		return nil, false
+	}
+	var opts []query.Option
+	if query.IsCaseInsensitive(ctx) {
+		opts = append(opts, query.CaseInsensitive())
+	}
+	var target any = v
+	if w, ok := wrapMessage(v, m.resolver); ok {
+		target = w
+	}
+	got, err := New(opts...).Key(key).Extract(target)
+	if err != nil {
+		return nil, false
+	}
+	return got, true
+}
+
 func AppendOptions(customOpts ...query.Option) {
 	m.Lock()
 	defer m.Unlock()
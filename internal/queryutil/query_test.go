@@ -7,8 +7,11 @@ import (
 
 	"github.com/scenarigo/scenarigo/protocol/grpc/proto"
 	"github.com/zoncoen/query-go"
+	protobuf "google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestKeyExtractor_ExtractByKey(t *testing.T) {
@@ -90,3 +93,74 @@ func TestKeyExtractor_ExtractByKey(t *testing.T) {
 		}
 	})
 }
+
+func TestAnyMessage_ExtractByKey(t *testing.T) {
+	comp := proto.NewCompiler([]string{"../../protocol/grpc/proto/testdata"})
+	fds, err := comp.Compile(context.Background(), []string{"wkt.proto"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := fds.Files()[0]
+	detail := dynamicpb.NewMessage(file.Messages().ByName("Detail"))
+	detail.Set(detail.Descriptor().Fields().ByName("name"), protoreflect.ValueOf("foo"))
+	b, err := protobuf.Marshal(detail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := dynamicpb.NewMessage(file.Messages().ByName("Message"))
+	anyField := msg.Descriptor().Fields().ByName("any")
+	anyMsg := msg.NewField(anyField).Message()
+	anyMsg.Set(anyMsg.Descriptor().Fields().ByName("type_url"), protoreflect.ValueOf("type.googleapis.com/scenarigo.testdata.wkt.Detail"))
+	anyMsg.Set(anyMsg.Descriptor().Fields().ByName("value"), protoreflect.ValueOf(b))
+	msg.Set(anyField, protoreflect.ValueOfMessage(anyMsg))
+
+	generated, err := anypb.New(wrapperspb.String("bar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		v      any
+		query  *query.Query
+		expect any
+	}{
+		"type URL": {
+			v:      msg,
+			query:  New().Key("any").Key("@type"),
+			expect: "type.googleapis.com/scenarigo.testdata.wkt.Detail",
+		},
+		"field of the packed message": {
+			v:      msg,
+			query:  New().Key("any").Key("name"),
+			expect: "foo",
+		},
+		"field of the packed message (case insensitive)": {
+			v:      msg,
+			query:  New(query.CaseInsensitive()).Key("any").Key("NAME"),
+			expect: "foo",
+		},
+		"generated message": {
+			v:      generated,
+			query:  New().Key("value"),
+			expect: "bar",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v, err := test.query.Extract(test.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, expect := v, test.expect; got != expect {
+				t.Errorf("expect %v but got %v", expect, got)
+			}
+		})
+	}
+
+	t.Run("unknown type", func(t *testing.T) {
+		anyMsg.Set(anyMsg.Descriptor().Fields().ByName("type_url"), protoreflect.ValueOf("type.googleapis.com/scenarigo.testdata.NotFound"))
+		if _, err := New().Key("any").Key("name").Extract(msg); err == nil {
+			t.Fatal("no error")
+		}
+	})
+}
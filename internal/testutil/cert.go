@@ -0,0 +1,89 @@
+package testutil
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// GenerateCert generates a CA certificate and a certificate signed by it for localhost.
+// The generated certificate can be used as both the server and client certificate.
+// It returns the file paths of the CA certificate, the certificate, and the private key.
+func GenerateCert(t *testing.T) (string, string, string) {
+	t.Helper()
+	tmp := t.TempDir()
+	now := time.Now()
+	validityPeriod := time.Hour
+
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             now,
+		NotAfter:              now.Add(validityPeriod),
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	caPub, caPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate private key: %s", err)
+	}
+	caBytes, err := x509.CreateCertificate(rand.Reader, ca, ca, caPub, caPriv)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %s", err)
+	}
+	caPEM, err := os.Create(filepath.Join(tmp, "ca.crt"))
+	if err != nil {
+		t.Fatalf("failed to create ca.crt: %s", err)
+	}
+	defer caPEM.Close()
+	if err := pem.Encode(caPEM, &pem.Block{Type: "CERTIFICATE", Bytes: caBytes}); err != nil {
+		t.Fatalf("failed to encode PEM: %s", err)
+	}
+
+	cert := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             now,
+		NotAfter:              now.Add(validityPeriod),
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1"), net.IPv6loopback},
+	}
+	certPub, certPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate private key: %s", err)
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, cert, ca, certPub, caPriv)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %s", err)
+	}
+	certPEM, err := os.Create(filepath.Join(tmp, "server.crt"))
+	if err != nil {
+		t.Fatalf("failed to create server.crt: %s", err)
+	}
+	if err := pem.Encode(certPEM, &pem.Block{Type: "CERTIFICATE", Bytes: certBytes}); err != nil {
+		t.Fatalf("failed to encode PEM: %s", err)
+	}
+	certKeyPEM, err := os.OpenFile(filepath.Join(tmp, "server.key"), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		t.Fatalf("failed to create server.key: %s", err)
+	}
+	privBytes, err := x509.MarshalPKCS8PrivateKey(certPriv)
+	if err != nil {
+		t.Fatalf("unable to marshal private key: %s", err)
+	}
+	if err := pem.Encode(certKeyPEM, &pem.Block{Type: "PRIVATE KEY", Bytes: privBytes}); err != nil {
+		t.Fatalf("failed to encode PEM: %s", err)
+	}
+
+	return caPEM.Name(), certPEM.Name(), certKeyPEM.Name()
+}
@@ -2,7 +2,10 @@ package testutil
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"net"
+	"os"
 	"testing"
 
 	"google.golang.org/grpc"
@@ -22,15 +25,34 @@ func StartTestGRPCServer(t *testing.T, srv testpb.TestServer, optFuncs ...TestGR
 
 	var serverOpts []grpc.ServerOption
 	if opts.tls != nil {
-		creds, err := credentials.NewServerTLSFromFile(opts.tls.certificate, opts.tls.key)
+		cert, err := tls.LoadX509KeyPair(opts.tls.certificate, opts.tls.key)
 		if err != nil {
 			t.Fatal(err)
 		}
-		serverOpts = append(serverOpts, grpc.Creds(creds))
+		cfg := &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+		if opts.tls.clientCA != "" {
+			b, err := os.ReadFile(opts.tls.clientCA)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cp := x509.NewCertPool()
+			if !cp.AppendCertsFromPEM(b) {
+				t.Fatal("failed to append client CA certificate")
+			}
+			cfg.ClientCAs = cp
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(cfg)))
 	}
 
 	s := grpc.NewServer(serverOpts...)
 	testpb.RegisterTestServer(s, srv)
+	if opts.streamServer != nil {
+		testpb.RegisterStreamTestServer(s, opts.streamServer)
+	}
 	if opts.reflection {
 		reflection.Register(s)
 	}
@@ -56,13 +78,15 @@ func StartTestGRPCServer(t *testing.T, srv testpb.TestServer, optFuncs ...TestGR
 type TestGRPCServerOption func(*grpcServerOpts)
 
 type grpcServerOpts struct {
-	reflection bool
-	tls        *tlsConfig
+	reflection   bool
+	tls          *tlsConfig
+	streamServer testpb.StreamTestServer
 }
 
 type tlsConfig struct {
 	certificate string
 	key         string
+	clientCA    string
 }
 
 func EnableReflection() TestGRPCServerOption {
@@ -80,6 +104,20 @@ func EnableTLS(cert, key string) TestGRPCServerOption {
 	}
 }
 
+func RequireClientCert(ca string) TestGRPCServerOption {
+	return func(opts *grpcServerOpts) {
+		if opts.tls != nil {
+			opts.tls.clientCA = ca
+		}
+	}
+}
+
+func WithStreamTestServer(srv testpb.StreamTestServer) TestGRPCServerOption {
+	return func(opts *grpcServerOpts) {
+		opts.streamServer = srv
+	}
+}
+
 type testServer func(context.Context, *testpb.EchoRequest) (*testpb.EchoResponse, error)
 
 func (f testServer) Echo(ctx context.Context, req *testpb.EchoRequest) (*testpb.EchoResponse, error) {
@@ -16,7 +16,7 @@ var (
 	elapsedTimePattern = regexp.MustCompile(`elapsed time(:)? .+`)
 	ipv4AddrPattern    = regexp.MustCompile(`127.0.0.1:\d+`)
 	ipv6AddrPattern    = regexp.MustCompile(`\[::\]:\d+`)
-	userAgentPattern   = regexp.MustCompile(fmt.Sprintf(`- scenarigo/%s`, version.String()))
+	userAgentPattern   = regexp.MustCompile(fmt.Sprintf(`(- |User-Agent: )scenarigo/%s`, version.String()))
 	dateHeaderPattern  = regexp.MustCompile(`Date:\n\s*- (.+)`)
 )
 
@@ -49,7 +49,7 @@ func ReplaceAddr(s string) string {
 
 // ReplaceUserAgent replaces User-Agent header on result output.
 func ReplaceUserAgent(s string) string {
-	return userAgentPattern.ReplaceAllString(s, "- scenarigo/v1.0.0")
+	return userAgentPattern.ReplaceAllString(s, "${1}scenarigo/v1.0.0")
 }
 
 // ReplaceDateHeader replaces Date header on result output.
@@ -20,6 +20,9 @@ func TestReplaceOutput(t *testing.T) {
         - scenarigo/%s
         Date:
         - Tue, 10 Nov 2009 23:00:00 GMT
+    curl:
+      curl -X GET 'http://[::]:35233/echo' \
+        -H 'User-Agent: scenarigo/%s'
     elapsed time: 0.123456 sec
        6 |     method: GET
        7 |     url: "http://{{env.TEST_HTTP_ADDR}}/echo"
@@ -30,7 +33,7 @@ func TestReplaceOutput(t *testing.T) {
 FAIL
 FAIL    test.yaml      1.234s
 FAIL
-`, version.String())
+`, version.String(), version.String())
 	expect := `
 === RUN   test.yaml
 --- FAIL: test.yaml (0.00s)
@@ -42,6 +45,9 @@ FAIL
         - scenarigo/v1.0.0
         Date:
         - Mon, 01 Jan 0001 00:00:00 GMT
+    curl:
+      curl -X GET 'http://[::]:12345/echo' \
+        -H 'User-Agent: scenarigo/v1.0.0'
     elapsed time: 0.000000 sec
        6 |     method: GET
        7 |     url: "http://{{env.TEST_HTTP_ADDR}}/echo"
@@ -0,0 +1,68 @@
+// Package tlsutil provides utilities to inspect TLS connections.
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"time"
+)
+
+// ConnectionState represents the state of a TLS connection to use in assertions.
+type ConnectionState struct {
+	// Version is the negotiated TLS version (e.g., "TLS 1.3").
+	Version string `yaml:"version"`
+	// CipherSuite is the negotiated cipher suite (e.g., "TLS_AES_128_GCM_SHA256").
+	CipherSuite string `yaml:"cipherSuite"`
+	// ALPN is the negotiated application protocol (e.g., "h2").
+	ALPN       string `yaml:"alpn"`
+	ServerName string `yaml:"serverName"`
+	// Certificates is the certificate chain sent by the peer. The first element is the leaf certificate.
+	Certificates []*Certificate `yaml:"certificates"`
+}
+
+// Certificate represents an X.509 certificate.
+type Certificate struct {
+	Subject     string    `yaml:"subject"`
+	Issuer      string    `yaml:"issuer"`
+	DNSNames    []string  `yaml:"dnsNames"`
+	IPAddresses []string  `yaml:"ipAddresses"`
+	NotBefore   time.Time `yaml:"notBefore"`
+	NotAfter    time.Time `yaml:"notAfter"`
+	// ExpiresIn is the remaining validity period at the time of the connection.
+	// It is negative if the certificate has already expired.
+	ExpiresIn time.Duration `yaml:"expiresIn"`
+}
+
+// NewConnectionState returns the state of the TLS connection. It returns nil if state is nil.
+func NewConnectionState(state *tls.ConnectionState, now time.Time) *ConnectionState {
+	if state == nil {
+		return nil
+	}
+	certs := make([]*Certificate, len(state.PeerCertificates))
+	for i, c := range state.PeerCertificates {
+		certs[i] = newCertificate(c, now)
+	}
+	return &ConnectionState{
+		Version:      tls.VersionName(state.Version),
+		CipherSuite:  tls.CipherSuiteName(state.CipherSuite),
+		ALPN:         state.NegotiatedProtocol,
+		ServerName:   state.ServerName,
+		Certificates: certs,
+	}
+}
+
+func newCertificate(c *x509.Certificate, now time.Time) *Certificate {
+	ips := make([]string, len(c.IPAddresses))
+	for i, ip := range c.IPAddresses {
+		ips[i] = ip.String()
+	}
+	return &Certificate{
+		Subject:     c.Subject.String(),
+		Issuer:      c.Issuer.String(),
+		DNSNames:    c.DNSNames,
+		IPAddresses: ips,
+		NotBefore:   c.NotBefore,
+		NotAfter:    c.NotAfter,
+		ExpiresIn:   c.NotAfter.Sub(now),
+	}
+}
@@ -0,0 +1,79 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewConnectionState(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		state  *tls.ConnectionState
+		expect *ConnectionState
+	}{
+		"nil": {},
+		"with certificates": {
+			state: &tls.ConnectionState{
+				Version:            tls.VersionTLS13,
+				CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+				NegotiatedProtocol: "h2",
+				ServerName:         "example.com",
+				PeerCertificates: []*x509.Certificate{
+					{
+						Subject:     pkix.Name{CommonName: "example.com"},
+						Issuer:      pkix.Name{CommonName: "Example CA", Organization: []string{"Example"}},
+						DNSNames:    []string{"example.com", "www.example.com"},
+						IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+						NotBefore:   now.Add(-24 * time.Hour),
+						NotAfter:    now.Add(30 * 24 * time.Hour),
+					},
+					{
+						Subject:   pkix.Name{CommonName: "Example CA", Organization: []string{"Example"}},
+						Issuer:    pkix.Name{CommonName: "Example CA", Organization: []string{"Example"}},
+						NotBefore: now.Add(-48 * time.Hour),
+						NotAfter:  now.Add(-time.Hour),
+					},
+				},
+			},
+			expect: &ConnectionState{
+				Version:     "TLS 1.3",
+				CipherSuite: "TLS_AES_128_GCM_SHA256",
+				ALPN:        "h2",
+				ServerName:  "example.com",
+				Certificates: []*Certificate{
+					{
+						Subject:     "CN=example.com",
+						Issuer:      "CN=Example CA,O=Example",
+						DNSNames:    []string{"example.com", "www.example.com"},
+						IPAddresses: []string{"127.0.0.1"},
+						NotBefore:   now.Add(-24 * time.Hour),
+						NotAfter:    now.Add(30 * 24 * time.Hour),
+						ExpiresIn:   30 * 24 * time.Hour,
+					},
+					{
+						Subject:     "CN=Example CA,O=Example",
+						Issuer:      "CN=Example CA,O=Example",
+						IPAddresses: []string{},
+						NotBefore:   now.Add(-48 * time.Hour),
+						NotAfter:    now.Add(-time.Hour),
+						ExpiresIn:   -time.Hour,
+					},
+				},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := NewConnectionState(test.state, now)
+			if diff := cmp.Diff(test.expect, got); diff != "" {
+				t.Errorf("differs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
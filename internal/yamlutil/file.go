@@ -0,0 +1,29 @@
+package yamlutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// ReadFile reads the file and returns its contents.
+// JSON and YAML files (.json, .yaml, and .yml) are decoded into a value, and the other files are returned as a string.
+func ReadFile(path string, opts ...yaml.DecodeOption) (interface{}, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml":
+		var v interface{}
+		if err := yaml.UnmarshalWithOptions(b, &v, opts...); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		return v, nil
+	default:
+		return string(b), nil
+	}
+}
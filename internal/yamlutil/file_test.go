@@ -0,0 +1,92 @@
+package yamlutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"body.json":    `{"name": "{{vars.name}}", "ids": [1, 2]}`,
+		"body.yaml":    "name: '{{vars.name}}'\nids:\n- 1\n- 2\n",
+		"body.txt":     "name: {{vars.name}}\n",
+		"invalid.json": `{"name":`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write file: %s", err)
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			path   string
+			opts   []yaml.DecodeOption
+			expect interface{}
+		}{
+			"JSON": {
+				path: "body.json",
+				expect: map[string]interface{}{
+					"name": "{{vars.name}}",
+					"ids":  []interface{}{uint64(1), uint64(2)},
+				},
+			},
+			"YAML with options": {
+				path: "body.yaml",
+				opts: []yaml.DecodeOption{yaml.UseOrderedMap()},
+				expect: yaml.MapSlice{
+					{Key: "name", Value: "{{vars.name}}"},
+					{Key: "ids", Value: []interface{}{uint64(1), uint64(2)}},
+				},
+			},
+			"text": {
+				path:   "body.txt",
+				expect: "name: {{vars.name}}\n",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				got, err := ReadFile(filepath.Join(dir, test.path), test.opts...)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if diff := cmp.Diff(test.expect, got); diff != "" {
+					t.Errorf("differs (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			path   string
+			expect string
+		}{
+			"not found": {
+				path:   "not-found.json",
+				expect: "no such file or directory",
+			},
+			"invalid JSON": {
+				path:   "invalid.json",
+				expect: "failed to decode",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := ReadFile(filepath.Join(dir, test.path))
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if got := err.Error(); !strings.Contains(got, test.expect) {
+					t.Errorf("%q doesn't contain %q", got, test.expect)
+				}
+			})
+		}
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
+	"github.com/scenarigo/scenarigo/internal/filepathutil"
 	"github.com/scenarigo/scenarigo/logger"
 	"github.com/scenarigo/scenarigo/mock/protocol"
 	"github.com/scenarigo/scenarigo/protocol/grpc/proto"
@@ -54,12 +55,23 @@ func (_ *GRPC) NewServer(iter *protocol.MockIterator, l logger.Logger, config in
 	}
 	if cfg != nil {
 		srv.config = *cfg
-		comp := proto.NewCompiler(cfg.Proto.Imports)
-		fds, err := comp.Compile(context.Background(), cfg.Proto.Files)
-		if err != nil {
-			return nil, fmt.Errorf("failed to compile proto: %w", err)
+		var resolvers proto.ServiceDescriptorResolvers
+		if len(cfg.Proto.DescriptorSets) > 0 {
+			set, err := proto.LoadDescriptorSets(cfg.Proto.DescriptorSets)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load descriptor sets: %w", err)
+			}
+			resolvers = append(resolvers, set)
 		}
-		srv.resolver = fds
+		if len(cfg.Proto.Files) > 0 || len(resolvers) == 0 {
+			comp := proto.NewCompiler(cfg.Proto.Imports)
+			fds, err := comp.Compile(context.Background(), cfg.Proto.Files)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compile proto: %w", err)
+			}
+			resolvers = append(resolvers, fds)
+		}
+		srv.resolver = resolvers
 	}
 	return srv, nil
 }
@@ -70,10 +82,20 @@ type ServerConfig struct {
 	Proto ProtoConfig `yaml:"proto,omitempty"`
 }
 
+// ResolvePaths implements protocol.PathResolver interface.
+func (c *ServerConfig) ResolvePaths(base string) {
+	for i, p := range c.Proto.DescriptorSets {
+		c.Proto.DescriptorSets[i] = filepathutil.From(base, p)
+	}
+}
+
 // ProtoConfig represents a proto configuration.
 type ProtoConfig struct {
 	Imports []string `yaml:"imports,omitempty"`
 	Files   []string `yaml:"files,omitempty"`
+	// DescriptorSets is a list of FileDescriptorSet files.
+	// The relative paths are resolved against the directory of the mock config file.
+	DescriptorSets []string `yaml:"descriptorSets,omitempty"`
 }
 
 type server struct {
@@ -2,16 +2,21 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/scenarigo/scenarigo/logger"
@@ -23,6 +28,26 @@ func init() {
 	Register()
 }
 
+func TestServerConfig_ResolvePaths(t *testing.T) {
+	cfg := &ServerConfig{
+		Proto: ProtoConfig{
+			Imports:        []string{"proto"},
+			Files:          []string{"test.proto"},
+			DescriptorSets: []string{"test.protoset", "/path/to/test.protoset"},
+		},
+	}
+	cfg.ResolvePaths("/path/to/mock")
+	if diff := cmp.Diff(&ServerConfig{
+		Proto: ProtoConfig{
+			Imports:        []string{"proto"},
+			Files:          []string{"test.proto"},
+			DescriptorSets: []string{"/path/to/mock/test.protoset", "/path/to/test.protoset"},
+		},
+	}, cfg); diff != "" {
+		t.Errorf("differs (-want +got):\n%s", diff)
+	}
+}
+
 func TestGRPC_Server(t *testing.T) {
 	cfg := `
 proto:
@@ -62,6 +87,15 @@ proto:
 			config:   cfg,
 			f:        sendEchoRequest(nil, "1", "hello"),
 		},
+		"descriptor sets": {
+			filename: "testdata/grpc.yaml",
+			config: `
+proto:
+  descriptorSets:
+  - ./testdata/test.protoset
+`,
+			f: sendEchoRequest(nil, "1", "hello"),
+		},
 		"int status code": {
 			filename: "testdata/int-status-code.yaml",
 			config:   cfg,
@@ -97,6 +131,236 @@ proto:
 			config:   cfg,
 			f:        sendEchoRequest(status.New(codes.InvalidArgument, ".expect.metadata.content-type: request assertion failed"), "", ""),
 		},
+		"header and trailer": {
+			filename: "testdata/header-trailer.yaml",
+			config:   cfg,
+			f: func(t *testing.T, addr string) {
+				t.Helper()
+				client := testpb.NewTestClient(newClientConn(t, addr))
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				var header, trailer metadata.MD
+				resp, err := client.Echo(ctx, &testpb.EchoRequest{
+					MessageId:   "1",
+					MessageBody: "hello",
+				}, grpc.Header(&header), grpc.Trailer(&trailer))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, expect := resp.GetMessageBody(), "hello"; got != expect {
+					t.Errorf("expect %s but got %s", expect, got)
+				}
+				if got, expect := header.Get("x-mock"), []string{"unary"}; !cmp.Equal(expect, got) {
+					t.Errorf("expect header %v but got %v", expect, got)
+				}
+				if got, expect := trailer.Get("count"), []string{"1"}; !cmp.Equal(expect, got) {
+					t.Errorf("expect trailer %v but got %v", expect, got)
+				}
+			},
+		},
+		"server streaming": {
+			filename: "testdata/server-streaming.yaml",
+			config:   cfg,
+			f: func(t *testing.T, addr string) {
+				t.Helper()
+				client := testpb.NewStreamTestClient(newClientConn(t, addr))
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				stream, err := client.ServerStreamingEcho(ctx, &testpb.EchoRequest{
+					MessageId:   "1",
+					MessageBody: "hello world",
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				var got []string
+				for {
+					resp, err := stream.Recv()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+					got = append(got, resp.GetMessageId()+":"+resp.GetMessageBody())
+				}
+				if diff := cmp.Diff([]string{"1:hello", "2:world"}, got); diff != "" {
+					t.Errorf("differs (-want +got):\n%s", diff)
+				}
+				header, err := stream.Header()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, expect := header.Get("x-mock"), []string{"server-streaming"}; !cmp.Equal(expect, got) {
+					t.Errorf("expect header %v but got %v", expect, got)
+				}
+				if got, expect := stream.Trailer().Get("count"), []string{"2"}; !cmp.Equal(expect, got) {
+					t.Errorf("expect trailer %v but got %v", expect, got)
+				}
+			},
+		},
+		"server streaming with error status": {
+			filename: "testdata/server-streaming-error.yaml",
+			config:   cfg,
+			f: func(t *testing.T, addr string) {
+				t.Helper()
+				client := testpb.NewStreamTestClient(newClientConn(t, addr))
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				stream, err := client.ServerStreamingEcho(ctx, &testpb.EchoRequest{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp, err := stream.Recv()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, expect := resp.GetMessageBody(), "hello"; got != expect {
+					t.Errorf("expect %s but got %s", expect, got)
+				}
+				_, err = stream.Recv()
+				if got, expect := status.Code(err), codes.Unavailable; got != expect {
+					t.Fatalf("expect status code %s but got %s", expect, got)
+				}
+				if got, expect := status.Convert(err).Message(), "stream closed"; got != expect {
+					t.Errorf("expect status message %s but got %s", expect, got)
+				}
+			},
+		},
+		"client streaming": {
+			filename: "testdata/client-streaming.yaml",
+			config:   cfg,
+			f: func(t *testing.T, addr string) {
+				t.Helper()
+				client := testpb.NewStreamTestClient(newClientConn(t, addr))
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				stream, err := client.ClientStreamingEcho(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i, body := range []string{"hello", "world"} {
+					if err := stream.Send(&testpb.EchoRequest{
+						MessageId:   strconv.Itoa(i + 1),
+						MessageBody: body,
+					}); err != nil {
+						t.Fatal(err)
+					}
+				}
+				resp, err := stream.CloseAndRecv()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, expect := resp.GetMessageBody(), "hello world"; got != expect {
+					t.Errorf("expect %s but got %s", expect, got)
+				}
+			},
+		},
+		"bidi streaming": {
+			filename: "testdata/bidi-streaming.yaml",
+			config:   cfg,
+			f: func(t *testing.T, addr string) {
+				t.Helper()
+				client := testpb.NewStreamTestClient(newClientConn(t, addr))
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				stream, err := client.BidiStreamingEcho(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i, body := range []string{"hello", "world"} {
+					if err := stream.Send(&testpb.EchoRequest{
+						MessageId:   strconv.Itoa(i + 1),
+						MessageBody: body,
+					}); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := stream.CloseSend(); err != nil {
+					t.Fatal(err)
+				}
+				var got []string
+				for {
+					resp, err := stream.Recv()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+					got = append(got, resp.GetMessageBody())
+				}
+				if diff := cmp.Diff([]string{"hello", "world"}, got); diff != "" {
+					t.Errorf("differs (-want +got):\n%s", diff)
+				}
+				if got, expect := stream.Trailer().Get("count"), []string{"2"}; !cmp.Equal(expect, got) {
+					t.Errorf("expect trailer %v but got %v", expect, got)
+				}
+			},
+		},
+		"bidi streaming ping-pong": {
+			filename: "testdata/bidi-streaming.yaml",
+			config:   cfg,
+			f: func(t *testing.T, addr string) {
+				t.Helper()
+				client := testpb.NewStreamTestClient(newClientConn(t, addr))
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				stream, err := client.BidiStreamingEcho(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// each response must be received before sending the next message
+				for i, body := range []string{"hello", "world"} {
+					if err := stream.Send(&testpb.EchoRequest{
+						MessageId:   strconv.Itoa(i + 1),
+						MessageBody: body,
+					}); err != nil {
+						t.Fatal(err)
+					}
+					resp, err := stream.Recv()
+					if err != nil {
+						t.Fatal(err)
+					}
+					if got, expect := resp.GetMessageBody(), body; got != expect {
+						t.Errorf("expect %s but got %s", expect, got)
+					}
+				}
+				if err := stream.CloseSend(); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+					t.Fatalf("expect EOF but got %v", err)
+				}
+			},
+		},
+		"invalid expect messages": {
+			filename: "testdata/client-streaming.yaml",
+			config:   cfg,
+			f: func(t *testing.T, addr string) {
+				t.Helper()
+				client := testpb.NewStreamTestClient(newClientConn(t, addr))
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				stream, err := client.ClientStreamingEcho(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := stream.Send(&testpb.EchoRequest{
+					MessageId:   "1",
+					MessageBody: "bye",
+				}); err != nil {
+					t.Fatal(err)
+				}
+				_, err = stream.CloseAndRecv()
+				if got, expect := status.Code(err), codes.InvalidArgument; got != expect {
+					t.Fatalf("expect status code %s but got %s", expect, got)
+				}
+				if got, expect := status.Convert(err).Message(), ".expect.messages[0].messageBody: request assertion failed"; !strings.Contains(got, expect) {
+					t.Errorf("expect status message %q but got %q", expect, got)
+				}
+			},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -158,6 +422,18 @@ proto:
 	}
 }
 
+func newClientConn(t *testing.T, addr string) *grpc.ClientConn {
+	t.Helper()
+	c, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("failed to connect server: %s", err)
+	}
+	t.Cleanup(func() {
+		c.Close()
+	})
+	return c
+}
+
 func sendEchoRequest(st *status.Status, id, msg string) func(t *testing.T, addr string) {
 	return func(t *testing.T, addr string) {
 		t.Helper()
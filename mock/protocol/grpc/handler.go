@@ -3,8 +3,11 @@ package grpc
 import (
 	gocontext "context"
 	"fmt"
+	"io"
 	"math"
+	"reflect"
 	"strconv"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -20,8 +23,11 @@ import (
 	"github.com/scenarigo/scenarigo/context"
 	"github.com/scenarigo/scenarigo/errors"
 	"github.com/scenarigo/scenarigo/internal/assertutil"
+	"github.com/scenarigo/scenarigo/internal/reflectutil"
 	"github.com/scenarigo/scenarigo/internal/yamlutil"
+	"github.com/scenarigo/scenarigo/mock/protocol"
 	grpcprotocol "github.com/scenarigo/scenarigo/protocol/grpc"
+	"github.com/scenarigo/scenarigo/schema"
 )
 
 func (s *server) convertToServicDesc(sd protoreflect.ServiceDescriptor) *grpc.ServiceDesc {
@@ -31,44 +37,28 @@ func (s *server) convertToServicDesc(sd protoreflect.ServiceDescriptor) *grpc.Se
 	}
 	for i := range sd.Methods().Len() {
 		m := sd.Methods().Get(i)
-		// TODO: streaming RPC
-		// if m.IsStreamingServer() || m.IsStreamingClient() {
-		// 	desc.Streams = append(desc.Streams, grpc.StreamDesc{
-		// 		StreamName:    string(m.Name()),
-		// 		ServerStreams: m.IsStreamingServer(),
-		// 		ClientStreams: m.IsStreamingClient(),
-		// 		Handler: func(srv any, stream grpc.ServerStream) error {
-		// 			return nil
-		// 		},
-		// 	})
-		// } else {
-		desc.Methods = append(desc.Methods, grpc.MethodDesc{
-			MethodName: string(m.Name()),
-			Handler:    s.unaryHandler(sd.FullName(), m),
-		})
-		// }
+		if m.IsStreamingServer() || m.IsStreamingClient() {
+			desc.Streams = append(desc.Streams, grpc.StreamDesc{
+				StreamName:    string(m.Name()),
+				ServerStreams: m.IsStreamingServer(),
+				ClientStreams: m.IsStreamingClient(),
+				Handler:       s.streamHandler(sd.FullName(), m),
+			})
+		} else {
+			desc.Methods = append(desc.Methods, grpc.MethodDesc{
+				MethodName: string(m.Name()),
+				Handler:    s.unaryHandler(sd.FullName(), m),
+			})
+		}
 	}
 	return desc
 }
 
 func (s *server) unaryHandler(svcName protoreflect.FullName, method protoreflect.MethodDescriptor) func(srv any, ctx gocontext.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
 	return func(srv any, ctx gocontext.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
-		mock, err := s.iter.Next()
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to get mock: %s", err)
-		}
-
-		if mock.Protocol != "grpc" {
-			return nil, status.Error(codes.Internal, errors.WithPath(fmt.Errorf("received gRPC request but the mock protocol is %q", mock.Protocol), "protocol").Error())
-		}
-
-		var e expect
-		if err := mock.Expect.Unmarshal(&e); err != nil {
-			return nil, status.Error(codes.Internal, errors.WrapPath(err, "expect", "failed to unmarshal").Error())
-		}
-		assertion, err := e.build(context.New(nil))
+		mock, assertion, err := s.nextMock()
 		if err != nil {
-			return nil, status.Error(codes.Internal, errors.WrapPath(err, "expect", "failed to build assretion").Error())
+			return nil, err
 		}
 
 		var md metadata.MD
@@ -88,20 +78,27 @@ func (s *server) unaryHandler(svcName protoreflect.FullName, method protoreflect
 			return nil, status.Error(codes.InvalidArgument, errors.WrapPath(err, "expect", "request assertion failed").Error())
 		}
 
-		var resp Response
-		if err := mock.Response.Unmarshal(&resp); err != nil {
-			return nil, status.Error(codes.Internal, errors.WrapPath(err, "response", "failed to unmarshal response").Error())
+		resp, err := unmarshalResponse(mock)
+		if err != nil {
+			return nil, err
 		}
-		sctx := context.New(nil)
-		v, err := sctx.ExecuteTemplate(resp)
+		header, trailer, err := resp.extractMetadata()
 		if err != nil {
-			return nil, status.Error(codes.Internal, errors.WrapPath(err, "response", "failed to execute template of response").Error())
+			return nil, status.Error(codes.Internal, errors.WithPath(err, "response").Error())
 		}
-		resp, ok := v.(Response)
-		if !ok {
-			return nil, status.Error(codes.Internal, errors.WithPath(fmt.Errorf("failed to execute template of response: unexpected type %T", v), "response").Error())
+		if len(header) > 0 {
+			if err := grpc.SetHeader(ctx, header); err != nil {
+				return nil, status.Error(codes.Internal, errors.WrapPath(err, "response.header", "failed to set header").Error())
+			}
+		}
+		if len(trailer) > 0 {
+			if err := grpc.SetTrailer(ctx, trailer); err != nil {
+				return nil, status.Error(codes.Internal, errors.WrapPath(err, "response.trailer", "failed to set trailer").Error())
+			}
+		}
+		if resp.Messages != nil {
+			return nil, status.Error(codes.Internal, errors.ErrorPath("response.messages", "messages can be used only for server-streaming or bidirectional-streaming RPC").Error())
 		}
-
 		var msg proto.Message = dynamicpb.NewMessage(method.Output())
 		msg, serr, err := resp.extract(msg)
 		if err != nil {
@@ -111,11 +108,190 @@ func (s *server) unaryHandler(svcName protoreflect.FullName, method protoreflect
 	}
 }
 
+// streamHandler returns a handler for streaming RPCs.
+// For client-streaming RPCs, it receives all request messages until the client half-closes the stream, and then sends the response messages in order.
+// For bidirectional-streaming RPCs, it sends the i-th response message after receiving the i-th request message
+// so that ping-pong exchanges can be tested, and the rest of the response messages are sent after the client half-closes the stream.
+func (s *server) streamHandler(svcName protoreflect.FullName, method protoreflect.MethodDescriptor) grpc.StreamHandler {
+	return func(srv any, stream grpc.ServerStream) error {
+		mock, assertion, err := s.nextMock()
+		if err != nil {
+			return err
+		}
+
+		ctx := stream.Context()
+		var md metadata.MD
+		if got, ok := metadata.FromIncomingContext(ctx); ok {
+			md = got
+		}
+		req := &request{
+			service:  string(svcName),
+			method:   string(method.Name()),
+			metadata: yamlutil.NewMDMarshaler(md),
+		}
+
+		resp, err := unmarshalResponse(mock)
+		if err != nil {
+			return err
+		}
+		header, trailer, err := resp.extractMetadata()
+		if err != nil {
+			return status.Error(codes.Internal, errors.WithPath(err, "response").Error())
+		}
+		msgs, delays, st, err := resp.extractMessages(method)
+		if err != nil {
+			return status.Error(codes.Internal, errors.WithPath(err, "response").Error())
+		}
+		sender := &messageSender{
+			stream: stream,
+			msgs:   msgs,
+			delays: delays,
+		}
+
+		bidi := method.IsStreamingClient() && method.IsStreamingServer()
+		var onRecv func() error
+		if bidi {
+			if len(header) > 0 {
+				if err := stream.SendHeader(header); err != nil {
+					return err
+				}
+			}
+			onRecv = sender.sendNext
+		}
+		if err := req.receive(stream, method, onRecv); err != nil {
+			return err
+		}
+		if err := assertion.Assert(req); err != nil {
+			return status.Error(codes.InvalidArgument, errors.WrapPath(err, "expect", "request assertion failed").Error())
+		}
+
+		if len(trailer) > 0 {
+			stream.SetTrailer(trailer)
+		}
+		if len(header) > 0 && !bidi {
+			if err := stream.SendHeader(header); err != nil {
+				return err
+			}
+		}
+		if err := sender.sendAll(); err != nil {
+			return err
+		}
+		return st.Err()
+	}
+}
+
+// messageSender sends the response messages of a stream in order.
+type messageSender struct {
+	stream grpc.ServerStream
+	msgs   []proto.Message
+	delays []time.Duration
+	sent   int
+}
+
+// sendNext sends the next message if it remains.
+func (s *messageSender) sendNext() error {
+	if s.sent >= len(s.msgs) {
+		return nil
+	}
+	ctx := s.stream.Context()
+	if d := s.delays[s.sent]; d > 0 {
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return status.FromContextError(ctx.Err()).Err()
+		case <-t.C:
+		}
+	}
+	if err := s.stream.SendMsg(s.msgs[s.sent]); err != nil {
+		return err
+	}
+	s.sent++
+	return nil
+}
+
+// sendAll sends the remaining messages.
+func (s *messageSender) sendAll() error {
+	for s.sent < len(s.msgs) {
+		if err := s.sendNext(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *server) nextMock() (*protocol.Mock, assert.Assertion, error) {
+	mock, err := s.iter.Next()
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to get mock: %s", err)
+	}
+
+	if mock.Protocol != "grpc" {
+		return nil, nil, status.Error(codes.Internal, errors.WithPath(fmt.Errorf("received gRPC request but the mock protocol is %q", mock.Protocol), "protocol").Error())
+	}
+
+	var e expect
+	if err := mock.Expect.Unmarshal(&e); err != nil {
+		return nil, nil, status.Error(codes.Internal, errors.WrapPath(err, "expect", "failed to unmarshal").Error())
+	}
+	assertion, err := e.build(context.New(nil))
+	if err != nil {
+		return nil, nil, status.Error(codes.Internal, errors.WrapPath(err, "expect", "failed to build assretion").Error())
+	}
+	return mock, assertion, nil
+}
+
+func unmarshalResponse(mock *protocol.Mock) (*Response, error) {
+	var resp Response
+	if err := mock.Response.Unmarshal(&resp); err != nil {
+		return nil, status.Error(codes.Internal, errors.WrapPath(err, "response", "failed to unmarshal response").Error())
+	}
+	sctx := context.New(nil)
+	v, err := sctx.ExecuteTemplate(resp)
+	if err != nil {
+		return nil, status.Error(codes.Internal, errors.WrapPath(err, "response", "failed to execute template of response").Error())
+	}
+	resp, ok := v.(Response)
+	if !ok {
+		return nil, status.Error(codes.Internal, errors.WithPath(fmt.Errorf("failed to execute template of response: unexpected type %T", v), "response").Error())
+	}
+	return &resp, nil
+}
+
 type request struct {
 	service  string
 	method   string
 	metadata *yamlutil.MDMarshaler
 	message  any
+	messages []any
+}
+
+// receive receives the request messages. If onRecv is not nil, it is called each time a message is received.
+func (r *request) receive(stream grpc.ServerStream, method protoreflect.MethodDescriptor, onRecv func() error) error {
+	if !method.IsStreamingClient() {
+		msg := dynamicpb.NewMessage(method.Input())
+		if err := stream.RecvMsg(msg); err != nil {
+			return status.Error(codes.Internal, errors.WrapPath(err, "expect.message", "failed to receive message").Error())
+		}
+		r.message = msg
+		return nil
+	}
+	for i := 0; ; i++ {
+		msg := dynamicpb.NewMessage(method.Input())
+		if err := stream.RecvMsg(msg); err != nil {
+			// io.EOF means that the client has closed the sending side of the stream.
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return status.Error(codes.Internal, errors.WrapPath(err, fmt.Sprintf("expect.messages[%d]", i), "failed to receive message").Error())
+		}
+		r.messages = append(r.messages, msg)
+		if onRecv != nil {
+			if err := onRecv(); err != nil {
+				return err
+			}
+		}
+	}
 }
 
 type expect struct {
@@ -123,6 +299,7 @@ type expect struct {
 	Method   *string       `yaml:"method"`
 	Metadata yaml.MapSlice `yaml:"metadata"`
 	Message  any           `yaml:"message"`
+	Messages any           `yaml:"messages"`
 }
 
 func (e *expect) build(ctx *context.Context) (assert.Assertion, error) {
@@ -153,6 +330,10 @@ func (e *expect) build(ctx *context.Context) (assert.Assertion, error) {
 	if err != nil {
 		return nil, errors.WrapPathf(err, "message", "invalid expect response message")
 	}
+	msgsAssertion, err := assert.Build(ctx.RequestContext(), e.Messages, assert.FromTemplate(ctx))
+	if err != nil {
+		return nil, errors.WrapPathf(err, "messages", "invalid expect request messages")
+	}
 
 	return assert.AssertionFunc(func(v interface{}) error {
 		req, ok := v.(*request)
@@ -171,39 +352,139 @@ func (e *expect) build(ctx *context.Context) (assert.Assertion, error) {
 		if err := assertion.Assert(req.message); err != nil {
 			return errors.WithPath(err, "message")
 		}
+		if err := msgsAssertion.Assert(req.messages); err != nil {
+			return errors.WithPath(err, "messages")
+		}
 		return nil
 	}), nil
 }
 
 // Response represents an gRPC response.
-type Response grpcprotocol.Expect
+type Response struct {
+	Code    string      `yaml:"code,omitempty"`
+	Message interface{} `yaml:"message,omitempty"`
+	// Messages is the list of the messages for server-streaming RPCs.
+	Messages []*ResponseMessage        `yaml:"messages,omitempty"`
+	Status   grpcprotocol.ExpectStatus `yaml:"status,omitempty"`
+	Header   yaml.MapSlice             `yaml:"header,omitempty"`
+	Trailer  yaml.MapSlice             `yaml:"trailer,omitempty"`
+
+	// for backward compatibility
+	Body interface{} `yaml:"body,omitempty"`
+}
+
+// ResponseMessage represents a message of a server-streaming response.
+type ResponseMessage struct {
+	Message interface{}      `yaml:"message,omitempty"`
+	Delay   *schema.Duration `yaml:"delay,omitempty"`
+}
 
 func (resp *Response) extract(msg proto.Message) (proto.Message, *status.Status, error) {
+	st, err := resp.status()
+	if err != nil {
+		return nil, nil, err
+	}
+	if st.Code() != codes.OK {
+		return nil, st, nil
+	}
+
+	if resp.Message != nil {
+		if err := grpcprotocol.ConvertToProto(resp.Message, msg); err != nil {
+			return nil, nil, errors.WrapPath(err, "message", "invalid message")
+		}
+	}
+
+	return msg, nil, nil
+}
+
+func (resp *Response) status() (*status.Status, error) {
+	code := codes.OK
 	if resp.Status.Code != "" {
-		var code codes.Code
 		c, err := strToCode(resp.Status.Code)
 		if err != nil {
-			return nil, nil, errors.WithPath(err, "status.code")
+			return nil, errors.WithPath(err, "status.code")
 		}
 		code = c
+	}
 
-		smsg := code.String()
-		if resp.Status.Message != "" {
-			smsg = resp.Status.Message
-		}
+	smsg := code.String()
+	if resp.Status.Message != "" {
+		smsg = resp.Status.Message
+	}
+	return status.New(code, smsg), nil
+}
 
-		if code != codes.OK {
-			return nil, status.New(code, smsg), nil
+// extractMessages returns the messages to send in order, their delays, and the final status of the stream.
+func (resp *Response) extractMessages(method protoreflect.MethodDescriptor) ([]proto.Message, []time.Duration, *status.Status, error) {
+	st, err := resp.status()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if !method.IsStreamingServer() {
+		if resp.Messages != nil {
+			return nil, nil, nil, errors.ErrorPath("messages", "messages can be used only for server-streaming or bidirectional-streaming RPC")
+		}
+		if st.Code() != codes.OK {
+			return nil, nil, st, nil
 		}
+		msg, _, err := resp.extract(dynamicpb.NewMessage(method.Output()))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return []proto.Message{msg}, []time.Duration{0}, st, nil
 	}
 
 	if resp.Message != nil {
-		if err := grpcprotocol.ConvertToProto(resp.Message, msg); err != nil {
-			return nil, nil, errors.WrapPath(err, "message", "invalid message")
+		return nil, nil, nil, errors.ErrorPath("message", "message can't be used for server-streaming RPC, use messages instead")
+	}
+	msgs := make([]proto.Message, len(resp.Messages))
+	delays := make([]time.Duration, len(resp.Messages))
+	for i, m := range resp.Messages {
+		msg := dynamicpb.NewMessage(method.Output())
+		if m == nil {
+			msgs[i] = msg
+			continue
+		}
+		if m.Message != nil {
+			if err := grpcprotocol.ConvertToProto(m.Message, msg); err != nil {
+				return nil, nil, nil, errors.WrapPath(err, fmt.Sprintf("messages[%d].message", i), "invalid message")
+			}
+		}
+		msgs[i] = msg
+		if m.Delay != nil {
+			delays[i] = time.Duration(*m.Delay)
 		}
 	}
+	return msgs, delays, st, nil
+}
 
-	return msg, nil, nil
+func (resp *Response) extractMetadata() (metadata.MD, metadata.MD, error) {
+	header, err := convertToMD(resp.Header)
+	if err != nil {
+		return nil, nil, errors.WithPath(err, "header")
+	}
+	trailer, err := convertToMD(resp.Trailer)
+	if err != nil {
+		return nil, nil, errors.WithPath(err, "trailer")
+	}
+	return header, trailer, nil
+}
+
+func convertToMD(s yaml.MapSlice) (metadata.MD, error) {
+	md := make(metadata.MD, len(s))
+	for _, item := range s {
+		k, err := reflectutil.ConvertString(reflect.ValueOf(item.Key))
+		if err != nil {
+			return nil, fmt.Errorf("metadata key must be a string: %+v is invalid: %w", item.Key, err)
+		}
+		vs, err := reflectutil.ConvertStrings(reflect.ValueOf(item.Value))
+		if err != nil {
+			return nil, errors.WithPath(fmt.Errorf("invalid metadata value: %w", err), k)
+		}
+		md.Append(k, vs...)
+	}
+	return md, nil
 }
 
 func strToCode(s string) (codes.Code, error) {
@@ -165,6 +165,58 @@ func TestUnaryHandler_failure(t *testing.T) {
 			decode:  func(_ any) error { return nil },
 			expect:  ".response.message: invalid message",
 		},
+		"messages for unary RPC": {
+			mocks: []protocol.Mock{
+				{
+					Protocol: "grpc",
+					Expect:   yamlutil.RawMessage(""),
+					Response: yamlutil.RawMessage("messages:\n- message:\n    messageId: '1'"),
+				},
+			},
+			svcName: svcName,
+			method:  md,
+			decode:  func(_ any) error { return nil },
+			expect:  ".response.messages: messages can be used only for server-streaming or bidirectional-streaming RPC",
+		},
+		"invalid response header": {
+			mocks: []protocol.Mock{
+				{
+					Protocol: "grpc",
+					Expect:   yamlutil.RawMessage(""),
+					Response: yamlutil.RawMessage("header:\n  foo:\n    bar: baz"),
+				},
+			},
+			svcName: svcName,
+			method:  md,
+			decode:  func(_ any) error { return nil },
+			expect:  ".response.header.foo: invalid metadata value",
+		},
+		"invalid response trailer": {
+			mocks: []protocol.Mock{
+				{
+					Protocol: "grpc",
+					Expect:   yamlutil.RawMessage(""),
+					Response: yamlutil.RawMessage("trailer:\n  foo:\n    bar: baz"),
+				},
+			},
+			svcName: svcName,
+			method:  md,
+			decode:  func(_ any) error { return nil },
+			expect:  ".response.trailer.foo: invalid metadata value",
+		},
+		"invalid response messages": {
+			mocks: []protocol.Mock{
+				{
+					Protocol: "grpc",
+					Expect:   yamlutil.RawMessage(""),
+					Response: yamlutil.RawMessage("messages: aaa"),
+				},
+			},
+			svcName: svcName,
+			method:  md,
+			decode:  func(_ any) error { return nil },
+			expect:  ".response: failed to unmarshal response",
+		},
 	}
 
 	for name, test := range tests {
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/goccy/go-yaml"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
 	"github.com/scenarigo/scenarigo/logger"
 	"github.com/scenarigo/scenarigo/mock/protocol"
@@ -58,6 +60,10 @@ func (_ *HTTP) NewServer(iter *protocol.MockIterator, l logger.Logger, config in
 // ServerConfig represents a server configuration.
 type ServerConfig struct {
 	Port int `yaml:"port,omitempty"`
+	// Socket is the path of the Unix domain socket to listen on instead of the TCP port.
+	Socket string `yaml:"socket,omitempty"`
+	// H2C enables HTTP/2 over cleartext in addition to HTTP/1.1.
+	H2C bool `yaml:"h2c,omitempty"`
 }
 
 type server struct {
@@ -83,19 +89,30 @@ func (s *server) setup() (func() error, error) {
 	if s.srv != nil {
 		return nil, errors.New("server already started")
 	}
-	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", s.config.Port))
+	network, address := "tcp", fmt.Sprintf(":%d", s.config.Port)
+	if s.config.Socket != "" {
+		if s.config.Port != 0 {
+			return nil, errors.New("port and socket can't be specified at the same time")
+		}
+		network, address = "unix", s.config.Socket
+	}
+	ln, err := net.Listen(network, address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to listen: %w", err)
 	}
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == healthPath {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		s.handler.ServeHTTP(w, r)
+	})
+	if s.config.H2C {
+		handler = h2c.NewHandler(handler, &http2.Server{})
+	}
 	s.srv = &http.Server{
-		Addr: ln.Addr().String(),
-		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if r.URL.Path == healthPath {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
-			s.handler.ServeHTTP(w, r)
-		}),
+		Addr:              ln.Addr().String(),
+		Handler:           handler,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 	return func() error {
@@ -126,6 +143,15 @@ func (s *server) wait(ctx context.Context) error {
 	client := &http.Client{
 		Timeout: time.Second,
 	}
+	if s.config.Socket != "" {
+		client.Transport = &http.Transport{
+			DisableKeepAlives: true,
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", s.config.Socket)
+			},
+		}
+	}
 	for {
 		if err := ctx.Err(); err != nil {
 			return err
@@ -134,7 +160,11 @@ func (s *server) wait(ctx context.Context) error {
 		srv := s.srv
 		s.m.Unlock()
 		if srv != nil {
-			req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s%s", srv.Addr, healthPath), nil)
+			host := srv.Addr
+			if s.config.Socket != "" {
+				host = "localhost"
+			}
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s%s", host, healthPath), nil)
 			if err != nil {
 				return err
 			}
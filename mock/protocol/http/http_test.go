@@ -2,15 +2,19 @@ package http
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/goccy/go-yaml"
 	"github.com/scenarigo/scenarigo/logger"
 	"github.com/scenarigo/scenarigo/mock/protocol"
+	"golang.org/x/net/http2"
 )
 
 func init() {
@@ -18,6 +22,7 @@ func init() {
 }
 
 func TestHTTP_Server(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "mock.sock")
 	tests := map[string]struct {
 		filename string
 		config   string
@@ -52,6 +57,59 @@ func TestHTTP_Server(t *testing.T) {
 				}
 			},
 		},
+		"unix domain socket": {
+			filename: "testdata/http.yaml",
+			config:   fmt.Sprintf("socket: %s", socket),
+			f: func(t *testing.T, addr string) {
+				t.Helper()
+				if addr != socket {
+					t.Errorf("expect address %q but got %q", socket, addr)
+				}
+				client := &http.Client{
+					Transport: &http.Transport{
+						DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+							var d net.Dialer
+							return d.DialContext(ctx, "unix", socket)
+						},
+					},
+				}
+				resp, err := client.Get("http://localhost")
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer resp.Body.Close()
+				if got, expect := resp.StatusCode, http.StatusOK; got != expect {
+					t.Errorf("expect %d but got %d", expect, got)
+				}
+			},
+		},
+		"h2c": {
+			filename: "testdata/http.yaml",
+			config:   "h2c: true",
+			f: func(t *testing.T, addr string) {
+				t.Helper()
+				client := &http.Client{
+					Transport: &http2.Transport{
+						AllowHTTP: true,
+						DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+							var d net.Dialer
+							return d.DialContext(ctx, network, addr)
+						},
+					},
+				}
+				resp, err := client.Get(fmt.Sprintf("http://%s", addr))
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer resp.Body.Close()
+				if got, expect := resp.StatusCode, http.StatusOK; got != expect {
+					t.Errorf("expect %d but got %d", expect, got)
+				}
+				if got, expect := resp.ProtoMajor, 2; got != expect {
+					t.Errorf("expect HTTP/%d but got %s", expect, resp.Proto)
+				}
+			},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -126,6 +184,15 @@ func TestHTTP_Server_Start_Failure(t *testing.T) {
 			},
 			expect: "server already started",
 		},
+		"both port and socket": {
+			server: &server{
+				config: ServerConfig{
+					Port:   8888,
+					Socket: "mock.sock",
+				},
+			},
+			expect: "port and socket can't be specified at the same time",
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
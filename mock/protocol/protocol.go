@@ -57,6 +57,12 @@ type Protocol interface {
 	NewServer(iter *MockIterator, l logger.Logger, config interface{}) (Server, error)
 }
 
+// PathResolver is the interface implemented by the protocol configurations which have file paths.
+type PathResolver interface {
+	// ResolvePaths resolves the relative paths in the configuration against the base directory.
+	ResolvePaths(base string)
+}
+
 // Server represents a mock server.
 type Server interface {
 	Start(context.Context) error
@@ -38,6 +38,9 @@ func NewServer(config *ServerConfig, l logger.Logger) (*Server, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to unmarshal %s config: %w", name, err)
 		}
+		if r, ok := cfg.(protocol.PathResolver); ok {
+			r.ResolvePaths(config.BaseDir)
+		}
 		s, err := p.NewServer(iter, l, cfg)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create %s server: %w", name, err)
@@ -62,6 +65,9 @@ type Server struct {
 type ServerConfig struct {
 	Mocks     []protocol.Mock                `yaml:"mocks,omitempty"`
 	Protocols map[string]yamlutil.RawMessage `yaml:"protocols,omitempty"`
+	// BaseDir is the directory to resolve the relative paths in the protocol configurations.
+	// It is usually the directory of the mock config file.
+	BaseDir string `yaml:"-"`
 }
 
 func (s *Server) Start(ctx context.Context) error {
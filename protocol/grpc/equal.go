@@ -1,8 +1,11 @@
 package grpc
 
 import (
+	"bytes"
 	"reflect"
 
+	"github.com/goccy/go-yaml"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 
@@ -13,6 +16,7 @@ import (
 func init() {
 	assert.RegisterCustomEqualer(assert.EqualerFunc(equalEnum))
 	assert.RegisterCustomEqualer(assert.EqualerFunc(equalMessage))
+	assert.RegisterCustomEqualer(assert.EqualerFunc(equalWellKnownType))
 }
 
 var protoMessage = reflect.TypeOf((*proto.Message)(nil)).Elem()
@@ -60,3 +64,53 @@ func equalMessage(expected interface{}, got interface{}) (bool, error) {
 	}
 	return false, nil
 }
+
+// wellKnownTypes are the types which have the special JSON representation.
+// ref. https://protobuf.dev/programming-guides/json/
+var wellKnownTypes = map[protoreflect.FullName]struct{}{
+	"google.protobuf.Timestamp":   {},
+	"google.protobuf.Duration":    {},
+	"google.protobuf.FieldMask":   {},
+	"google.protobuf.Struct":      {},
+	"google.protobuf.Value":       {},
+	"google.protobuf.ListValue":   {},
+	"google.protobuf.BoolValue":   {},
+	"google.protobuf.BytesValue":  {},
+	"google.protobuf.DoubleValue": {},
+	"google.protobuf.FloatValue":  {},
+	"google.protobuf.Int32Value":  {},
+	"google.protobuf.Int64Value":  {},
+	"google.protobuf.StringValue": {},
+	"google.protobuf.UInt32Value": {},
+	"google.protobuf.UInt64Value": {},
+}
+
+// equalWellKnownType compares the well-known type message with the expected value written in the JSON representation (e.g., "1.5s" for google.protobuf.Duration).
+func equalWellKnownType(expected interface{}, got interface{}) (bool, error) {
+	if _, ok := expected.(proto.Message); ok {
+		return false, nil
+	}
+	g, ok, _ := reflectutil.ConvertInterface(protoMessage, got)
+	if ok {
+		got = g
+	}
+	gm, ok := got.(proto.Message)
+	if !ok || !gm.ProtoReflect().IsValid() {
+		return false, nil
+	}
+	if _, ok := wellKnownTypes[gm.ProtoReflect().Descriptor().FullName()]; !ok {
+		return false, nil
+	}
+	var buf bytes.Buffer
+	if err := yaml.NewEncoder(&buf, yaml.JSON()).Encode(expected); err != nil {
+		return false, nil //nolint:nilerr
+	}
+	em := gm.ProtoReflect().New().Interface()
+	if err := protojson.Unmarshal(buf.Bytes(), em); err != nil {
+		return false, nil //nolint:nilerr
+	}
+	if proto.Equal(em, gm) {
+		return true, nil
+	}
+	return false, nil
+}
@@ -4,8 +4,14 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/goccy/go-yaml"
 	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"github.com/scenarigo/scenarigo/testdata/gen/pb/test"
 )
@@ -122,6 +128,80 @@ func TestEqualMessage(t *testing.T) {
 	}
 }
 
+func TestEqualWellKnownType(t *testing.T) {
+	tests := map[string]struct {
+		expected interface{}
+		got      interface{}
+		ok       bool
+	}{
+		"timestamp": {
+			expected: "2024-01-02T03:04:05.5Z",
+			got:      timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 5e8, time.UTC)),
+			ok:       true,
+		},
+		"timestamp with offset": {
+			expected: "2024-01-02T12:04:05+09:00",
+			got:      timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)),
+			ok:       true,
+		},
+		"timestamp not equals": {
+			expected: "2024-01-02T03:04:05Z",
+			got:      timestamppb.New(time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC)),
+		},
+		"duration": {
+			expected: "1.5s",
+			got:      durationpb.New(1500 * time.Millisecond),
+			ok:       true,
+		},
+		"string value": {
+			expected: "hello",
+			got:      wrapperspb.String("hello"),
+			ok:       true,
+		},
+		"int64 value": {
+			expected: uint64(1),
+			got:      wrapperspb.Int64(1),
+			ok:       true,
+		},
+		"struct": {
+			expected: yaml.MapSlice{
+				yaml.MapItem{Key: "foo", Value: "bar"},
+			},
+			got: func() *structpb.Struct {
+				s, err := structpb.NewStruct(map[string]any{"foo": "bar"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return s
+			}(),
+			ok: true,
+		},
+		"invalid representation": {
+			expected: "1.5",
+			got:      durationpb.New(1500 * time.Millisecond),
+		},
+		"not well-known type": {
+			expected: "xxx",
+			got:      &test.EchoResponse{},
+		},
+		"nil": {
+			expected: "1.5s",
+			got:      (*durationpb.Duration)(nil),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ok, err := equalWellKnownType(test.expected, test.got)
+			if ok != test.ok {
+				t.Errorf("expect %t but got %t", test.ok, ok)
+			}
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
 func echoResponse(t *testing.T, id, body string) *test.EchoResponse {
 	t.Helper()
 
@@ -22,11 +22,14 @@ import (
 
 // Expect represents expected response values.
 type Expect struct {
-	Code    string        `yaml:"code,omitempty"`
-	Message interface{}   `yaml:"message,omitempty"`
-	Status  ExpectStatus  `yaml:"status,omitempty"`
-	Header  yaml.MapSlice `yaml:"header,omitempty"`
-	Trailer yaml.MapSlice `yaml:"trailer,omitempty"`
+	Code     string        `yaml:"code,omitempty"`
+	Message  interface{}   `yaml:"message,omitempty"`
+	Messages interface{}   `yaml:"messages,omitempty"`
+	Status   ExpectStatus  `yaml:"status,omitempty"`
+	Header   yaml.MapSlice `yaml:"header,omitempty"`
+	Trailer  yaml.MapSlice `yaml:"trailer,omitempty"`
+	// TLS is the expected state of the TLS connection (e.g., certificates[0].expiresIn: '{{$ > duration("336h")}}').
+	TLS interface{} `yaml:"tls,omitempty"`
 
 	// for backward compatibility
 	Body interface{} `yaml:"body,omitempty"`
@@ -82,6 +85,19 @@ func (e *Expect) Build(ctx *context.Context) (assert.Assertion, error) {
 		return nil, errors.WrapPathf(err, "message", "invalid expect response message")
 	}
 
+	msgsAssertion, err := assert.Build(ctx.RequestContext(), e.Messages, assert.FromTemplate(ctx))
+	if err != nil {
+		return nil, errors.WrapPathf(err, "messages", "invalid expect response messages")
+	}
+
+	tlsAssertion := assert.Nop()
+	if e.TLS != nil {
+		tlsAssertion, err = assert.Build(ctx.RequestContext(), e.TLS, assert.FromTemplate(ctx))
+		if err != nil {
+			return nil, errors.WrapPathf(err, "tls", "invalid expect tls")
+		}
+	}
+
 	return assert.AssertionFunc(func(v interface{}) error {
 		resp, ok := v.(*response)
 		if !ok {
@@ -105,6 +121,15 @@ func (e *Expect) Build(ctx *context.Context) (assert.Assertion, error) {
 		if err := msgAssertion.Assert(resp.Message); err != nil {
 			return errors.WithPath(err, "message")
 		}
+		if err := msgsAssertion.Assert(resp.Messages); err != nil {
+			return errors.WithPath(err, "messages")
+		}
+		if e.TLS != nil && resp.TLS == nil {
+			return errors.ErrorPath("tls", "not a TLS connection")
+		}
+		if err := tlsAssertion.Assert(resp.TLS); err != nil {
+			return errors.WithPath(err, "tls")
+		}
 		return nil
 	}), nil
 }
@@ -3,6 +3,7 @@ package grpc
 import (
 	"strconv"
 	"testing"
+	"time"
 
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
@@ -14,6 +15,7 @@ import (
 	"github.com/goccy/go-yaml"
 
 	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/internal/tlsutil"
 	"github.com/scenarigo/scenarigo/internal/yamlutil"
 	"github.com/scenarigo/scenarigo/testdata/gen/pb/test"
 )
@@ -28,7 +30,7 @@ func TestExpect_Build(t *testing.T) {
 			"default": {
 				expect: &Expect{},
 				v: &response{
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 			},
 			"code": {
@@ -70,12 +72,46 @@ func TestExpect_Build(t *testing.T) {
 					},
 				},
 				v: &response{
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{
 						MessageId:   "1",
 						MessageBody: "hello",
 					}},
 				},
 			},
+			"assert messages": {
+				expect: &Expect{
+					Code: "OK",
+					Messages: []interface{}{
+						yaml.MapSlice{
+							yaml.MapItem{
+								Key:   "messageId",
+								Value: "1",
+							},
+						},
+						yaml.MapSlice{
+							yaml.MapItem{
+								Key:   "messageBody",
+								Value: "{{vars.body}}",
+							},
+						},
+					},
+				},
+				vars: map[string]string{
+					"body": "world",
+				},
+				v: &response{
+					Messages: []*ProtoMessageYAMLMarshaler{
+						{Message: &test.EchoResponse{
+							MessageId:   "1",
+							MessageBody: "hello",
+						}},
+						{Message: &test.EchoResponse{
+							MessageId:   "2",
+							MessageBody: "world",
+						}},
+					},
+				},
+			},
 			"assert metadata.header": {
 				expect: &Expect{
 					Code: "OK",
@@ -92,7 +128,7 @@ func TestExpect_Build(t *testing.T) {
 							"application/grpc",
 						},
 					}),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 			},
 			"assert metadata.trailer": {
@@ -111,7 +147,37 @@ func TestExpect_Build(t *testing.T) {
 							"application/grpc",
 						},
 					}),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
+				},
+			},
+			"assert tls": {
+				expect: &Expect{
+					TLS: yaml.MapSlice{
+						yaml.MapItem{
+							Key:   "alpn",
+							Value: "h2",
+						},
+						yaml.MapItem{
+							Key: "certificates",
+							Value: []interface{}{
+								yaml.MapSlice{
+									yaml.MapItem{
+										Key:   "expiresIn",
+										Value: `{{$ > duration("336h")}}`,
+									},
+								},
+							},
+						},
+					},
+				},
+				v: &response{
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
+					TLS: &tlsutil.ConnectionState{
+						ALPN: "h2",
+						Certificates: []*tlsutil.Certificate{
+							{ExpiresIn: 30 * 24 * time.Hour},
+						},
+					},
 				},
 			},
 			"assert in case of error": {
@@ -179,7 +245,7 @@ func TestExpect_Build(t *testing.T) {
 					},
 				},
 				v: &response{
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{
 						MessageId:   "1",
 						MessageBody: "hello",
 					}},
@@ -243,7 +309,7 @@ func TestExpect_Build(t *testing.T) {
 							"v1.0.0",
 						},
 					}),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{
 						MessageBody: "hello",
 					}},
 				},
@@ -315,7 +381,7 @@ func TestExpect_Build(t *testing.T) {
 				expect: &Expect{},
 				v: &response{
 					Status:  createStatus(t, codes.InvalidArgument, "invalid argument"),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectAssertError: true,
 			},
@@ -325,7 +391,7 @@ func TestExpect_Build(t *testing.T) {
 				},
 				v: &response{
 					Status:  createStatus(t, codes.InvalidArgument, "invalid argument"),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectAssertError: true,
 			},
@@ -344,7 +410,7 @@ func TestExpect_Build(t *testing.T) {
 					},
 				},
 				v: &response{
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{
 						MessageId:   "1",
 						MessageBody: "hell",
 					}},
@@ -367,7 +433,7 @@ func TestExpect_Build(t *testing.T) {
 							"application/grpc",
 						},
 					}),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectAssertError: true,
 			},
@@ -387,7 +453,7 @@ func TestExpect_Build(t *testing.T) {
 							"application/grpc",
 						},
 					}),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectAssertError: true,
 			},
@@ -407,7 +473,7 @@ func TestExpect_Build(t *testing.T) {
 							"application/grpc",
 						},
 					}),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectAssertError: true,
 			},
@@ -427,7 +493,7 @@ func TestExpect_Build(t *testing.T) {
 							"application/grpc",
 						},
 					}),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectAssertError: true,
 			},
@@ -447,7 +513,7 @@ func TestExpect_Build(t *testing.T) {
 							"application/grpc",
 						},
 					}),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectAssertError: true,
 			},
@@ -459,7 +525,7 @@ func TestExpect_Build(t *testing.T) {
 				},
 				v: &response{
 					Status:  createStatus(t, codes.NotFound, "not found"),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectAssertError: true,
 			},
@@ -472,7 +538,7 @@ func TestExpect_Build(t *testing.T) {
 				},
 				v: &response{
 					Status:  createStatus(t, codes.NotFound, "not found"),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectAssertError: true,
 			},
@@ -504,7 +570,7 @@ func TestExpect_Build(t *testing.T) {
 							Detail: "debug",
 						},
 					),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectBuildError: true,
 			},
@@ -535,7 +601,7 @@ func TestExpect_Build(t *testing.T) {
 							Detail: "debug",
 						},
 					),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectAssertError: true,
 				expectError:       `.status.details[0]: expected "google.rpc.Invalid" but got "google.rpc.LocalizedMessage"`,
@@ -567,7 +633,7 @@ func TestExpect_Build(t *testing.T) {
 							Detail: "debug",
 						},
 					),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectBuildError: true,
 				expectError:      `.status.details[0].'google.rpc.LocalizedMessage': invalid expect status detail: failed to build assertion: failed to parse "{{locale": col 9: expected '}}', found 'EOF'`,
@@ -599,11 +665,74 @@ func TestExpect_Build(t *testing.T) {
 							Detail: "debug",
 						},
 					),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectAssertError: true,
 				expectError:       `.status.details[0].'google.rpc.LocalizedMessage': ".Loc" not found`,
 			},
+			"wrong messages": {
+				expect: &Expect{
+					Messages: []interface{}{
+						yaml.MapSlice{
+							yaml.MapItem{
+								Key:   "messageId",
+								Value: "1",
+							},
+						},
+						yaml.MapSlice{
+							yaml.MapItem{
+								Key:   "messageId",
+								Value: "3",
+							},
+						},
+					},
+				},
+				v: &response{
+					Messages: []*ProtoMessageYAMLMarshaler{
+						{Message: &test.EchoResponse{
+							MessageId: "1",
+						}},
+						{Message: &test.EchoResponse{
+							MessageId: "2",
+						}},
+					},
+				},
+				expectAssertError: true,
+				expectError:       `.messages[1].messageId: expected "3" but got "2"`,
+			},
+			"wrong tls": {
+				expect: &Expect{
+					TLS: yaml.MapSlice{
+						yaml.MapItem{
+							Key:   "version",
+							Value: "TLS 1.3",
+						},
+					},
+				},
+				v: &response{
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
+					TLS: &tlsutil.ConnectionState{
+						Version: "TLS 1.2",
+					},
+				},
+				expectAssertError: true,
+				expectError:       `.tls.version: expected "TLS 1.3" but got "TLS 1.2"`,
+			},
+			"not a TLS connection": {
+				expect: &Expect{
+					TLS: yaml.MapSlice{
+						yaml.MapItem{
+							Key:   "version",
+							Value: "TLS 1.3",
+						},
+					},
+				},
+				v: &response{
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
+				},
+				expectAssertError: true,
+				expectError:       `.tls: not a TLS connection`,
+			},
 			"wrong status details: value is wrong": {
 				expect: &Expect{
 					Status: ExpectStatus{
@@ -631,7 +760,7 @@ func TestExpect_Build(t *testing.T) {
 							Detail: "debug",
 						},
 					),
-					Message: &ProtoMessageYAMLMarshaler{&test.EchoResponse{}},
+					Message: &ProtoMessageYAMLMarshaler{Message: &test.EchoResponse{}},
 				},
 				expectAssertError: true,
 				expectError:       `.status.details[0].'google.rpc.LocalizedMessage'.Locale: expected "en-US" but got "ja-JP"`,
@@ -28,6 +28,9 @@ type GRPC struct {
 // Option represents a Option for gRPC.
 type Option struct {
 	Request *RequestOptions `yaml:"request,omitempty"`
+
+	// rootDir is the root directory of the configuration to resolve the relative paths in the options.
+	rootDir string
 }
 
 // Name implements protocol.Protocol interface.
@@ -37,9 +40,18 @@ func (p *GRPC) Name() string {
 
 // UnmarshalOption implements protocol.Protocol interface.
 func (p *GRPC) UnmarshalOption(b []byte) error {
+	return p.UnmarshalOptionWithRootDir(b, "")
+}
+
+// UnmarshalOptionWithRootDir implements protocol.RootDirOptionUnmarshaler interface.
+func (p *GRPC) UnmarshalOptionWithRootDir(b []byte, root string) error {
 	p.m.Lock()
 	defer p.m.Unlock()
-	return yaml.UnmarshalWithOptions(b, &p.option, yaml.Strict())
+	if err := yaml.UnmarshalWithOptions(b, &p.option, yaml.Strict()); err != nil {
+		return err
+	}
+	p.option.rootDir = root
+	return nil
 }
 
 func (p *GRPC) getOption() *Option {
@@ -90,6 +102,12 @@ func (p *GRPC) UnmarshalExpect(b []byte) (protocol.AssertionBuilder, error) {
 	return &e, nil
 }
 
+// Close implements protocol.Closer interface.
+// It closes the cached connections and clears the descriptors resolved via the reflection service.
+func (p *GRPC) Close() error {
+	return connPool.closeAll()
+}
+
 // QueryOptions implements the QueryOptionsProvider interface.
 func (p *GRPC) QueryOptions() []query.Option {
 	return []query.Option{
@@ -86,6 +86,12 @@ method: Ping`),
 body: test
 message: test`),
 			},
+			"invalid call timeout": {
+				bytes: []byte(`
+options:
+  call:
+    timeout: 1`),
+			},
 		}
 		for name, test := range tests {
 			t.Run(name, func(t *testing.T) {
@@ -0,0 +1,130 @@
+package proto
+
+import (
+	"fmt"
+	"os"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// LoadDescriptorSets loads the given binary FileDescriptorSet files (e.g., generated by "protoc --descriptor_set_out" or "buf build").
+// Dependencies that are not contained in the sets are resolved from the well-known types linked into the binary.
+func LoadDescriptorSets(files []string) (*DescriptorSet, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := map[string]struct{}{}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read descriptor set: %w", err)
+		}
+		var s descriptorpb.FileDescriptorSet
+		if err := proto.Unmarshal(b, &s); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal descriptor set %s: %w", f, err)
+		}
+		for _, fd := range s.GetFile() {
+			if _, ok := seen[fd.GetName()]; ok {
+				continue
+			}
+			seen[fd.GetName()] = struct{}{}
+			set.File = append(set.File, fd)
+		}
+	}
+	for _, fd := range set.GetFile() {
+		for _, dep := range fd.GetDependency() {
+			if _, ok := seen[dep]; ok {
+				continue
+			}
+			if d, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+				seen[dep] = struct{}{}
+				set.File = append(set.File, protodesc.ToFileDescriptorProto(d))
+			}
+		}
+	}
+	reg, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file descriptors: %w", err)
+	}
+	return &DescriptorSet{
+		files: reg,
+	}, nil
+}
+
+// DescriptorSet is a collection of file descriptors loaded from FileDescriptorSet files.
+type DescriptorSet struct {
+	files *protoregistry.Files
+}
+
+// ListServices lists all service names.
+func (s *DescriptorSet) ListServices() ([]protoreflect.FullName, error) {
+	names := []protoreflect.FullName{}
+	s.files.RangeFiles(func(f protoreflect.FileDescriptor) bool {
+		svcs := f.Services()
+		for i := range svcs.Len() {
+			names = append(names, svcs.Get(i).FullName())
+		}
+		return true
+	})
+	return names, nil
+}
+
+// ResolveService resolves a service descriptor by the given name.
+func (s *DescriptorSet) ResolveService(name protoreflect.FullName) (protoreflect.ServiceDescriptor, error) {
+	d, err := s.files.FindDescriptorByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("service %q not found", name)
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a service", name)
+	}
+	return sd, nil
+}
+
+// Files returns the underlying protobuf files.
+func (s *DescriptorSet) Files() *protoregistry.Files {
+	return s.files
+}
+
+// ServiceDescriptorResolvers is a list of resolvers that resolves service descriptors in order.
+type ServiceDescriptorResolvers []ServiceDescriptorResolver
+
+// ListServices lists all service names.
+func (rs ServiceDescriptorResolvers) ListServices() ([]protoreflect.FullName, error) {
+	names := []protoreflect.FullName{}
+	seen := map[protoreflect.FullName]struct{}{}
+	for _, r := range rs {
+		ns, err := r.ListServices()
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range ns {
+			if _, ok := seen[n]; ok {
+				continue
+			}
+			seen[n] = struct{}{}
+			names = append(names, n)
+		}
+	}
+	return names, nil
+}
+
+// ResolveService resolves a service descriptor by the given name.
+// It returns the result of the first resolver that finds the service.
+func (rs ServiceDescriptorResolvers) ResolveService(name protoreflect.FullName) (protoreflect.ServiceDescriptor, error) {
+	var lastErr error
+	for _, r := range rs {
+		sd, err := r.ResolveService(name)
+		if err == nil {
+			return sd, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("service %q not found", name)
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,137 @@
+package proto
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestLoadDescriptorSets(t *testing.T) {
+	tests := map[string]struct {
+		files    []string
+		services []protoreflect.FullName
+	}{
+		"single file": {
+			files: []string{
+				"./testdata/foo.protoset",
+			},
+			services: []protoreflect.FullName{
+				"scenarigo.testdata.foo.Foo",
+			},
+		},
+		"include imports": {
+			files: []string{
+				"./testdata/bar.protoset",
+			},
+			services: []protoreflect.FullName{
+				"scenarigo.testdata.bar.Bar",
+			},
+		},
+		"multiple files": {
+			files: []string{
+				"./testdata/foo.protoset",
+				"./testdata/bar.protoset",
+			},
+			services: []protoreflect.FullName{
+				"scenarigo.testdata.foo.Foo",
+				"scenarigo.testdata.bar.Bar",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			set, err := LoadDescriptorSets(test.files)
+			if err != nil {
+				t.Fatalf("failed to load: %s", err)
+			}
+
+			names, err := set.ListServices()
+			if err != nil {
+				t.Fatalf("failed to get services: %s", err)
+			}
+			if diff := cmp.Diff(test.services, names, cmpopts.SortSlices(func(a, b protoreflect.FullName) bool { return a < b })); diff != "" {
+				t.Fatalf("request differs (-want +got):\n%s", diff)
+			}
+
+			for _, name := range test.services {
+				sd, err := set.ResolveService(name)
+				if err != nil {
+					t.Fatalf("failed to get service: %s", err)
+				}
+				if got, expect := sd.FullName(), name; got != expect {
+					t.Errorf("expect %s but got %s", expect, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadDescriptorSets_failure(t *testing.T) {
+	tests := map[string]struct {
+		files  []string
+		expect string
+	}{
+		"not found": {
+			files: []string{
+				"./testdata/not-found.protoset",
+			},
+			expect: "failed to read descriptor set",
+		},
+		"invalid file": {
+			files: []string{
+				"./testdata/foo.proto",
+			},
+			expect: "failed to unmarshal descriptor set ./testdata/foo.proto",
+		},
+		"missing imports": {
+			files: []string{
+				"./testdata/bar-without-imports.protoset",
+			},
+			expect: "failed to create file descriptors",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadDescriptorSets(test.files)
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if !strings.Contains(err.Error(), test.expect) {
+				t.Errorf("expect error %q but got %q", test.expect, err)
+			}
+		})
+	}
+}
+
+func TestServiceDescriptorResolvers(t *testing.T) {
+	set, err := LoadDescriptorSets([]string{"./testdata/foo.protoset"})
+	if err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	fds, err := NewCompiler([]string{"./testdata"}).Compile(context.Background(), []string{"bar.proto"})
+	if err != nil {
+		t.Fatalf("failed to compile: %s", err)
+	}
+	rs := ServiceDescriptorResolvers{set, fds}
+
+	names, err := rs.ListServices()
+	if err != nil {
+		t.Fatalf("failed to get services: %s", err)
+	}
+	expect := []protoreflect.FullName{"scenarigo.testdata.foo.Foo", "scenarigo.testdata.bar.Bar"}
+	if diff := cmp.Diff(expect, names); diff != "" {
+		t.Fatalf("request differs (-want +got):\n%s", diff)
+	}
+	for _, name := range expect {
+		if _, err := rs.ResolveService(name); err != nil {
+			t.Fatalf("failed to get service: %s", err)
+		}
+	}
+	if _, err := rs.ResolveService("scenarigo.testdata.NotFound"); err == nil {
+		t.Fatal("no error")
+	}
+}
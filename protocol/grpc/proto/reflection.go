@@ -47,6 +47,11 @@ func (c *ReflectionClient) ResolveService(name protoreflect.FullName) (protorefl
 	return fd.UnwrapService(), nil
 }
 
+// Close closes the stream to the reflection service.
+func (c *ReflectionClient) Close() {
+	c.client.Reset()
+}
+
 // IsUnimplementedReflectionServiceError returns a boolean indicating whether its argument is known to report that the server doesn't implement reflection service.
 func IsUnimplementedReflectionServiceError(err error) bool {
 	if err == nil {
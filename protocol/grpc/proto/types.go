@@ -0,0 +1,71 @@
+package proto
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// TypeResolver resolves message and extension types (e.g., the type of google.protobuf.Any value) from the loaded descriptors.
+type TypeResolver struct {
+	types *dynamicpb.Types
+}
+
+// NewTypeResolver creates a new resolver that resolves types from the given files and their dependencies.
+// Types that are not found are resolved from the types linked into the binary.
+func NewTypeResolver(files ...protoreflect.FileDescriptor) *TypeResolver {
+	reg := &protoregistry.Files{}
+	var register func(fd protoreflect.FileDescriptor)
+	register = func(fd protoreflect.FileDescriptor) {
+		if fd == nil {
+			return
+		}
+		if _, err := reg.FindFileByPath(fd.Path()); err == nil {
+			return
+		}
+		// ignore conflicts since the first registered one is used
+		_ = reg.RegisterFile(fd)
+		imports := fd.Imports()
+		for i := range imports.Len() {
+			register(imports.Get(i).FileDescriptor)
+		}
+	}
+	for _, fd := range files {
+		register(fd)
+	}
+	return &TypeResolver{
+		types: dynamicpb.NewTypes(reg),
+	}
+}
+
+// FindMessageByName implements protoregistry.MessageTypeResolver interface.
+func (r *TypeResolver) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	if mt, err := r.types.FindMessageByName(name); err == nil {
+		return mt, nil
+	}
+	return protoregistry.GlobalTypes.FindMessageByName(name)
+}
+
+// FindMessageByURL implements protoregistry.MessageTypeResolver interface.
+func (r *TypeResolver) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	if mt, err := r.types.FindMessageByURL(url); err == nil {
+		return mt, nil
+	}
+	return protoregistry.GlobalTypes.FindMessageByURL(url)
+}
+
+// FindExtensionByName implements protoregistry.ExtensionTypeResolver interface.
+func (r *TypeResolver) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	if xt, err := r.types.FindExtensionByName(field); err == nil {
+		return xt, nil
+	}
+	return protoregistry.GlobalTypes.FindExtensionByName(field)
+}
+
+// FindExtensionByNumber implements protoregistry.ExtensionTypeResolver interface.
+func (r *TypeResolver) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	if xt, err := r.types.FindExtensionByNumber(message, field); err == nil {
+		return xt, nil
+	}
+	return protoregistry.GlobalTypes.FindExtensionByNumber(message, field)
+}
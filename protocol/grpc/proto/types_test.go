@@ -0,0 +1,45 @@
+package proto
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestTypeResolver(t *testing.T) {
+	fds, err := NewCompiler([]string{"./testdata"}).Compile(context.Background(), []string{"wkt.proto"})
+	if err != nil {
+		t.Fatalf("failed to compile: %s", err)
+	}
+	r := NewTypeResolver(fds.Files()[0])
+
+	t.Run("find by URL", func(t *testing.T) {
+		tests := map[string]protoreflect.FullName{
+			"type.googleapis.com/scenarigo.testdata.wkt.Detail": "scenarigo.testdata.wkt.Detail",
+			"type.googleapis.com/google.protobuf.Timestamp":     "google.protobuf.Timestamp",
+			"type.googleapis.com/google.rpc.Status":             "google.rpc.Status",
+		}
+		for url, expect := range tests {
+			t.Run(url, func(t *testing.T) {
+				mt, err := r.FindMessageByURL(url)
+				if err != nil {
+					t.Fatalf("failed to find: %s", err)
+				}
+				if got := mt.Descriptor().FullName(); got != expect {
+					t.Errorf("expect %s but got %s", expect, got)
+				}
+			})
+		}
+	})
+	t.Run("find by name", func(t *testing.T) {
+		if _, err := r.FindMessageByName("scenarigo.testdata.wkt.Message"); err != nil {
+			t.Fatalf("failed to find: %s", err)
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		if _, err := r.FindMessageByURL("type.googleapis.com/scenarigo.testdata.NotFound"); err == nil {
+			t.Fatal("no error")
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package grpc
 
 import (
+	"bytes"
 	gocontext "context"
 	"crypto/tls"
 	"crypto/x509"
@@ -9,26 +10,37 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"time"
 
 	"dario.cat/mergo"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // register gzip compressor
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protodelim"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
 
 	"github.com/goccy/go-yaml"
 	"github.com/zoncoen/query-go"
 
 	"github.com/scenarigo/scenarigo/context"
 	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/internal/artifact"
 	"github.com/scenarigo/scenarigo/internal/filepathutil"
+	"github.com/scenarigo/scenarigo/internal/fileutil"
 	"github.com/scenarigo/scenarigo/internal/queryutil"
 	"github.com/scenarigo/scenarigo/internal/reflectutil"
+	"github.com/scenarigo/scenarigo/internal/tlsutil"
 	"github.com/scenarigo/scenarigo/internal/yamlutil"
+	grpcproto "github.com/scenarigo/scenarigo/protocol/grpc/proto"
+	"github.com/scenarigo/scenarigo/schema"
 )
 
 var tlsVers = map[string]uint16{
@@ -47,7 +59,13 @@ type Request struct {
 	Method   string          `yaml:"method,omitempty"`
 	Metadata interface{}     `yaml:"metadata,omitempty"`
 	Message  interface{}     `yaml:"message,omitempty"`
+	Messages []interface{}   `yaml:"messages,omitempty"`
 	Options  *RequestOptions `yaml:"options,omitempty"`
+	// MessageFile is the path of the JSON or YAML file which has the message instead of message.
+	// The path is relative to the scenario file.
+	MessageFile string `yaml:"messageFile,omitempty"`
+	// Save is the option to save the response message to a file in the artifacts directory.
+	Save *artifact.SaveOption `yaml:"save,omitempty"`
 
 	// for backward compatibility
 	Body interface{} `yaml:"body,omitempty"`
@@ -58,6 +76,13 @@ type RequestOptions struct {
 	Reflection *ReflectionOption `yaml:"reflection,omitempty"`
 	Proto      *ProtoOption      `yaml:"proto,omitempty"`
 	Auth       *AuthOption       `yaml:"auth,omitempty"`
+	Call       *CallOption       `yaml:"call,omitempty"`
+	// Transport is the protocol to send RPCs. It must be one of "grpc" (default), "connect", or "grpc-web".
+	// The reflection service is always called with the gRPC protocol because it requires bidirectional streaming.
+	Transport string `yaml:"transport,omitempty"`
+	// Codec is the encoding of messages. It must be one of "proto" (default) or "json".
+	// The "json" codec is supported only by the "connect" transport.
+	Codec string `yaml:"codec,omitempty"`
 }
 
 // ReflectionOption represents a gRPC reflection service option.
@@ -74,8 +99,37 @@ func (o *ReflectionOption) IsEnabled() bool {
 
 // ProtoOption represents a protocol buffers option.
 type ProtoOption struct {
-	Imports []string `yaml:"imports,omitempty"`
-	Files   []string `yaml:"files,omitempty"`
+	Imports        []string `yaml:"imports,omitempty"`
+	Files          []string `yaml:"files,omitempty"`
+	DescriptorSets []string `yaml:"descriptorSets,omitempty"`
+}
+
+// ResolvePaths returns a copy of the option whose relative file paths are resolved against dir.
+func (o *ProtoOption) ResolvePaths(dir string) *ProtoOption {
+	if o == nil {
+		return nil
+	}
+	opt := &ProtoOption{
+		Imports:        resolvePaths(dir, o.Imports),
+		Files:          o.Files,
+		DescriptorSets: resolvePaths(dir, o.DescriptorSets),
+	}
+	// If import paths present and not empty, then all file paths to find are assumed to be relative to one of these paths.
+	if len(o.Imports) == 0 {
+		opt.Files = resolvePaths(dir, o.Files)
+	}
+	return opt
+}
+
+func resolvePaths(dir string, paths []string) []string {
+	if paths == nil {
+		return nil
+	}
+	resolved := make([]string, len(paths))
+	for i, p := range paths {
+		resolved[i] = filepathutil.From(dir, p)
+	}
+	return resolved
 }
 
 // AuthOption represents a authentication option.
@@ -86,17 +140,43 @@ type AuthOption struct {
 
 // Credentials returns a credentials for transport security.
 func (o *AuthOption) Credentials() (credentials.TransportCredentials, error) {
-	cfg := &tls.Config{
-		MinVersion: tls.VersionTLS12,
+	if o.isInsecure() {
+		return insecure.NewCredentials(), nil
 	}
-	if o == nil {
-		return credentials.NewTLS(cfg), nil
+	cfg, err := o.TLSConfig()
+	if err != nil {
+		return nil, err
 	}
-	if o.Insecure != nil && *o.Insecure {
-		return insecure.NewCredentials(), nil
+	return credentials.NewTLS(cfg), nil
+}
+
+// resolvePaths returns a copy of the option whose relative TLS file paths are resolved against dir.
+func (o *AuthOption) resolvePaths(dir string) *AuthOption {
+	if o == nil || o.TLS == nil {
+		return o
 	}
-	if o.TLS == nil {
-		return credentials.NewTLS(cfg), nil
+	auth := *o
+	tlsOpt := *auth.TLS
+	for _, p := range []*string{&tlsOpt.Certificate, &tlsOpt.ClientCertificate, &tlsOpt.ClientKey} {
+		if *p != "" {
+			*p = filepathutil.From(dir, *p)
+		}
+	}
+	auth.TLS = &tlsOpt
+	return &auth
+}
+
+func (o *AuthOption) isInsecure() bool {
+	return o != nil && o.Insecure != nil && *o.Insecure
+}
+
+// TLSConfig returns a TLS configuration for transport security.
+func (o *AuthOption) TLSConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if o == nil || o.TLS == nil {
+		return cfg, nil
 	}
 	if o.TLS.MinVersion != "" {
 		v, ok := tlsVers[o.TLS.MinVersion]
@@ -123,10 +203,26 @@ func (o *AuthOption) Credentials() (credentials.TransportCredentials, error) {
 		}
 		cfg.RootCAs = cp
 	}
+	if o.TLS.ClientCertificate != "" || o.TLS.ClientKey != "" {
+		if o.TLS.ClientCertificate == "" {
+			return nil, errors.ErrorPath("tls.clientCertificate", "client certificate must be specified with client key")
+		}
+		if o.TLS.ClientKey == "" {
+			return nil, errors.ErrorPath("tls.clientKey", "client key must be specified with client certificate")
+		}
+		cert, err := tls.LoadX509KeyPair(o.TLS.ClientCertificate, o.TLS.ClientKey)
+		if err != nil {
+			return nil, errors.WrapPath(err, "tls.clientCertificate", "failed to load client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if o.TLS.ServerName != "" {
+		cfg.ServerName = o.TLS.ServerName
+	}
 	if o.TLS.Skip {
 		cfg.InsecureSkipVerify = true
 	}
-	return credentials.NewTLS(cfg), nil
+	return cfg, nil
 }
 
 // TLSOption represents a TLS option.
@@ -139,8 +235,66 @@ type TLSOption struct {
 	// By default, TLS 1.3 is currently used as the maximum.
 	MaxVersion string `json:"maxVersion,omitempty" yaml:"maxVersion,omitempty"`
 
+	// Certificate is the file path of the PEM encoded CA certificate to verify the server certificate.
 	Certificate string `json:"certificate,omitempty" yaml:"certificate,omitempty"`
-	Skip        bool   `json:"skip,omitempty"        yaml:"skip,omitempty"`
+
+	// ClientCertificate and ClientKey are the file paths of the PEM encoded client certificate and its private key for mutual TLS.
+	// Relative paths in a step are resolved from the directory of the scenario file,
+	// and the ones in the protocols.grpc.request configuration are resolved from the root directory of the configuration.
+	// The path of Certificate is resolved in the same way.
+	ClientCertificate string `json:"clientCertificate,omitempty" yaml:"clientCertificate,omitempty"`
+	ClientKey         string `json:"clientKey,omitempty"         yaml:"clientKey,omitempty"`
+
+	// ServerName is used to verify the hostname of the server certificate instead of the target host.
+	// It is also sent to the server as SNI.
+	ServerName string `json:"serverName,omitempty" yaml:"serverName,omitempty"`
+
+	Skip bool `json:"skip,omitempty" yaml:"skip,omitempty"`
+}
+
+// CallOption represents options for each RPC call.
+type CallOption struct {
+	// Timeout sets the deadline of the call (e.g., 3s).
+	// It is independent of the step timeout.
+	Timeout *schema.Duration `yaml:"timeout,omitempty"`
+
+	// Compression is the name of the compressor to compress request messages.
+	// Currently, only "gzip" is supported.
+	Compression string `yaml:"compression,omitempty"`
+
+	// MaxSendMsgSize and MaxRecvMsgSize are the maximum message sizes in bytes the client can send and receive.
+	MaxSendMsgSize *int `yaml:"maxSendMsgSize,omitempty"`
+	MaxRecvMsgSize *int `yaml:"maxRecvMsgSize,omitempty"`
+
+	// WaitForReady configures the call to block until the connection is ready instead of failing fast.
+	WaitForReady *bool `yaml:"waitForReady,omitempty"`
+}
+
+func (o *CallOption) build(ctx gocontext.Context) (gocontext.Context, gocontext.CancelFunc, []grpc.CallOption, error) {
+	if o == nil {
+		return ctx, func() {}, nil, nil
+	}
+	var opts []grpc.CallOption
+	if o.Compression != "" {
+		if encoding.GetCompressor(o.Compression) == nil {
+			return nil, nil, nil, errors.ErrorPathf("compression", "unsupported compression %q", o.Compression)
+		}
+		opts = append(opts, grpc.UseCompressor(o.Compression))
+	}
+	if o.MaxSendMsgSize != nil {
+		opts = append(opts, grpc.MaxCallSendMsgSize(*o.MaxSendMsgSize))
+	}
+	if o.MaxRecvMsgSize != nil {
+		opts = append(opts, grpc.MaxCallRecvMsgSize(*o.MaxRecvMsgSize))
+	}
+	if o.WaitForReady != nil {
+		opts = append(opts, grpc.WaitForReady(*o.WaitForReady))
+	}
+	if o.Timeout != nil {
+		ctx, cancel := gocontext.WithTimeout(ctx, time.Duration(*o.Timeout))
+		return ctx, cancel, opts, nil
+	}
+	return ctx, func() {}, opts, nil
 }
 
 // RequestExtractor represents a request dump.
@@ -160,18 +314,43 @@ func (r RequestExtractor) ExtractByKey(key string) (interface{}, bool) {
 }
 
 type request struct {
-	Method   string                     `yaml:"method,omitempty"`
-	Metadata any                        `yaml:"metadata,omitempty"`
-	Message  *ProtoMessageYAMLMarshaler `yaml:"message,omitempty"`
+	Method   string                       `yaml:"method,omitempty"`
+	Metadata any                          `yaml:"metadata,omitempty"`
+	Message  *ProtoMessageYAMLMarshaler   `yaml:"message,omitempty"`
+	Messages []*ProtoMessageYAMLMarshaler `yaml:"messages,omitempty"`
+	Options  *requestOptions              `yaml:"options,omitempty"`
+	// ResolvedAddress is the address connected instead of the target by the resolve configuration.
+	ResolvedAddress string `yaml:"resolvedAddress,omitempty"`
+}
+
+type requestOptions struct {
+	Call *CallOption `yaml:"call,omitempty"`
 }
 
 type ProtoMessageYAMLMarshaler struct {
 	proto.Message `yaml:",inline"`
+	// types resolves the types of google.protobuf.Any values in the message.
+	// If nil, the types are resolved from the file which defines the message and its dependencies.
+	types *grpcproto.TypeResolver
+}
+
+// TypeResolver implements queryutil.TypeResolvable interface.
+func (m *ProtoMessageYAMLMarshaler) TypeResolver() protoregistry.MessageTypeResolver {
+	return m.typeResolver()
+}
+
+func (m *ProtoMessageYAMLMarshaler) typeResolver() *grpcproto.TypeResolver {
+	if m.types != nil {
+		return m.types
+	}
+	return grpcproto.NewTypeResolver(m.ProtoReflect().Descriptor().ParentFile())
 }
 
 // MarshalYAML implements yaml.BytesMarshalerContext interface.
 func (m *ProtoMessageYAMLMarshaler) MarshalYAML(_ gocontext.Context) ([]byte, error) {
-	jb, err := protojson.Marshal(m.Message)
+	jb, err := protojson.MarshalOptions{
+		Resolver: m.typeResolver(),
+	}.Marshal(m.Message)
 	if err != nil {
 		return nil, err
 	}
@@ -180,10 +359,15 @@ func (m *ProtoMessageYAMLMarshaler) MarshalYAML(_ gocontext.Context) ([]byte, er
 }
 
 type response struct {
-	Status  *responseStatus            `yaml:"status,omitempty"`
-	Header  *yamlutil.MDMarshaler      `yaml:"header,omitempty"`
-	Trailer *yamlutil.MDMarshaler      `yaml:"trailer,omitempty"`
-	Message *ProtoMessageYAMLMarshaler `yaml:"message,omitempty"`
+	Status   *responseStatus              `yaml:"status,omitempty"`
+	Header   *yamlutil.MDMarshaler        `yaml:"header,omitempty"`
+	Trailer  *yamlutil.MDMarshaler        `yaml:"trailer,omitempty"`
+	Message  *ProtoMessageYAMLMarshaler   `yaml:"message,omitempty"`
+	Messages []*ProtoMessageYAMLMarshaler `yaml:"messages,omitempty"`
+	// TLS is not dumped to the logs since it is too verbose.
+	TLS *tlsutil.ConnectionState `yaml:"tls,omitempty"`
+	// Artifact is the path of the file saved by the save option.
+	Artifact string `yaml:"artifact,omitempty"`
 }
 
 type responseStatus struct {
@@ -299,31 +483,48 @@ func (r *Request) addIndent(s string, indentNum int) string {
 
 // Invoke implements protocol.Invoker interface.
 func (r *Request) Invoke(ctx *context.Context) (*context.Context, interface{}, error) {
+	if r.MessageFile != "" {
+		if r.Message != nil {
+			return ctx, nil, errors.ErrorPath("messageFile", "message and messageFile can't be specified at the same time")
+		}
+		msg, err := fileutil.Load(ctx, r.MessageFile)
+		if err != nil {
+			return ctx, nil, errors.WithPath(err, "messageFile")
+		}
+		req := *r
+		req.Message, req.MessageFile = msg, ""
+		return req.Invoke(ctx)
+	}
 	opts := &RequestOptions{}
 	if r.Options != nil {
 		if err := mergo.Merge(opts, r.Options); err != nil {
 			return ctx, nil, errors.WrapPath(err, "options", "failed to apply options")
 		}
 	}
-	if pOpt := grpcProtocol.getOption(); pOpt != nil && pOpt.Request != nil {
-		if err := mergo.Merge(opts, pOpt.Request, mergo.WithoutDereference); err != nil {
-			return ctx, nil, errors.WrapPath(err, "options", "failed to apply options")
-		}
-	}
 	opts, err := context.ExecuteTemplate(ctx, opts)
 	if err != nil {
 		return ctx, nil, errors.WrapPath(err, "options", "failed to execute template")
 	}
-	if opts.Proto != nil {
-		dir := filepath.Dir(ctx.ScenarioFilepath())
-		for i, p := range opts.Proto.Imports {
-			opts.Proto.Imports[i] = filepathutil.From(dir, p)
+	// The file paths of the step are relative to the scenario file, and the ones of the configuration are relative to the root directory.
+	dir := filepath.Dir(ctx.ScenarioFilepath())
+	opts.Auth = opts.Auth.resolvePaths(dir)
+	opts.Proto = opts.Proto.ResolvePaths(dir)
+	if pOpt := grpcProtocol.getOption(); pOpt != nil && pOpt.Request != nil {
+		reqOpts, err := context.ExecuteTemplate(ctx, pOpt.Request)
+		if err != nil {
+			return ctx, nil, errors.WrapPath(err, "options", "failed to execute template")
 		}
-		// If import paths present and not empty, then all file paths to find are assumed to be relative to one of these paths.
-		if len(opts.Proto.Imports) == 0 {
-			for i, p := range opts.Proto.Files {
-				opts.Proto.Files[i] = filepathutil.From(dir, p)
-			}
+		// copy to avoid modifying the global options
+		global := *reqOpts
+		global.Auth = global.Auth.resolvePaths(pOpt.rootDir)
+		// for backward compatibility, the proto file paths are relative to the scenario file if the root directory is unknown
+		protoDir := pOpt.rootDir
+		if protoDir == "" {
+			protoDir = dir
+		}
+		global.Proto = global.Proto.ResolvePaths(protoDir)
+		if err := mergo.Merge(opts, &global, mergo.WithoutDereference); err != nil {
+			return ctx, nil, errors.WrapPath(err, "options", "failed to apply options")
 		}
 	}
 
@@ -335,18 +536,33 @@ func (r *Request) Invoke(ctx *context.Context) (*context.Context, interface{}, e
 	if err != nil {
 		return ctx, nil, err
 	}
+	reqCtx, cancel, callOpts, err := opts.Call.build(ctx.RequestContext())
+	if err != nil {
+		return ctx, nil, errors.WithPath(err, "options.call")
+	}
+	defer cancel()
+	if sc, ok := client.(streamServiceClient); ok && sc.isStreaming() {
+		return r.invokeStream(ctx, reqCtx, sc, opts, callOpts)
+	}
+	if len(r.Messages) > 0 {
+		return ctx, nil, errors.ErrorPath("messages", "messages can be used only for client-streaming or bidirectional-streaming RPC")
+	}
 	reqMsg, err := client.buildRequestMessage(ctx)
 	if err != nil {
 		return ctx, nil, err
 	}
-	ctx = r.dumpRequest(ctx, reqMsg)
+	ctx = r.dumpRequest(ctx, opts, client, reqMsg, nil)
 
-	var header, trailer metadata.MD
-	callOpts := []grpc.CallOption{
+	var (
+		header, trailer metadata.MD
+		p               peer.Peer
+	)
+	callOpts = append(callOpts,
 		grpc.Header(&header),
 		grpc.Trailer(&trailer),
-	}
-	respMsg, sts, err := client.invoke(ctx.RequestContext(), reqMsg, callOpts...)
+		grpc.Peer(&p),
+	)
+	respMsg, sts, err := client.invoke(reqCtx, reqMsg, callOpts...)
 	if err != nil {
 		return ctx, nil, err
 	}
@@ -354,22 +570,14 @@ func (r *Request) Invoke(ctx *context.Context) (*context.Context, interface{}, e
 		Status: &responseStatus{
 			status.New(codes.OK, ""),
 		},
-		Message: &ProtoMessageYAMLMarshaler{respMsg},
+		Message: &ProtoMessageYAMLMarshaler{Message: respMsg, types: typeResolver(client)},
 	}
 	if sts != nil {
 		resp.Status = &responseStatus{sts}
 	}
-	if len(header) > 0 {
-		resp.Header = yamlutil.NewMDMarshaler(header)
-	}
-	if len(trailer) > 0 {
-		resp.Trailer = yamlutil.NewMDMarshaler(trailer)
-	}
-	ctx = ctx.WithResponse((*ResponseExtractor)(resp))
-	if b, err := yaml.Marshal(resp); err == nil {
-		ctx.Reporter().Logf("response:\n%s", r.addIndent(string(b), indentNum))
-	} else {
-		ctx.Reporter().Logf("failed to dump response:\n%s", err)
+	ctx = r.dumpResponse(ctx, resp, header, trailer, &p)
+	if err := r.saveResponse(ctx, resp); err != nil {
+		return ctx, nil, err
 	}
 
 	return ctx, resp, nil
@@ -380,6 +588,16 @@ type serviceClient interface {
 	invoke(gocontext.Context, proto.Message, ...grpc.CallOption) (proto.Message, *status.Status, error)
 }
 
+// streamServiceClient is a serviceClient which can also call streaming RPCs.
+type streamServiceClient interface {
+	serviceClient
+	isStreaming() bool
+	isClientStreaming() bool
+	isServerStreaming() bool
+	buildRequestMessages(*context.Context) ([]proto.Message, error)
+	invokeStream(gocontext.Context, []proto.Message, ...grpc.CallOption) ([]proto.Message, *status.Status, error)
+}
+
 func (r *Request) buildClient(ctx *context.Context, opts *RequestOptions) (serviceClient, error) {
 	if r.Client != "" {
 		x, err := ctx.ExecuteTemplate(r.Client)
@@ -395,6 +613,23 @@ func (r *Request) buildClient(ctx *context.Context, opts *RequestOptions) (servi
 	return newProtoClient(ctx, r, opts)
 }
 
+// resolvedAddress returns the address connected instead of the target by the resolve configuration.
+func resolvedAddress(client serviceClient) string {
+	if c, ok := client.(*protoClient); ok {
+		return c.resolvedAddr
+	}
+	return ""
+}
+
+// typeResolver returns the resolver of the types in the proto files loaded by the client.
+// It returns nil for the custom clients since their messages are registered in the global registry.
+func typeResolver(client serviceClient) *grpcproto.TypeResolver {
+	if c, ok := client.(*protoClient); ok {
+		return c.types
+	}
+	return nil
+}
+
 func (r *Request) appendMetadata(ctx *context.Context) (*context.Context, error) {
 	if r.Metadata == nil {
 		return ctx, nil
@@ -418,11 +653,26 @@ func (r *Request) appendMetadata(ctx *context.Context) (*context.Context, error)
 	), nil
 }
 
-func (r *Request) dumpRequest(ctx *context.Context, reqMsg proto.Message) *context.Context {
+func (r *Request) dumpRequest(ctx *context.Context, opts *RequestOptions, client serviceClient, reqMsg proto.Message, reqMsgs []proto.Message) *context.Context {
 	//nolint:exhaustruct
 	dumpReq := &request{
-		Method:  r.Method,
-		Message: &ProtoMessageYAMLMarshaler{reqMsg},
+		Method:          r.Method,
+		ResolvedAddress: resolvedAddress(client),
+	}
+	types := typeResolver(client)
+	if opts != nil && opts.Call != nil {
+		dumpReq.Options = &requestOptions{
+			Call: opts.Call,
+		}
+	}
+	if reqMsg != nil {
+		dumpReq.Message = &ProtoMessageYAMLMarshaler{Message: reqMsg, types: types}
+	}
+	if len(reqMsgs) > 0 {
+		dumpReq.Messages = make([]*ProtoMessageYAMLMarshaler, len(reqMsgs))
+		for i, msg := range reqMsgs {
+			dumpReq.Messages[i] = &ProtoMessageYAMLMarshaler{Message: msg, types: types}
+		}
 	}
 	reqMD, _ := metadata.FromOutgoingContext(ctx.RequestContext())
 	if len(reqMD) > 0 {
@@ -436,3 +686,65 @@ func (r *Request) dumpRequest(ctx *context.Context, reqMsg proto.Message) *conte
 	}
 	return ctx
 }
+
+func (r *Request) dumpResponse(ctx *context.Context, resp *response, header, trailer metadata.MD, p *peer.Peer) *context.Context {
+	if len(header) > 0 {
+		resp.Header = yamlutil.NewMDMarshaler(header)
+	}
+	if len(trailer) > 0 {
+		resp.Trailer = yamlutil.NewMDMarshaler(trailer)
+	}
+	resp.TLS = peerTLS(p)
+	ctx = ctx.WithResponse((*ResponseExtractor)(resp))
+	dump := *resp
+	dump.TLS = nil
+	if b, err := yaml.Marshal(&dump); err == nil {
+		ctx.Reporter().Logf("response:\n%s", r.addIndent(string(b), indentNum))
+	} else {
+		ctx.Reporter().Logf("failed to dump response:\n%s", err)
+	}
+	return ctx
+}
+
+// saveResponse saves the response message in the binary wire format if the save option is specified.
+// The messages of server-streaming RPCs are saved as a stream of size-delimited messages.
+func (r *Request) saveResponse(ctx *context.Context, resp *response) error {
+	if r.Save == nil {
+		return nil
+	}
+	var (
+		raw []byte
+		err error
+	)
+	if resp.Messages != nil {
+		var buf bytes.Buffer
+		for _, msg := range resp.Messages {
+			if _, err := protodelim.MarshalTo(&buf, msg.Message); err != nil {
+				return errors.Errorf("failed to marshal response message: %s", err)
+			}
+		}
+		raw = buf.Bytes()
+	} else if resp.Message != nil && resp.Message.Message != nil {
+		raw, err = proto.Marshal(resp.Message.Message)
+		if err != nil {
+			return errors.Errorf("failed to marshal response message: %s", err)
+		}
+	}
+	path, err := r.Save.Save(ctx, raw)
+	if err != nil {
+		return errors.WithPath(err, "save")
+	}
+	resp.Artifact = path
+	return nil
+}
+
+// peerTLS returns the state of the TLS connection to the peer. It returns nil if the connection is not secured by TLS.
+func peerTLS(p *peer.Peer) *tlsutil.ConnectionState {
+	if p == nil {
+		return nil
+	}
+	if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		return tlsutil.NewConnectionState(&info.State, time.Now())
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/scenarigo/scenarigo/context"
 	"github.com/scenarigo/scenarigo/errors"
 	"github.com/scenarigo/scenarigo/internal/reflectutil"
+	grpcproto "github.com/scenarigo/scenarigo/protocol/grpc/proto"
 )
 
 type customServiceClient struct {
@@ -53,7 +54,7 @@ func newCustomServiceClient(r *Request, v reflect.Value) (*customServiceClient,
 
 func (client *customServiceClient) buildRequestMessage(ctx *context.Context) (proto.Message, error) {
 	req := reflect.New(client.method.Type().In(1).Elem()).Interface()
-	if err := buildRequestMsg(ctx, req, client.r.Message); err != nil {
+	if err := buildRequestMsg(ctx, req, client.r.Message, nil); err != nil {
 		return nil, errors.WrapPathf(err, "message", "failed to build request message")
 	}
 	reqMsg, ok := req.(proto.Message)
@@ -142,7 +143,7 @@ func validateMethod(method reflect.Value) error {
 	return nil
 }
 
-func buildRequestMsg(ctx *context.Context, req interface{}, src interface{}) error {
+func buildRequestMsg(ctx *context.Context, req interface{}, src interface{}, types *grpcproto.TypeResolver) error {
 	x, err := ctx.ExecuteTemplate(src)
 	if err != nil {
 		return err
@@ -154,15 +155,24 @@ func buildRequestMsg(ctx *context.Context, req interface{}, src interface{}) err
 	if !ok {
 		return fmt.Errorf("expect proto.Message but got %T", req)
 	}
-	return ConvertToProto(x, msg)
+	return convertToProto(x, msg, types)
 }
 
+// ConvertToProto converts v into msg via the JSON representation.
+// The type of google.protobuf.Any value is resolved from the descriptors which msg depends on.
 func ConvertToProto(v any, msg proto.Message) error {
+	return convertToProto(v, msg, nil)
+}
+
+func convertToProto(v any, msg proto.Message, types *grpcproto.TypeResolver) error {
+	if types == nil {
+		types = grpcproto.NewTypeResolver(msg.ProtoReflect().Descriptor().ParentFile())
+	}
 	var buf bytes.Buffer
 	if err := yaml.NewEncoder(&buf, yaml.JSON()).Encode(v); err != nil {
 		return err
 	}
-	if err := protojson.Unmarshal(buf.Bytes(), msg); err != nil {
+	if err := (protojson.UnmarshalOptions{Resolver: types}).Unmarshal(buf.Bytes(), msg); err != nil {
 		return err
 	}
 	return nil
@@ -4,10 +4,14 @@ import (
 	gocontext "context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"strings"
 	"sync"
 
 	"google.golang.org/grpc"
+	grpcresolver "google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -15,29 +19,51 @@ import (
 
 	"github.com/scenarigo/scenarigo/context"
 	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/internal/netutil"
 	grpcproto "github.com/scenarigo/scenarigo/protocol/grpc/proto"
 )
 
 var (
 	connPool = &grpcConnPool{
-		conns: map[string]*grpc.ClientConn{},
+		conns:     map[string]*grpc.ClientConn{},
+		httpConns: map[string]*httpConn{},
+		services:  map[string]map[protoreflect.FullName]protoreflect.ServiceDescriptor{},
 	}
 	fdCache = &protoFdCache{
-		fds: map[string]grpcproto.FileDescriptors{},
+		fds:  map[string]grpcproto.FileDescriptors{},
+		sets: map[string]*grpcproto.DescriptorSet{},
 	}
 )
 
 type grpcConnPool struct {
 	m     sync.Mutex
 	conns map[string]*grpc.ClientConn
+	// httpConns holds the connections for the Connect and gRPC-Web transports.
+	httpConns map[string]*httpConn
+	// services caches the service descriptors resolved via the reflection service for each connection.
+	services map[string]map[protoreflect.FullName]protoreflect.ServiceDescriptor
 }
 
-func (p *grpcConnPool) NewClient(target string, o *AuthOption) (*grpc.ClientConn, error) {
+// connKey returns the key of the connection.
+// The resolve is the address (or the overrides) connected instead of the target by the resolve configuration.
+func connKey(target string, o *AuthOption, resolve string) (string, error) {
 	b, err := json.Marshal(o)
 	if err != nil {
-		return nil, errors.WrapPath(err, "auth", "failed to marshal auth option")
+		return "", errors.WrapPath(err, "auth", "failed to marshal auth option")
 	}
 	k := fmt.Sprintf("target=%s:auth=%s", target, string(b))
+	if resolve != "" {
+		k = fmt.Sprintf("%s:resolve=%s", k, resolve)
+	}
+	return k, nil
+}
+
+func (p *grpcConnPool) NewClient(target string, o *AuthOption, overrides netutil.Resolver) (*grpc.ClientConn, error) {
+	addr, resolved := resolveTarget(target, overrides)
+	k, err := connKey(target, o, addr)
+	if err != nil {
+		return nil, err
+	}
 
 	p.m.Lock()
 	defer p.m.Unlock()
@@ -48,7 +74,11 @@ func (p *grpcConnPool) NewClient(target string, o *AuthOption) (*grpc.ClientConn
 	if err != nil {
 		return nil, errors.WithPath(err, "auth")
 	}
-	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if resolved {
+		dialOpts = append(dialOpts, grpc.WithResolvers(staticResolver(addr)))
+	}
+	conn, err := grpc.NewClient(target, dialOpts...)
 	if err != nil {
 		return nil, errors.WithPath(err, "target")
 	}
@@ -56,6 +86,63 @@ func (p *grpcConnPool) NewClient(target string, o *AuthOption) (*grpc.ClientConn
 	return conn, nil
 }
 
+// NewHTTPClient returns a connection to send RPCs with the Connect or gRPC-Web protocol.
+func (p *grpcConnPool) NewHTTPClient(transport, target string, o *AuthOption, overrides netutil.Resolver) (*httpConn, error) {
+	// all overrides are used since the connection dials with them
+	k, err := connKey(target, o, overrides.String())
+	if err != nil {
+		return nil, err
+	}
+	k = fmt.Sprintf("%s:transport=%s", k, transport)
+
+	p.m.Lock()
+	defer p.m.Unlock()
+	if conn, ok := p.httpConns[k]; ok {
+		return conn, nil
+	}
+	conn, err := newHTTPConn(transport, target, o, overrides)
+	if err != nil {
+		return nil, err
+	}
+	p.httpConns[k] = conn
+	return conn, nil
+}
+
+// reflectionResolver returns a resolver that resolves service descriptors via the reflection service.
+// The resolved descriptors are cached until the connection is closed.
+func (p *grpcConnPool) reflectionResolver(ctx gocontext.Context, target string, o *AuthOption, overrides netutil.Resolver, conn *grpc.ClientConn) (*cachedReflectionResolver, error) {
+	addr, _ := resolveTarget(target, overrides)
+	k, err := connKey(target, o, addr)
+	if err != nil {
+		return nil, err
+	}
+	return &cachedReflectionResolver{
+		ctx:  ctx,
+		pool: p,
+		key:  k,
+		conn: conn,
+	}, nil
+}
+
+func (p *grpcConnPool) getService(k string, name protoreflect.FullName) (protoreflect.ServiceDescriptor, bool) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	sd, ok := p.services[k][name]
+	return sd, ok
+}
+
+func (p *grpcConnPool) setService(k string, sd protoreflect.ServiceDescriptor) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if _, ok := p.conns[k]; !ok {
+		return // already closed
+	}
+	if p.services[k] == nil {
+		p.services[k] = map[protoreflect.FullName]protoreflect.ServiceDescriptor{}
+	}
+	p.services[k][sd.FullName()] = sd
+}
+
 func (p *grpcConnPool) closeConnection(target string) error {
 	prefix := fmt.Sprintf("target=%s:", target)
 	p.m.Lock()
@@ -63,17 +150,73 @@ func (p *grpcConnPool) closeConnection(target string) error {
 	for k, conn := range p.conns {
 		if strings.HasPrefix(k, prefix) {
 			delete(p.conns, k)
+			delete(p.services, k)
 			if err := conn.Close(); err != nil {
 				return err
 			}
 		}
 	}
+	for k, conn := range p.httpConns {
+		if strings.HasPrefix(k, prefix) {
+			delete(p.httpConns, k)
+			_ = conn.Close()
+		}
+	}
 	return nil
 }
 
+// closeAll closes all connections and clears the cached descriptors.
+func (p *grpcConnPool) closeAll() error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	var errs []error
+	for k, conn := range p.conns {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close connection to %s: %w", conn.Target(), err))
+		}
+		delete(p.conns, k)
+	}
+	for k, conn := range p.httpConns {
+		_ = conn.Close()
+		delete(p.httpConns, k)
+	}
+	p.services = map[string]map[protoreflect.FullName]protoreflect.ServiceDescriptor{}
+	return errors.Errors(errs...)
+}
+
+type cachedReflectionResolver struct {
+	ctx  gocontext.Context
+	pool *grpcConnPool
+	key  string
+	conn *grpc.ClientConn
+}
+
+// ListServices implements grpcproto.ServiceDescriptorResolver interface.
+func (r *cachedReflectionResolver) ListServices() ([]protoreflect.FullName, error) {
+	c := grpcproto.NewReflectionClient(r.ctx, r.conn)
+	defer c.Close()
+	return c.ListServices()
+}
+
+// ResolveService implements grpcproto.ServiceDescriptorResolver interface.
+func (r *cachedReflectionResolver) ResolveService(name protoreflect.FullName) (protoreflect.ServiceDescriptor, error) {
+	if sd, ok := r.pool.getService(r.key, name); ok {
+		return sd, nil
+	}
+	c := grpcproto.NewReflectionClient(r.ctx, r.conn)
+	defer c.Close()
+	sd, err := c.ResolveService(name)
+	if err != nil {
+		return nil, err
+	}
+	r.pool.setService(r.key, sd)
+	return sd, nil
+}
+
 type protoFdCache struct {
-	m   sync.Mutex
-	fds map[string]grpcproto.FileDescriptors
+	m    sync.Mutex
+	fds  map[string]grpcproto.FileDescriptors
+	sets map[string]*grpcproto.DescriptorSet
 }
 
 func (c *protoFdCache) Compile(ctx gocontext.Context, imports, files []string) (grpcproto.FileDescriptors, error) {
@@ -92,12 +235,32 @@ func (c *protoFdCache) Compile(ctx gocontext.Context, imports, files []string) (
 	return fds, nil
 }
 
+func (c *protoFdCache) LoadDescriptorSets(files []string) (*grpcproto.DescriptorSet, error) {
+	k := strings.Join(files, ",")
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	if set, ok := c.sets[k]; ok {
+		return set, nil
+	}
+	set, err := grpcproto.LoadDescriptorSets(files)
+	if err != nil {
+		return nil, err
+	}
+	c.sets[k] = set
+	return set, nil
+}
+
 type protoClient struct {
 	r              *Request
-	conn           *grpc.ClientConn
+	conn           grpc.ClientConnInterface
 	resolver       grpcproto.ServiceDescriptorResolver
+	types          *grpcproto.TypeResolver
 	fullMethodName string
 	md             protoreflect.MethodDescriptor
+	// resolvedAddr is the address connected instead of the target by the resolve configuration.
+	resolvedAddr string
+	codec        string
 }
 
 func newProtoClient(ctx *context.Context, r *Request, opts *RequestOptions) (*protoClient, error) {
@@ -112,21 +275,28 @@ func newProtoClient(ctx *context.Context, r *Request, opts *RequestOptions) (*pr
 	if !ok {
 		return nil, errors.ErrorPathf("target", "target must be string but %T", x)
 	}
-	conn, err := connPool.NewClient(target, opts.Auth)
+	overrides := ctx.Resolver()
+	conn, reflectionConn, err := newConn(target, opts, overrides)
 	if err != nil {
 		return nil, err
 	}
+	resolvedAddr, _ := resolveTarget(target, overrides)
+	if c, ok := conn.(*httpConn); ok {
+		resolvedAddr, _ = overrides.Resolve(c.host())
+	}
 
 	var resolver grpcproto.ServiceDescriptorResolver
-	if !opts.Reflection.IsEnabled() && opts.Proto != nil && len(opts.Proto.Files) > 0 {
-		fds, err := fdCache.Compile(ctx.RequestContext(), opts.Proto.Imports, opts.Proto.Files)
+	if !opts.Reflection.IsEnabled() && opts.Proto != nil && (len(opts.Proto.Files) > 0 || len(opts.Proto.DescriptorSets) > 0) {
+		resolver, err = buildProtoResolver(ctx.RequestContext(), opts.Proto)
 		if err != nil {
 			return nil, errors.WithPath(err, "options.proto")
 		}
-		resolver = fds
 	}
 	if resolver == nil {
-		resolver = grpcproto.NewReflectionClient(ctx.RequestContext(), conn)
+		resolver, err = connPool.reflectionResolver(ctx.RequestContext(), reflectionConn.Target(), opts.Auth, overrides, reflectionConn)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	sd, err := resolver.ResolveService(protoreflect.FullName(r.Service))
@@ -145,24 +315,245 @@ func newProtoClient(ctx *context.Context, r *Request, opts *RequestOptions) (*pr
 		r:              r,
 		conn:           conn,
 		resolver:       resolver,
+		types:          grpcproto.NewTypeResolver(append([]protoreflect.FileDescriptor{sd.ParentFile()}, loadedFiles(resolver)...)...),
 		fullMethodName: fmt.Sprintf("/%s/%s", sd.FullName(), md.Name()),
 		md:             md,
+		resolvedAddr:   resolvedAddr,
+		codec:          opts.Codec,
 	}, nil
 }
 
+// newConn returns a connection to send RPCs with the specified transport and a gRPC connection to call the reflection service.
+func newConn(target string, opts *RequestOptions, overrides netutil.Resolver) (grpc.ClientConnInterface, *grpc.ClientConn, error) {
+	switch opts.Codec {
+	case "", CodecProto:
+	case CodecJSON:
+		if opts.Transport != TransportConnect {
+			return nil, nil, errors.ErrorPathf("options.codec", "codec %q is supported only by the connect transport", opts.Codec)
+		}
+	default:
+		return nil, nil, errors.ErrorPathf("options.codec", "unsupported codec %q", opts.Codec)
+	}
+	switch opts.Transport {
+	case "", TransportGRPC:
+		conn, err := connPool.NewClient(target, opts.Auth, overrides)
+		if err != nil {
+			return nil, nil, err
+		}
+		return conn, conn, nil
+	case TransportConnect, TransportGRPCWeb:
+		conn, err := connPool.NewHTTPClient(opts.Transport, target, opts.Auth, overrides)
+		if err != nil {
+			return nil, nil, err
+		}
+		// The reflection service is called via the gRPC protocol since it requires bidirectional-streaming.
+		reflectionConn, err := connPool.NewClient(conn.host(), opts.Auth, overrides)
+		if err != nil {
+			return nil, nil, err
+		}
+		return conn, reflectionConn, nil
+	default:
+		return nil, nil, errors.ErrorPathf("options.transport", "unsupported transport %q", opts.Transport)
+	}
+}
+
+// resolveTarget returns the address to connect instead of the target by the resolve configuration.
+// Only the targets resolved by DNS (e.g., localhost:50051, dns:///localhost:50051) can be overridden.
+func resolveTarget(target string, overrides netutil.Resolver) (string, bool) {
+	if len(overrides) == 0 {
+		return "", false
+	}
+	hostport, ok := strings.CutPrefix(target, "dns:///")
+	if !ok && (strings.Contains(target, "://") || strings.HasPrefix(target, "unix:")) {
+		return "", false
+	}
+	if _, _, err := net.SplitHostPort(hostport); err != nil {
+		// the default port of gRPC
+		hostport = net.JoinHostPort(strings.Trim(hostport, "[]"), "443")
+	}
+	return overrides.Resolve(hostport)
+}
+
+// staticResolver returns a resolver builder which resolves the target to addr instead of DNS.
+// Since the target is not changed, the authority (and the server name of TLS) is still the host of the target.
+func staticResolver(addr string) grpcresolver.Builder {
+	r := manual.NewBuilderWithScheme("dns")
+	r.InitialState(grpcresolver.State{
+		Addresses: []grpcresolver.Address{{Addr: addr}},
+	})
+	return r
+}
+
+// loadedFiles returns the files loaded by the resolver to resolve the type of google.protobuf.Any value.
+func loadedFiles(r grpcproto.ServiceDescriptorResolver) []protoreflect.FileDescriptor {
+	var files []protoreflect.FileDescriptor
+	switch r := r.(type) {
+	case grpcproto.FileDescriptors:
+		for _, f := range r.Files() {
+			files = append(files, f)
+		}
+	case *grpcproto.DescriptorSet:
+		r.Files().RangeFiles(func(f protoreflect.FileDescriptor) bool {
+			files = append(files, f)
+			return true
+		})
+	case grpcproto.ServiceDescriptorResolvers:
+		for _, rr := range r {
+			files = append(files, loadedFiles(rr)...)
+		}
+	}
+	return files
+}
+
+// TypeResolver returns a resolver of the types defined in the proto files and the descriptor sets.
+// The compiled files and the loaded descriptor sets are cached and shared with the gRPC requests.
+func (o *ProtoOption) TypeResolver(ctx gocontext.Context) (*grpcproto.TypeResolver, error) {
+	if o == nil || (len(o.Files) == 0 && len(o.DescriptorSets) == 0) {
+		return grpcproto.NewTypeResolver(), nil
+	}
+	resolver, err := buildProtoResolver(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	return grpcproto.NewTypeResolver(loadedFiles(resolver)...), nil
+}
+
+func buildProtoResolver(ctx gocontext.Context, opt *ProtoOption) (grpcproto.ServiceDescriptorResolver, error) {
+	var resolvers grpcproto.ServiceDescriptorResolvers
+	if len(opt.DescriptorSets) > 0 {
+		set, err := fdCache.LoadDescriptorSets(opt.DescriptorSets)
+		if err != nil {
+			return nil, errors.WithPath(err, "descriptorSets")
+		}
+		resolvers = append(resolvers, set)
+	}
+	if len(opt.Files) > 0 {
+		fds, err := fdCache.Compile(ctx, opt.Imports, opt.Files)
+		if err != nil {
+			return nil, err
+		}
+		resolvers = append(resolvers, fds)
+	}
+	if len(resolvers) == 1 {
+		return resolvers[0], nil
+	}
+	return resolvers, nil
+}
+
 func (client *protoClient) buildRequestMessage(ctx *context.Context) (proto.Message, error) {
 	in := dynamicpb.NewMessage(client.md.Input())
-	if err := buildRequestMsg(ctx, in, client.r.Message); err != nil {
+	if err := buildRequestMsg(ctx, in, client.r.Message, client.types); err != nil {
 		return nil, errors.WrapPathf(err, "message", "failed to build request message")
 	}
 	return in, nil
 }
 
+// callOptions returns the call options with the codec option for the HTTP transports.
+func (client *protoClient) callOptions(opts []grpc.CallOption) []grpc.CallOption {
+	if client.codec == "" || client.codec == CodecProto {
+		return opts
+	}
+	return append(opts, codecCallOption{
+		codec: client.codec,
+		types: client.types,
+	})
+}
+
 func (client *protoClient) invoke(ctx gocontext.Context, in proto.Message, opts ...grpc.CallOption) (proto.Message, *status.Status, error) {
 	out := dynamicpb.NewMessage(client.md.Output())
 	var sts *status.Status
-	if err := client.conn.Invoke(ctx, client.fullMethodName, in, out, opts...); err != nil {
+	if err := client.conn.Invoke(ctx, client.fullMethodName, in, out, client.callOptions(opts)...); err != nil {
 		sts = status.Convert(err)
 	}
 	return out, sts, nil
 }
+
+func (client *protoClient) isStreaming() bool {
+	return client.md.IsStreamingClient() || client.md.IsStreamingServer()
+}
+
+func (client *protoClient) isClientStreaming() bool {
+	return client.md.IsStreamingClient()
+}
+
+func (client *protoClient) isServerStreaming() bool {
+	return client.md.IsStreamingServer()
+}
+
+func (client *protoClient) buildRequestMessages(ctx *context.Context) ([]proto.Message, error) {
+	msgs := make([]proto.Message, len(client.r.Messages))
+	for i, m := range client.r.Messages {
+		in := dynamicpb.NewMessage(client.md.Input())
+		if err := buildRequestMsg(ctx, in, m, client.types); err != nil {
+			return nil, errors.WrapPathf(err, fmt.Sprintf("messages[%d]", i), "failed to build request message")
+		}
+		msgs[i] = in
+	}
+	return msgs, nil
+}
+
+func (client *protoClient) invokeStream(ctx gocontext.Context, in []proto.Message, opts ...grpc.CallOption) ([]proto.Message, *status.Status, error) {
+	desc := &grpc.StreamDesc{
+		StreamName:    string(client.md.Name()),
+		ServerStreams: client.md.IsStreamingServer(),
+		ClientStreams: client.md.IsStreamingClient(),
+	}
+	stream, err := client.conn.NewStream(ctx, desc, client.fullMethodName, client.callOptions(opts)...)
+	if err != nil {
+		return nil, status.Convert(err), nil
+	}
+	// The messages of bidirectional streaming RPCs are sent concurrently with receiving the responses
+	// because the server may wait for the client to receive a response before receiving the next request.
+	// The HTTP transports (Connect and gRPC-Web) are half-duplex, so they always send all messages first.
+	_, isHTTP := client.conn.(*httpConn)
+	if desc.ClientStreams && desc.ServerStreams && !isHTTP {
+		sendErr := make(chan error, 1)
+		go func() {
+			sendErr <- sendMessages(stream, in)
+		}()
+		out, sts := client.receiveMessages(stream, desc)
+		if err := <-sendErr; err != nil && sts == nil {
+			sts = status.Convert(err)
+		}
+		return out, sts, nil
+	}
+	if err := sendMessages(stream, in); err != nil {
+		return nil, status.Convert(err), nil
+	}
+	out, sts := client.receiveMessages(stream, desc)
+	return out, sts, nil
+}
+
+// sendMessages sends the messages and half-closes the stream.
+func sendMessages(stream grpc.ClientStream, in []proto.Message) error {
+	for _, msg := range in {
+		if err := stream.SendMsg(msg); err != nil {
+			// io.EOF means that the server has closed the stream.
+			// The actual status is returned by RecvMsg.
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+	}
+	return stream.CloseSend()
+}
+
+// receiveMessages receives the response messages until the server closes the stream.
+func (client *protoClient) receiveMessages(stream grpc.ClientStream, desc *grpc.StreamDesc) ([]proto.Message, *status.Status) {
+	var out []proto.Message
+	for {
+		msg := dynamicpb.NewMessage(client.md.Output())
+		if err := stream.RecvMsg(msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return out, status.Convert(err)
+		}
+		out = append(out, msg)
+		if !desc.ServerStreams {
+			break
+		}
+	}
+	return out, nil
+}
@@ -1,13 +1,11 @@
 package grpc
 
 import (
+	"bytes"
 	gocontext "context"
-	"crypto/ed25519"
-	"crypto/rand"
-	"crypto/x509"
-	"encoding/pem"
-	"math/big"
-	"net"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -18,16 +16,23 @@ import (
 	"github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/internal/artifact"
+	"github.com/scenarigo/scenarigo/internal/netutil"
 	"github.com/scenarigo/scenarigo/internal/ptr"
 	"github.com/scenarigo/scenarigo/internal/testutil"
+	"github.com/scenarigo/scenarigo/schema"
 	testpb "github.com/scenarigo/scenarigo/testdata/gen/pb/test"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/testing/protocmp"
 )
 
 func TestProtoClient(t *testing.T) {
-	caCert, serverCert, serverKey := generateCert(t)
+	caCert, serverCert, serverKey := testutil.GenerateCert(t)
 
 	defaultHandler := func(ctx gocontext.Context, req *testpb.EchoRequest) (*testpb.EchoResponse, error) {
 		return &testpb.EchoResponse{
@@ -39,6 +44,7 @@ func TestProtoClient(t *testing.T) {
 		handler           func(gocontext.Context, *testpb.EchoRequest) (*testpb.EchoResponse, error)
 		disableReflection bool
 		enableTLS         bool
+		requireClientCert bool
 		request           *Request
 		expectCode        codes.Code
 		expectResponse    *testpb.EchoResponse
@@ -71,6 +77,33 @@ func TestProtoClient(t *testing.T) {
 				MessageBody: "hello",
 			},
 		},
+		"success (proto client with descriptor sets)": {
+			handler: defaultHandler,
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: &RequestOptions{
+					Proto: &ProtoOption{
+						DescriptorSets: []string{
+							"../../testdata/gen/pb/test/test.protoset",
+						},
+					},
+					Auth: &AuthOption{
+						Insecure: ptr.To(true),
+					},
+				},
+			},
+			expectCode: codes.OK,
+			expectResponse: &testpb.EchoResponse{
+				MessageId:   "1",
+				MessageBody: "hello",
+			},
+		},
 		"success (proto reflection client)": {
 			handler: defaultHandler,
 			request: &Request{
@@ -93,6 +126,59 @@ func TestProtoClient(t *testing.T) {
 				MessageBody: "hello",
 			},
 		},
+		"call options": {
+			handler: defaultHandler,
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: &RequestOptions{
+					Proto: &ProtoOption{
+						Files: []string{
+							"../../testdata/proto/test/test.proto",
+						},
+					},
+					Auth: &AuthOption{
+						Insecure: ptr.To(true),
+					},
+					Call: &CallOption{
+						Timeout:        ptr.To(schema.Duration(time.Second)),
+						Compression:    "gzip",
+						MaxSendMsgSize: ptr.To(1024),
+						MaxRecvMsgSize: ptr.To(1024),
+						WaitForReady:   ptr.To(true),
+					},
+				},
+			},
+			expectCode: codes.OK,
+			expectResponse: &testpb.EchoResponse{
+				MessageId:   "1",
+				MessageBody: "hello",
+			},
+		},
+		"message file": {
+			handler: defaultHandler,
+			request: &Request{
+				Target:      "{{vars.target}}",
+				Service:     testpb.Test_ServiceDesc.ServiceName,
+				Method:      "Echo",
+				MessageFile: "testdata/message.json",
+				Options: &RequestOptions{
+					Auth: &AuthOption{
+						Insecure: ptr.To(true),
+					},
+				},
+			},
+			expectCode: codes.OK,
+			expectResponse: &testpb.EchoResponse{
+				MessageId:   "1",
+				MessageBody: "hello",
+			},
+		},
 		"enable TLS": {
 			handler:   defaultHandler,
 			enableTLS: true,
@@ -125,6 +211,70 @@ func TestProtoClient(t *testing.T) {
 				MessageBody: "hello",
 			},
 		},
+		"mutual TLS": {
+			handler:           defaultHandler,
+			enableTLS:         true,
+			requireClientCert: true,
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: &RequestOptions{
+					Proto: &ProtoOption{
+						Files: []string{
+							"../../testdata/proto/test/test.proto",
+						},
+					},
+					Auth: &AuthOption{
+						TLS: &TLSOption{
+							Certificate:       caCert,
+							ClientCertificate: serverCert,
+							ClientKey:         serverKey,
+						},
+					},
+				},
+			},
+			expectCode: codes.OK,
+			expectResponse: &testpb.EchoResponse{
+				MessageId:   "1",
+				MessageBody: "hello",
+			},
+		},
+		"server name": {
+			handler:   defaultHandler,
+			enableTLS: true,
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: &RequestOptions{
+					Proto: &ProtoOption{
+						Files: []string{
+							"../../testdata/proto/test/test.proto",
+						},
+					},
+					Auth: &AuthOption{
+						TLS: &TLSOption{
+							Certificate: caCert,
+							ServerName:  "localhost",
+						},
+					},
+				},
+			},
+			expectCode: codes.OK,
+			expectResponse: &testpb.EchoResponse{
+				MessageId:   "1",
+				MessageBody: "hello",
+			},
+		},
 		"skip TLS verification": {
 			handler:   defaultHandler,
 			enableTLS: true,
@@ -181,6 +331,87 @@ func TestProtoClient(t *testing.T) {
 			},
 			expectCode: codes.Unauthenticated,
 		},
+		"deadline exceeded": {
+			handler: func(ctx gocontext.Context, req *testpb.EchoRequest) (*testpb.EchoResponse, error) {
+				<-ctx.Done()
+				return nil, status.FromContextError(ctx.Err()).Err()
+			},
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: &RequestOptions{
+					Proto: &ProtoOption{
+						Files: []string{
+							"../../testdata/proto/test/test.proto",
+						},
+					},
+					Auth: &AuthOption{
+						Insecure: ptr.To(true),
+					},
+					Call: &CallOption{
+						Timeout: ptr.To(schema.Duration(10 * time.Millisecond)),
+					},
+				},
+			},
+			expectCode: codes.DeadlineExceeded,
+		},
+		"exceed max receive message size": {
+			handler: defaultHandler,
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: &RequestOptions{
+					Proto: &ProtoOption{
+						Files: []string{
+							"../../testdata/proto/test/test.proto",
+						},
+					},
+					Auth: &AuthOption{
+						Insecure: ptr.To(true),
+					},
+					Call: &CallOption{
+						MaxRecvMsgSize: ptr.To(1),
+					},
+				},
+			},
+			expectCode: codes.ResourceExhausted,
+		},
+		"unsupported compression": {
+			handler: defaultHandler,
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: &RequestOptions{
+					Proto: &ProtoOption{
+						Files: []string{
+							"../../testdata/proto/test/test.proto",
+						},
+					},
+					Auth: &AuthOption{
+						Insecure: ptr.To(true),
+					},
+					Call: &CallOption{
+						Compression: "br",
+					},
+				},
+			},
+			expectError: `.options.call.compression: unsupported compression "br"`,
+		},
 		"no TLS certificate": {
 			handler:   defaultHandler,
 			enableTLS: true,
@@ -202,6 +433,115 @@ func TestProtoClient(t *testing.T) {
 			},
 			expectCode: codes.Unavailable,
 		},
+		"no client certificate": {
+			handler:           defaultHandler,
+			enableTLS:         true,
+			requireClientCert: true,
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: &RequestOptions{
+					Proto: &ProtoOption{
+						Files: []string{
+							"../../testdata/proto/test/test.proto",
+						},
+					},
+					Auth: &AuthOption{
+						TLS: &TLSOption{
+							Certificate: caCert,
+						},
+					},
+				},
+			},
+			expectCode: codes.Unavailable,
+		},
+		"invalid server name": {
+			handler:   defaultHandler,
+			enableTLS: true,
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: &RequestOptions{
+					Proto: &ProtoOption{
+						Files: []string{
+							"../../testdata/proto/test/test.proto",
+						},
+					},
+					Auth: &AuthOption{
+						TLS: &TLSOption{
+							Certificate: caCert,
+							ServerName:  "example.com",
+						},
+					},
+				},
+			},
+			expectCode: codes.Unavailable,
+		},
+		"no client key": {
+			handler:   defaultHandler,
+			enableTLS: true,
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: &RequestOptions{
+					Proto: &ProtoOption{
+						Files: []string{
+							"../../testdata/proto/test/test.proto",
+						},
+					},
+					Auth: &AuthOption{
+						TLS: &TLSOption{
+							Certificate:       caCert,
+							ClientCertificate: serverCert,
+						},
+					},
+				},
+			},
+			expectError: ".auth.tls.clientKey: client key must be specified with client certificate",
+		},
+		"client certificate not found": {
+			handler:   defaultHandler,
+			enableTLS: true,
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: &RequestOptions{
+					Proto: &ProtoOption{
+						Files: []string{
+							"../../testdata/proto/test/test.proto",
+						},
+					},
+					Auth: &AuthOption{
+						TLS: &TLSOption{
+							Certificate:       caCert,
+							ClientCertificate: "client.crt",
+							ClientKey:         "client.key",
+						},
+					},
+				},
+			},
+			expectError: ".auth.tls.clientCertificate: failed to load client certificate: open client.crt: no such file or directory",
+		},
 		"unnecessary TLS certificate": {
 			handler: defaultHandler,
 			request: &Request{
@@ -233,7 +573,24 @@ func TestProtoClient(t *testing.T) {
 			request: &Request{
 				Target: "",
 			},
-			expectError: ".target: target must be specified",
+			expectError: ".target: target must be specified",
+		},
+		"both message and message file": {
+			handler: defaultHandler,
+			request: &Request{
+				Target:      "{{vars.target}}",
+				Message:     yaml.MapSlice{},
+				MessageFile: "testdata/message.json",
+			},
+			expectError: ".messageFile: message and messageFile can't be specified at the same time",
+		},
+		"message file not found": {
+			handler: defaultHandler,
+			request: &Request{
+				Target:      "{{vars.target}}",
+				MessageFile: "testdata/not-found.json",
+			},
+			expectError: ".messageFile: failed to read file",
 		},
 		"target is invalid template": {
 			handler: defaultHandler,
@@ -333,6 +690,26 @@ func TestProtoClient(t *testing.T) {
 			},
 			expectError: ".options.proto: failed to compile: open foo.proto: no such file or directory",
 		},
+		"descriptor set not found": {
+			handler: defaultHandler,
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{},
+				Options: &RequestOptions{
+					Proto: &ProtoOption{
+						DescriptorSets: []string{
+							"foo.protoset",
+						},
+					},
+					Auth: &AuthOption{
+						Insecure: ptr.To(true),
+					},
+				},
+			},
+			expectError: ".options.proto.descriptorSets: failed to read descriptor set: open foo.protoset: no such file or directory",
+		},
 		"reflection service is not implemented": {
 			handler:           defaultHandler,
 			disableReflection: true,
@@ -438,6 +815,9 @@ func TestProtoClient(t *testing.T) {
 			if test.enableTLS {
 				opts = append(opts, testutil.EnableTLS(serverCert, serverKey))
 			}
+			if test.requireClientCert {
+				opts = append(opts, testutil.RequireClientCert(caCert))
+			}
 			target := testutil.StartTestGRPCServer(t, srv, opts...)
 			t.Cleanup(func() { _ = connPool.closeConnection(target) })
 			ctx := context.FromT(t).WithVars(map[string]any{
@@ -467,83 +847,639 @@ func TestProtoClient(t *testing.T) {
 			if serr.Code() != test.expectCode {
 				t.Fatalf("expected code is %s but got %s: %s", test.expectCode, serr.Code(), serr.Err())
 			}
-			if test.expectCode == codes.OK {
-				if diff := cmp.Diff(test.expectResponse, resp.Message, protocmp.Transform()); diff != "" {
-					t.Errorf("differs: (-want +got)\n%s", diff)
+			if test.expectCode != codes.OK {
+				return
+			}
+			if diff := cmp.Diff(test.expectResponse, resp.Message, protocmp.Transform()); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+			if !test.enableTLS {
+				if resp.TLS != nil {
+					t.Errorf("expect no TLS state but got %+v", resp.TLS)
 				}
+				return
+			}
+			if resp.TLS == nil {
+				t.Fatal("no TLS state")
+			}
+			if got, expect := resp.TLS.ALPN, "h2"; got != expect {
+				t.Errorf("expect ALPN %q but got %q", expect, got)
+			}
+			if len(resp.TLS.Certificates) == 0 {
+				t.Fatal("no peer certificates")
+			}
+			if diff := cmp.Diff([]string{"localhost"}, resp.TLS.Certificates[0].DNSNames); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
 			}
 		})
 	}
 }
 
-func generateCert(t *testing.T) (string, string, string) {
-	t.Helper()
-	tmp := t.TempDir()
-	now := time.Now()
-	validityPeriod := time.Hour
-
-	ca := &x509.Certificate{
-		SerialNumber:          big.NewInt(1),
-		NotBefore:             now,
-		NotAfter:              now.Add(validityPeriod),
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
-		BasicConstraintsValid: true,
-		IsCA:                  true,
-		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+func TestProtoClient_ReflectionCache(t *testing.T) {
+	srv := testutil.TestGRPCServerFunc(func(ctx gocontext.Context, req *testpb.EchoRequest) (*testpb.EchoResponse, error) {
+		return &testpb.EchoResponse{
+			MessageId:   req.GetMessageId(),
+			MessageBody: req.GetMessageBody(),
+		}, nil
+	})
+	target := testutil.StartTestGRPCServer(t, srv, testutil.EnableReflection())
+	t.Cleanup(func() { _ = connPool.closeConnection(target) })
+	ctx := context.FromT(t).WithVars(map[string]any{
+		"target": target,
+	})
+	auth := &AuthOption{
+		Insecure: ptr.To(true),
 	}
-	caPub, caPriv, err := ed25519.GenerateKey(rand.Reader)
-	if err != nil {
-		t.Fatalf("failed to generate private key: %s", err)
+	req := &Request{
+		Target:  "{{vars.target}}",
+		Service: testpb.Test_ServiceDesc.ServiceName,
+		Method:  "Echo",
+		Message: yaml.MapSlice{
+			yaml.MapItem{Key: "messageId", Value: "1"},
+		},
+		Options: &RequestOptions{
+			Auth: auth,
+		},
 	}
-	caBytes, err := x509.CreateCertificate(rand.Reader, ca, ca, caPub, caPriv)
+	k, err := connKey(target, auth, "")
 	if err != nil {
-		t.Fatalf("failed to create certificate: %s", err)
+		t.Fatal(err)
 	}
-	caPEM, err := os.Create(filepath.Join(tmp, "ca.crt"))
-	if err != nil {
-		t.Fatalf("failed to create ca.crt: %s", err)
+
+	for range 2 {
+		if _, _, err := req.Invoke(ctx); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if _, ok := connPool.getService(k, protoreflect.FullName(testpb.Test_ServiceDesc.ServiceName)); !ok {
+			t.Fatal("service descriptor is not cached")
+		}
+	}
+
+	if err := grpcProtocol.Close(); err != nil {
+		t.Fatalf("failed to close: %s", err)
+	}
+	if _, ok := connPool.getService(k, protoreflect.FullName(testpb.Test_ServiceDesc.ServiceName)); ok {
+		t.Fatal("service descriptor cache is not cleared")
 	}
-	defer caPEM.Close()
-	if err := pem.Encode(caPEM, &pem.Block{Type: "CERTIFICATE", Bytes: caBytes}); err != nil {
-		t.Fatalf("failed to encode PEM: %s", err)
+	if got := len(connPool.conns); got != 0 {
+		t.Fatalf("expect no connections but got %d", got)
 	}
 
-	cert := &x509.Certificate{
-		SerialNumber:          big.NewInt(1),
-		NotBefore:             now,
-		NotAfter:              now.Add(validityPeriod),
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
-		BasicConstraintsValid: true,
-		KeyUsage:              x509.KeyUsageDigitalSignature,
-		DNSNames:              []string{"localhost"},
-		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1"), net.IPv6loopback},
+	// reconnect after closing
+	if _, _, err := req.Invoke(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
 	}
-	certPub, certPriv, err := ed25519.GenerateKey(rand.Reader)
-	if err != nil {
-		t.Fatalf("failed to generate private key: %s", err)
+}
+
+func TestProtoClient_Resolve(t *testing.T) {
+	caCert, serverCert, serverKey := testutil.GenerateCert(t)
+	srv := testutil.TestGRPCServerFunc(func(ctx gocontext.Context, req *testpb.EchoRequest) (*testpb.EchoResponse, error) {
+		return &testpb.EchoResponse{
+			MessageId:   req.GetMessageId(),
+			MessageBody: req.GetMessageBody(),
+		}, nil
+	})
+	addr := testutil.StartTestGRPCServer(t, srv, testutil.EnableReflection())
+	tlsAddr := testutil.StartTestGRPCServer(t, srv, testutil.EnableReflection(), testutil.EnableTLS(serverCert, serverKey))
+
+	tests := map[string]struct {
+		target    string
+		resolver  netutil.Resolver
+		transport string
+		auth      *AuthOption
+		expect    string
+	}{
+		"insecure": {
+			target:   "grpc.example.com:50051",
+			resolver: netutil.Resolver{"grpc.example.com:50051": addr},
+			auth:     &AuthOption{Insecure: ptr.To(true)},
+			expect:   addr,
+		},
+		"dns scheme": {
+			target:   "dns:///grpc.example.com:50051",
+			resolver: netutil.Resolver{"grpc.example.com:50051": addr},
+			auth:     &AuthOption{Insecure: ptr.To(true)},
+			expect:   addr,
+		},
+		"TLS": {
+			// the certificate is issued for localhost
+			target:   "localhost",
+			resolver: netutil.Resolver{"localhost:443": tlsAddr},
+			auth: &AuthOption{
+				TLS: &TLSOption{
+					Certificate: caCert,
+				},
+			},
+			expect: tlsAddr,
+		},
+		"not matched": {
+			target:   addr,
+			resolver: netutil.Resolver{"grpc.example.com:50051": "127.0.0.1:1"},
+			auth:     &AuthOption{Insecure: ptr.To(true)},
+		},
 	}
-	certBytes, err := x509.CreateCertificate(rand.Reader, cert, ca, certPub, caPriv)
-	if err != nil {
-		t.Fatalf("failed to create certificate: %s", err)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Cleanup(func() { _ = connPool.closeConnection(test.target) })
+			req := &Request{
+				Target:  test.target,
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+				},
+				Options: &RequestOptions{
+					Auth: test.auth,
+				},
+			}
+			ctx, result, err := req.Invoke(context.FromT(t).WithResolver(test.resolver))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			resp, ok := result.(*response)
+			if !ok {
+				t.Fatalf("failed to type conversion from %s to *response", reflect.TypeOf(result))
+			}
+			if got := resp.Status.Code(); got != codes.OK {
+				t.Fatalf("expected code is %s but got %s: %s", codes.OK, got, resp.Status.Err())
+			}
+			dump, ok := ctx.Request().(*RequestExtractor)
+			if !ok {
+				t.Fatalf("failed to type conversion from %s to *RequestExtractor", reflect.TypeOf(ctx.Request()))
+			}
+			if got := dump.ResolvedAddress; got != test.expect {
+				t.Errorf("expect resolved address %q but got %q", test.expect, got)
+			}
+		})
 	}
-	certPEM, err := os.Create(filepath.Join(tmp, "server.crt"))
-	if err != nil {
-		t.Fatalf("failed to create server.crt: %s", err)
+}
+
+func TestProtoClient_Resolve_ConnectionKey(t *testing.T) {
+	newServer := func(body string) string {
+		srv := testutil.TestGRPCServerFunc(func(ctx gocontext.Context, req *testpb.EchoRequest) (*testpb.EchoResponse, error) {
+			return &testpb.EchoResponse{
+				MessageId:   req.GetMessageId(),
+				MessageBody: body,
+			}, nil
+		})
+		return testutil.StartTestGRPCServer(t, srv, testutil.EnableReflection())
 	}
-	if err := pem.Encode(certPEM, &pem.Block{Type: "CERTIFICATE", Bytes: certBytes}); err != nil {
-		t.Fatalf("failed to encode PEM: %s", err)
+	addrA, addrB := newServer("a"), newServer("b")
+	target := "grpc.example.com:50051"
+	t.Cleanup(func() { _ = connPool.closeConnection(target) })
+
+	// the connection must not be shared between the different resolve configurations
+	for _, test := range []struct {
+		addr   string
+		expect string
+	}{
+		{addr: addrA, expect: "a"},
+		{addr: addrB, expect: "b"},
+	} {
+		req := &Request{
+			Target:  target,
+			Service: testpb.Test_ServiceDesc.ServiceName,
+			Method:  "Echo",
+			Message: yaml.MapSlice{
+				yaml.MapItem{Key: "messageId", Value: "1"},
+			},
+			Options: &RequestOptions{
+				Auth: &AuthOption{Insecure: ptr.To(true)},
+			},
+		}
+		ctx := context.FromT(t).WithResolver(netutil.Resolver{target: test.addr})
+		_, result, err := req.Invoke(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		resp, ok := result.(*response)
+		if !ok {
+			t.Fatalf("failed to type conversion from %s to *response", reflect.TypeOf(result))
+		}
+		expect := &testpb.EchoResponse{
+			MessageId:   "1",
+			MessageBody: test.expect,
+		}
+		if diff := cmp.Diff(expect, resp.Message, protocmp.Transform()); diff != "" {
+			t.Errorf("differs (-want +got):\n%s", diff)
+		}
 	}
-	certKeyPEM, err := os.OpenFile(filepath.Join(tmp, "server.key"), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
-	if err != nil {
-		t.Fatalf("failed to create server.key: %s", err)
+}
+
+func TestProtoClient_Save(t *testing.T) {
+	srv := testutil.TestGRPCServerFunc(func(ctx gocontext.Context, req *testpb.EchoRequest) (*testpb.EchoResponse, error) {
+		return &testpb.EchoResponse{
+			MessageId:   req.GetMessageId(),
+			MessageBody: req.GetMessageBody(),
+		}, nil
+	})
+	target := testutil.StartTestGRPCServer(t, srv, testutil.EnableReflection(), testutil.WithStreamTestServer(&streamTestServer{}))
+	t.Cleanup(func() { _ = connPool.closeConnection(target) })
+
+	tests := map[string]struct {
+		service        string
+		method         string
+		save           *artifact.SaveOption
+		expectMessages []*testpb.EchoResponse
+		expectValue    string
+	}{
+		"unary": {
+			service: testpb.Test_ServiceDesc.ServiceName,
+			method:  "Echo",
+			save: &artifact.SaveOption{
+				Path: "response.bin",
+			},
+			expectMessages: []*testpb.EchoResponse{
+				{MessageId: "1", MessageBody: "hello world"},
+			},
+		},
+		"server streaming": {
+			service: testpb.StreamTest_ServiceDesc.ServiceName,
+			method:  "ServerStreamingEcho",
+			save: &artifact.SaveOption{
+				Path: "response.bin",
+			},
+			expectMessages: []*testpb.EchoResponse{
+				{MessageId: "1-0", MessageBody: "hello"},
+				{MessageId: "1-1", MessageBody: "world"},
+			},
+		},
+		"queried value": {
+			service: testpb.Test_ServiceDesc.ServiceName,
+			method:  "Echo",
+			save: &artifact.SaveOption{
+				Path:  "response.txt",
+				Value: "{{response.message.messageBody}}",
+			},
+			expectValue: "hello world",
+		},
 	}
-	privBytes, err := x509.MarshalPKCS8PrivateKey(certPriv)
-	if err != nil {
-		t.Fatalf("unable to marshal private key: %s", err)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			ctx := context.FromT(t).WithArtifactsDir(dir)
+			req := &Request{
+				Target:  target,
+				Service: test.service,
+				Method:  test.method,
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello world"},
+				},
+				Options: &RequestOptions{
+					Auth: &AuthOption{
+						Insecure: ptr.To(true),
+					},
+				},
+				Save: test.save,
+			}
+			ctx, _, err := req.Invoke(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			path, err := ctx.ExecuteTemplate("{{response.artifact}}")
+			if err != nil {
+				t.Fatalf("failed to execute template: %s", err)
+			}
+			if got, expect := path, filepath.Join(dir, test.save.Path); got != expect {
+				t.Errorf("expect artifact %q but got %q", expect, got)
+			}
+			b, err := os.ReadFile(filepath.Join(dir, test.save.Path))
+			if err != nil {
+				t.Fatalf("failed to read file: %s", err)
+			}
+			if test.expectMessages == nil {
+				if got, expect := string(b), test.expectValue; got != expect {
+					t.Errorf("expect %q but got %q", expect, got)
+				}
+				return
+			}
+			var msgs []*testpb.EchoResponse
+			if len(test.expectMessages) == 1 {
+				var msg testpb.EchoResponse
+				if err := proto.Unmarshal(b, &msg); err != nil {
+					t.Fatalf("failed to unmarshal: %s", err)
+				}
+				msgs = append(msgs, &msg)
+			} else {
+				r := bytes.NewReader(b)
+				for r.Len() > 0 {
+					var msg testpb.EchoResponse
+					if err := protodelim.UnmarshalFrom(r, &msg); err != nil {
+						t.Fatalf("failed to unmarshal: %s", err)
+					}
+					msgs = append(msgs, &msg)
+				}
+			}
+			if diff := cmp.Diff(test.expectMessages, msgs, protocmp.Transform()); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
 	}
-	if err := pem.Encode(certKeyPEM, &pem.Block{Type: "PRIVATE KEY", Bytes: privBytes}); err != nil {
-		t.Fatalf("failed to encode PEM: %s", err)
+}
+
+func TestProtoClient_Stream(t *testing.T) {
+	streamOpts := func(useReflection bool) *RequestOptions {
+		opts := &RequestOptions{
+			Auth: &AuthOption{
+				Insecure: ptr.To(true),
+			},
+		}
+		if !useReflection {
+			opts.Proto = &ProtoOption{
+				Files: []string{
+					"../../testdata/proto/test/test.proto",
+				},
+			}
+		}
+		return opts
+	}
+	tests := map[string]struct {
+		request        *Request
+		expectCode     codes.Code
+		expectMessage  *testpb.EchoResponse
+		expectMessages []*testpb.EchoResponse
+		expectTrailer  metadata.MD
+		expectError    string
+	}{
+		"server streaming": {
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.StreamTest_ServiceDesc.ServiceName,
+				Method:  "ServerStreamingEcho",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello world"},
+				},
+				Options: streamOpts(false),
+			},
+			expectCode: codes.OK,
+			expectMessages: []*testpb.EchoResponse{
+				{MessageId: "1-0", MessageBody: "hello"},
+				{MessageId: "1-1", MessageBody: "world"},
+			},
+		},
+		"server streaming (reflection)": {
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.StreamTest_ServiceDesc.ServiceName,
+				Method:  "ServerStreamingEcho",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello world"},
+				},
+				Options: streamOpts(true),
+			},
+			expectCode: codes.OK,
+			expectMessages: []*testpb.EchoResponse{
+				{MessageId: "1-0", MessageBody: "hello"},
+				{MessageId: "1-1", MessageBody: "world"},
+			},
+		},
+		"server streaming returns error": {
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.StreamTest_ServiceDesc.ServiceName,
+				Method:  "ServerStreamingEcho",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+				},
+				Options: streamOpts(false),
+			},
+			expectCode: codes.InvalidArgument,
+		},
+		"client streaming": {
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.StreamTest_ServiceDesc.ServiceName,
+				Method:  "ClientStreamingEcho",
+				Messages: []any{
+					yaml.MapSlice{
+						yaml.MapItem{Key: "messageId", Value: "1"},
+						yaml.MapItem{Key: "messageBody", Value: "hello"},
+					},
+					yaml.MapSlice{
+						yaml.MapItem{Key: "messageId", Value: "2"},
+						yaml.MapItem{Key: "messageBody", Value: "{{vars.body}}"},
+					},
+				},
+				Options: streamOpts(false),
+			},
+			expectCode: codes.OK,
+			expectMessage: &testpb.EchoResponse{
+				MessageId:   "2",
+				MessageBody: "hello world",
+			},
+		},
+		"bidirectional streaming": {
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.StreamTest_ServiceDesc.ServiceName,
+				Method:  "BidiStreamingEcho",
+				Messages: []any{
+					yaml.MapSlice{
+						yaml.MapItem{Key: "messageId", Value: "1"},
+						yaml.MapItem{Key: "messageBody", Value: "hello"},
+					},
+					yaml.MapSlice{
+						yaml.MapItem{Key: "messageId", Value: "2"},
+						yaml.MapItem{Key: "messageBody", Value: "world"},
+					},
+				},
+				Options: streamOpts(true),
+			},
+			expectCode: codes.OK,
+			expectMessages: []*testpb.EchoResponse{
+				{MessageId: "1", MessageBody: "hello"},
+				{MessageId: "2", MessageBody: "world"},
+			},
+			expectTrailer: metadata.MD{
+				"count": []string{"2"},
+			},
+		},
+		"bidirectional streaming without messages": {
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.StreamTest_ServiceDesc.ServiceName,
+				Method:  "BidiStreamingEcho",
+				Options: streamOpts(false),
+			},
+			expectCode: codes.OK,
+			expectTrailer: metadata.MD{
+				"count": []string{"0"},
+			},
+		},
+
+		"message for client streaming": {
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.StreamTest_ServiceDesc.ServiceName,
+				Method:  "ClientStreamingEcho",
+				Message: yaml.MapSlice{},
+				Options: streamOpts(false),
+			},
+			expectError: ".message: message can't be used for client-streaming RPC, use messages instead",
+		},
+		"messages for server streaming": {
+			request: &Request{
+				Target:   "{{vars.target}}",
+				Service:  testpb.StreamTest_ServiceDesc.ServiceName,
+				Method:   "ServerStreamingEcho",
+				Messages: []any{yaml.MapSlice{}},
+				Options:  streamOpts(false),
+			},
+			expectError: ".messages: messages can be used only for client-streaming or bidirectional-streaming RPC",
+		},
+		"messages for unary": {
+			request: &Request{
+				Target:   "{{vars.target}}",
+				Service:  testpb.Test_ServiceDesc.ServiceName,
+				Method:   "Echo",
+				Messages: []any{yaml.MapSlice{}},
+				Options:  streamOpts(false),
+			},
+			expectError: ".messages: messages can be used only for client-streaming or bidirectional-streaming RPC",
+		},
+		"invalid request message": {
+			request: &Request{
+				Target:  "{{vars.target}}",
+				Service: testpb.StreamTest_ServiceDesc.ServiceName,
+				Method:  "BidiStreamingEcho",
+				Messages: []any{
+					yaml.MapSlice{},
+					yaml.MapSlice{
+						yaml.MapItem{Key: "messageId", Value: 1},
+					},
+				},
+				Options: streamOpts(false),
+			},
+			expectError: ".messages[1]: failed to build request message",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			target := testutil.StartTestGRPCServer(t, nil, testutil.EnableReflection(), testutil.WithStreamTestServer(&streamTestServer{}))
+			t.Cleanup(func() { _ = connPool.closeConnection(target) })
+			ctx := context.FromT(t).WithVars(map[string]any{
+				"target": target,
+				"body":   "world",
+			})
+
+			_, result, err := test.request.Invoke(ctx)
+			if test.expectError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			} else {
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if got := err.Error(); !strings.Contains(got, test.expectError) {
+					t.Fatalf("expected error is %q but got %q", test.expectError, got)
+				}
+				return
+			}
+
+			resp, ok := result.(*response)
+			if !ok {
+				t.Fatalf("failed to type conversion from %s to *response", reflect.TypeOf(result))
+			}
+			if got := resp.Status.Code(); got != test.expectCode {
+				t.Fatalf("expected code is %s but got %s: %s", test.expectCode, got, resp.Status.Err())
+			}
+			if test.expectMessage != nil {
+				if diff := cmp.Diff(test.expectMessage, resp.Message, protocmp.Transform()); diff != "" {
+					t.Errorf("differs: (-want +got)\n%s", diff)
+				}
+			} else if resp.Message != nil {
+				t.Errorf("unexpected message: %v", resp.Message)
+			}
+			expectMsgs := make([]proto.Message, len(test.expectMessages))
+			for i, m := range test.expectMessages {
+				expectMsgs[i] = m
+			}
+			gotMsgs := make([]proto.Message, len(resp.Messages))
+			for i, m := range resp.Messages {
+				gotMsgs[i] = m.Message
+			}
+			if diff := cmp.Diff(expectMsgs, gotMsgs, protocmp.Transform()); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+			if test.expectTrailer != nil {
+				if resp.Trailer == nil {
+					t.Fatal("no trailer")
+				}
+				for k, v := range test.expectTrailer {
+					if diff := cmp.Diff(v, metadata.MD(*resp.Trailer).Get(k)); diff != "" {
+						t.Errorf("trailer %q differs: (-want +got)\n%s", k, diff)
+					}
+				}
+			}
+		})
+	}
+}
+
+type streamTestServer struct {
+	testpb.UnimplementedStreamTestServer
+}
+
+func (s *streamTestServer) ServerStreamingEcho(req *testpb.EchoRequest, stream testpb.StreamTest_ServerStreamingEchoServer) error {
+	words := strings.Fields(req.GetMessageBody())
+	if len(words) == 0 {
+		return status.Error(codes.InvalidArgument, "empty message body")
+	}
+	for i, w := range words {
+		if err := stream.Send(&testpb.EchoResponse{
+			MessageId:   fmt.Sprintf("%s-%d", req.GetMessageId(), i),
+			MessageBody: w,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *streamTestServer) ClientStreamingEcho(stream testpb.StreamTest_ClientStreamingEchoServer) error {
+	var (
+		id    string
+		words []string
+	)
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		id = req.GetMessageId()
+		words = append(words, req.GetMessageBody())
 	}
+	return stream.SendAndClose(&testpb.EchoResponse{
+		MessageId:   id,
+		MessageBody: strings.Join(words, " "),
+	})
+}
 
-	return caPEM.Name(), certPEM.Name(), certKeyPEM.Name()
+func (s *streamTestServer) BidiStreamingEcho(stream testpb.StreamTest_BidiStreamingEchoServer) error {
+	var count int
+	defer func() {
+		stream.SetTrailer(metadata.Pairs("count", fmt.Sprint(count)))
+	}()
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		count++
+		if err := stream.Send(&testpb.EchoResponse{
+			MessageId:   req.GetMessageId(),
+			MessageBody: req.GetMessageBody(),
+		}); err != nil {
+			return err
+		}
+	}
 }
@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	gocontext "context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/errors"
+)
+
+// invokeStream calls a streaming RPC.
+// The request messages are sent in order, and then the client half-closes the stream and receives the response messages until the server closes it.
+// For bidirectional streaming RPCs over native gRPC, the request messages are sent while receiving the response messages.
+func (r *Request) invokeStream(ctx *context.Context, reqCtx gocontext.Context, client streamServiceClient, opts *RequestOptions, callOpts []grpc.CallOption) (*context.Context, interface{}, error) {
+	var (
+		reqMsg  proto.Message
+		reqMsgs []proto.Message
+		err     error
+	)
+	if client.isClientStreaming() {
+		if r.Message != nil {
+			return ctx, nil, errors.ErrorPath("message", "message can't be used for client-streaming RPC, use messages instead")
+		}
+		reqMsgs, err = client.buildRequestMessages(ctx)
+		if err != nil {
+			return ctx, nil, err
+		}
+	} else {
+		if len(r.Messages) > 0 {
+			return ctx, nil, errors.ErrorPath("messages", "messages can be used only for client-streaming or bidirectional-streaming RPC")
+		}
+		reqMsg, err = client.buildRequestMessage(ctx)
+		if err != nil {
+			return ctx, nil, err
+		}
+		reqMsgs = []proto.Message{reqMsg}
+	}
+	if reqMsg != nil {
+		ctx = r.dumpRequest(ctx, opts, client, reqMsg, nil)
+	} else {
+		ctx = r.dumpRequest(ctx, opts, client, nil, reqMsgs)
+	}
+
+	var (
+		header, trailer metadata.MD
+		p               peer.Peer
+	)
+	callOpts = append(callOpts,
+		grpc.Header(&header),
+		grpc.Trailer(&trailer),
+		grpc.Peer(&p),
+	)
+	respMsgs, sts, err := client.invokeStream(reqCtx, reqMsgs, callOpts...)
+	if err != nil {
+		return ctx, nil, err
+	}
+	resp := &response{
+		Status: &responseStatus{
+			status.New(codes.OK, ""),
+		},
+	}
+	if sts != nil {
+		resp.Status = &responseStatus{sts}
+	}
+	if client.isServerStreaming() {
+		resp.Messages = make([]*ProtoMessageYAMLMarshaler, len(respMsgs))
+		for i, msg := range respMsgs {
+			resp.Messages[i] = &ProtoMessageYAMLMarshaler{Message: msg, types: typeResolver(client)}
+		}
+	} else if len(respMsgs) > 0 {
+		resp.Message = &ProtoMessageYAMLMarshaler{Message: respMsgs[0], types: typeResolver(client)}
+	}
+	ctx = r.dumpResponse(ctx, resp, header, trailer, &p)
+	if err := r.saveResponse(ctx, resp); err != nil {
+		return ctx, nil, err
+	}
+
+	return ctx, resp, nil
+}
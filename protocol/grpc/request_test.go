@@ -2,15 +2,19 @@ package grpc
 
 import (
 	"bytes"
+	gocontext "context"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/dynamicpb"
 
 	"github.com/goccy/go-yaml"
 	"github.com/golang/mock/gomock"
@@ -21,10 +25,13 @@ import (
 
 	"github.com/scenarigo/scenarigo/context"
 	"github.com/scenarigo/scenarigo/internal/mockutil"
+	"github.com/scenarigo/scenarigo/internal/ptr"
 	"github.com/scenarigo/scenarigo/internal/queryutil"
 	"github.com/scenarigo/scenarigo/internal/testutil"
 	"github.com/scenarigo/scenarigo/internal/yamlutil"
+	grpcproto "github.com/scenarigo/scenarigo/protocol/grpc/proto"
 	"github.com/scenarigo/scenarigo/reporter"
+	"github.com/scenarigo/scenarigo/schema"
 	testpb "github.com/scenarigo/scenarigo/testdata/gen/pb/test"
 )
 
@@ -34,11 +41,9 @@ func TestRequestExtractor(t *testing.T) {
 		Metadata: metadata.MD{
 			"foo": []string{"FOO"},
 		},
-		Message: &ProtoMessageYAMLMarshaler{
-			&testpb.EchoRequest{
-				MessageBody: "hey",
-			},
-		},
+		Message: &ProtoMessageYAMLMarshaler{Message: &testpb.EchoRequest{
+			MessageBody: "hey",
+		}},
 	}
 	tests := map[string]struct {
 		query       string
@@ -101,7 +106,7 @@ func TestResponseExtractor(t *testing.T) {
 		Trailer: &yamlutil.MDMarshaler{
 			"bar": []string{"BAR"},
 		},
-		Message: &ProtoMessageYAMLMarshaler{&testpb.EchoResponse{
+		Message: &ProtoMessageYAMLMarshaler{Message: &testpb.EchoResponse{
 			MessageBody: "hey",
 		}},
 	}
@@ -204,7 +209,7 @@ func TestRequest_Invoke(t *testing.T) {
 				dumpReq := &request{
 					Method:   r.Method,
 					Metadata: r.Metadata,
-					Message:  &ProtoMessageYAMLMarshaler{req},
+					Message:  &ProtoMessageYAMLMarshaler{Message: req},
 				}
 				if diff := cmp.Diff((*RequestExtractor)(dumpReq), ctx.Request(), protocmp.Transform()); diff != "" {
 					t.Errorf("differs: (-want +got)\n%s", diff)
@@ -252,7 +257,7 @@ func TestRequest_Invoke(t *testing.T) {
 				dumpReq := &request{
 					Method:   r.Method,
 					Metadata: r.Metadata,
-					Message:  &ProtoMessageYAMLMarshaler{req},
+					Message:  &ProtoMessageYAMLMarshaler{Message: req},
 				}
 				if diff := cmp.Diff((*RequestExtractor)(dumpReq), ctx.Request(), protocmp.Transform()); diff != "" {
 					t.Errorf("differs: (-want +got)\n%s", diff)
@@ -341,8 +346,9 @@ func TestRequest_Invoke_Log(t *testing.T) {
 	resp := &testpb.EchoResponse{MessageId: "1", MessageBody: "hello"}
 
 	tests := map[string]struct {
-		err    error
-		expect string
+		options *RequestOptions
+		err     error
+		expect  string
 	}{
 		"success": {
 			expect: `
@@ -364,6 +370,38 @@ func TestRequest_Invoke_Log(t *testing.T) {
             messageBody: hello
 PASS
 ok  	test.yaml	0.000s
+`,
+		},
+		"with call options": {
+			options: &RequestOptions{
+				Call: &CallOption{
+					Timeout:     ptr.To(schema.Duration(3 * time.Second)),
+					Compression: "gzip",
+				},
+			},
+			expect: `
+=== RUN   test.yaml
+--- PASS: test.yaml (0.00s)
+        request:
+          method: Echo
+          metadata:
+            version:
+            - 1.0.0
+          message:
+            messageId: "1"
+            messageBody: hello
+          options:
+            call:
+              timeout: 3s
+              compression: gzip
+        response:
+          status:
+            code: OK
+          message:
+            messageId: "1"
+            messageBody: hello
+PASS
+ok  	test.yaml	0.000s
 `,
 		},
 		"failure": {
@@ -423,6 +461,7 @@ ok  	test.yaml	0.000s
 					yaml.MapItem{Key: "messageId", Value: "1"},
 					yaml.MapItem{Key: "messageBody", Value: "hello"},
 				},
+				Options: test.options,
 			}
 
 			var b bytes.Buffer
@@ -445,6 +484,132 @@ ok  	test.yaml	0.000s
 	}
 }
 
+func TestRequest_Invoke_TLSPaths(t *testing.T) {
+	tests := map[string]struct {
+		option      string
+		auth        *AuthOption
+		expectError string
+	}{
+		"step certificate": {
+			auth: &AuthOption{
+				TLS: &TLSOption{
+					Certificate: "ca.crt",
+				},
+			},
+			expectError: "open /path/to/scenarios/ca.crt: no such file or directory",
+		},
+		"step client certificate": {
+			auth: &AuthOption{
+				TLS: &TLSOption{
+					ClientCertificate: "client.crt",
+					ClientKey:         "client.key",
+				},
+			},
+			expectError: "open /path/to/scenarios/client.crt: no such file or directory",
+		},
+		"config certificate": {
+			option: `
+request:
+  auth:
+    tls:
+      certificate: ca.crt
+`,
+			expectError: "open /path/to/root/ca.crt: no such file or directory",
+		},
+		"config client certificate": {
+			option: `
+request:
+  auth:
+    tls:
+      clientCertificate: client.crt
+      clientKey: client.key
+`,
+			expectError: "open /path/to/root/client.crt: no such file or directory",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := grpcProtocol.UnmarshalOptionWithRootDir([]byte(test.option), "/path/to/root"); err != nil {
+				t.Fatalf("failed to unmarshal option: %s", err)
+			}
+			t.Cleanup(func() {
+				grpcProtocol.m.Lock()
+				defer grpcProtocol.m.Unlock()
+				grpcProtocol.option = Option{}
+			})
+			req := &Request{
+				Target:  "localhost:50051",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Options: &RequestOptions{
+					Auth: test.auth,
+				},
+			}
+			ctx := context.FromT(t).WithScenarioFilepath("/path/to/scenarios/test.yaml")
+			_, _, err := req.Invoke(ctx)
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if !strings.Contains(err.Error(), test.expectError) {
+				t.Errorf("expect error %q but got %q", test.expectError, err)
+			}
+		})
+	}
+}
+
+func TestRequest_Invoke_ProtoPaths(t *testing.T) {
+	srv := testutil.TestGRPCServerFunc(func(ctx gocontext.Context, req *testpb.EchoRequest) (*testpb.EchoResponse, error) {
+		return &testpb.EchoResponse{
+			MessageId:   req.GetMessageId(),
+			MessageBody: req.GetMessageBody(),
+		}, nil
+	})
+	target := testutil.StartTestGRPCServer(t, srv)
+	root, err := filepath.Abs("../../testdata/gen/pb")
+	if err != nil {
+		t.Fatalf("failed to get absolute path: %s", err)
+	}
+	// the descriptor set is not found from the scenario directory
+	if err := grpcProtocol.UnmarshalOptionWithRootDir([]byte(`
+request:
+  proto:
+    descriptorSets:
+    - test/test.protoset
+`), root); err != nil {
+		t.Fatalf("failed to unmarshal option: %s", err)
+	}
+	t.Cleanup(func() {
+		grpcProtocol.m.Lock()
+		defer grpcProtocol.m.Unlock()
+		grpcProtocol.option = Option{}
+	})
+	req := &Request{
+		Target:  target,
+		Service: testpb.Test_ServiceDesc.ServiceName,
+		Method:  "Echo",
+		Message: yaml.MapSlice{
+			yaml.MapItem{Key: "messageId", Value: "1"},
+		},
+		Options: &RequestOptions{
+			Auth: &AuthOption{
+				Insecure: ptr.To(true),
+			},
+		},
+	}
+	ctx := context.FromT(t).WithScenarioFilepath(filepath.Join(t.TempDir(), "test.yaml"))
+	_, res, err := req.Invoke(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp, ok := res.(*response)
+	if !ok {
+		t.Fatalf("failed to type conversion from %s to *response", reflect.TypeOf(res))
+	}
+	if diff := cmp.Diff(&testpb.EchoResponse{MessageId: "1"}, resp.Message, protocmp.Transform()); diff != "" {
+		t.Errorf("differs (-want +got):\n%s", diff)
+	}
+}
+
 func TestBuildRequestBody(t *testing.T) {
 	tests := map[string]struct {
 		vars   interface{}
@@ -501,7 +666,7 @@ func TestBuildRequestBody(t *testing.T) {
 				ctx = ctx.WithVars(tc.vars)
 			}
 			var req testpb.EchoRequest
-			err := buildRequestMsg(ctx, &req, tc.src)
+			err := buildRequestMsg(ctx, &req, tc.src, nil)
 			if err != nil {
 				if !tc.error {
 					t.Fatalf("unexpected error: %s", err)
@@ -518,6 +683,90 @@ func TestBuildRequestBody(t *testing.T) {
 	}
 }
 
+func TestBuildRequestMsg_WellKnownTypes(t *testing.T) {
+	fds, err := grpcproto.NewCompiler([]string{"./proto/testdata"}).Compile(gocontext.Background(), []string{"wkt.proto"})
+	if err != nil {
+		t.Fatalf("failed to compile: %s", err)
+	}
+	md := fds.Files()[0].Messages().ByName("Message")
+	msg := dynamicpb.NewMessage(md)
+	src := yaml.MapSlice{
+		yaml.MapItem{Key: "timestamp", Value: "2024-01-02T03:04:05Z"},
+		yaml.MapItem{Key: "duration", Value: "1.5s"},
+		yaml.MapItem{Key: "stringValue", Value: "hello"},
+		yaml.MapItem{Key: "int64Value", Value: 1},
+		yaml.MapItem{Key: "any", Value: yaml.MapSlice{
+			yaml.MapItem{Key: "@type", Value: "type.googleapis.com/scenarigo.testdata.wkt.Detail"},
+			yaml.MapItem{Key: "name", Value: "{{vars.name}}"},
+		}},
+	}
+	ctx := context.FromT(t).WithVars(map[string]string{"name": "foo"})
+	if err := buildRequestMsg(ctx, msg, src, nil); err != nil {
+		t.Fatalf("failed to build message: %s", err)
+	}
+
+	b, err := (&ProtoMessageYAMLMarshaler{Message: msg}).MarshalYAML(gocontext.Background())
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+	expect := `timestamp: "2024-01-02T03:04:05Z"
+duration: 1.500s
+stringValue: hello
+int64Value: "1"
+any:
+  "@type": type.googleapis.com/scenarigo.testdata.wkt.Detail
+  name: foo
+`
+	if diff := cmp.Diff(expect, string(b)); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func TestProtoMessageYAMLMarshaler_LoadedTypes(t *testing.T) {
+	// extra.proto is loaded but not imported by wkt.proto
+	fds, err := grpcproto.NewCompiler([]string{"./proto/testdata"}).Compile(gocontext.Background(), []string{"wkt.proto", "extra.proto"})
+	if err != nil {
+		t.Fatalf("failed to compile: %s", err)
+	}
+	types := grpcproto.NewTypeResolver(fds.Files()[0], fds.Files()[1])
+	md := fds.Files()[0].Messages().ByName("Message")
+	msg := dynamicpb.NewMessage(md)
+	src := yaml.MapSlice{
+		yaml.MapItem{Key: "any", Value: yaml.MapSlice{
+			yaml.MapItem{Key: "@type", Value: "type.googleapis.com/scenarigo.testdata.extra.Extra"},
+			yaml.MapItem{Key: "name", Value: "foo"},
+		}},
+	}
+	if err := buildRequestMsg(context.FromT(t), msg, src, types); err != nil {
+		t.Fatalf("failed to build message: %s", err)
+	}
+	m := &ProtoMessageYAMLMarshaler{Message: msg, types: types}
+
+	t.Run("marshal", func(t *testing.T) {
+		b, err := m.MarshalYAML(gocontext.Background())
+		if err != nil {
+			t.Fatalf("failed to marshal: %s", err)
+		}
+		expect := `any:
+  "@type": type.googleapis.com/scenarigo.testdata.extra.Extra
+  name: foo
+`
+		if diff := cmp.Diff(expect, string(b)); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+
+	t.Run("extract", func(t *testing.T) {
+		v, err := queryutil.New().Key("message").Key("any").Key("name").Extract(ResponseExtractor{Message: m})
+		if err != nil {
+			t.Fatalf("failed to extract: %s", err)
+		}
+		if got, expect := v, "foo"; got != expect {
+			t.Errorf("expect %v but got %v", expect, got)
+		}
+	})
+}
+
 func TestMDMarshaler_MarshalYAML(t *testing.T) {
 	tests := map[string]struct {
 		md       metadata.MD
@@ -0,0 +1,794 @@
+package grpc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	gocontext "context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/internal/netutil"
+	grpcproto "github.com/scenarigo/scenarigo/protocol/grpc/proto"
+)
+
+// Transports to send RPCs.
+const (
+	// TransportGRPC sends RPCs with the gRPC protocol over HTTP/2 (default).
+	TransportGRPC = "grpc"
+	// TransportConnect sends RPCs with the Connect protocol.
+	TransportConnect = "connect"
+	// TransportGRPCWeb sends RPCs with the gRPC-Web protocol.
+	TransportGRPCWeb = "grpc-web"
+)
+
+// Codecs to encode messages with the Connect transport.
+const (
+	// CodecProto encodes messages in the binary format of protocol buffers (default).
+	CodecProto = "proto"
+	// CodecJSON encodes messages in the JSON format of protocol buffers.
+	CodecJSON = "json"
+)
+
+const (
+	flagEnvelopeCompressed = 0b00000001
+	flagConnectEndStream   = 0b00000010
+	flagGRPCWebTrailer     = 0b10000000
+
+	compressionGzip = "gzip"
+)
+
+// httpConn is a connection to send RPCs with the Connect or gRPC-Web protocol.
+// It implements grpc.ClientConnInterface to use the same client as the gRPC transport.
+// Since streams are sent over a single HTTP request, the client sends all request messages before receiving response messages.
+type httpConn struct {
+	transport string
+	baseURL   *url.URL
+	client    *http.Client
+	// socketTarget is the target of the Unix domain socket (e.g., unix:///tmp/grpc.sock).
+	socketTarget string
+}
+
+func newHTTPConn(transport, target string, auth *AuthOption, overrides netutil.Resolver) (*httpConn, error) {
+	socket, isUnix := unixSocketPath(target)
+	socketTarget := ""
+	if isUnix {
+		if socket == "" {
+			return nil, errors.ErrorPathf("target", "invalid Unix domain socket target %q", target)
+		}
+		// the host is used as the Host header (and the server name of TLS)
+		socketTarget, target = target, "localhost"
+	}
+	if !strings.Contains(target, "://") {
+		scheme := "https"
+		if auth.isInsecure() {
+			scheme = "http"
+		}
+		target = fmt.Sprintf("%s://%s", scheme, target)
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, errors.WithPath(err, "target")
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	if u.Scheme == "https" {
+		cfg, err := auth.TLSConfig()
+		if err != nil {
+			return nil, errors.WithPath(err, "auth")
+		}
+		t.TLSClientConfig = cfg
+	}
+	if isUnix {
+		t.Proxy = nil
+		t.DialContext = func(ctx gocontext.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+	} else if len(overrides) > 0 {
+		t.DialContext = overrides.DialContext(t.DialContext)
+	}
+	// disable transparent decompression to handle compressed messages by itself
+	t.DisableCompression = true
+	return &httpConn{
+		transport: transport,
+		baseURL:   u,
+		client: &http.Client{
+			Transport: t,
+		},
+		socketTarget: socketTarget,
+	}, nil
+}
+
+// codecCallOption is a call option to encode messages with the codec instead of the binary format.
+// The types resolve the message types of google.protobuf.Any fields.
+type codecCallOption struct {
+	grpc.EmptyCallOption
+	codec string
+	types *grpcproto.TypeResolver
+}
+
+// unixSocketPath returns the path of the Unix domain socket if the target uses the unix scheme of gRPC name resolution
+// (unix:path or unix:///absolute/path).
+func unixSocketPath(target string) (string, bool) {
+	if !strings.HasPrefix(target, "unix:") {
+		return "", false
+	}
+	if path, ok := strings.CutPrefix(target, "unix://"); ok {
+		return path, true
+	}
+	return strings.TrimPrefix(target, "unix:"), true
+}
+
+// host returns the host and port of the server.
+// It returns the target as it is if the server listens on the Unix domain socket.
+func (c *httpConn) host() string {
+	if c.socketTarget != "" {
+		return c.socketTarget
+	}
+	if c.baseURL.Port() != "" {
+		return c.baseURL.Host
+	}
+	if c.baseURL.Scheme == "http" {
+		return c.baseURL.Host + ":80"
+	}
+	return c.baseURL.Host + ":443"
+}
+
+// Close closes the idle connections.
+func (c *httpConn) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// Invoke implements grpc.ClientConnInterface interface.
+func (c *httpConn) Invoke(ctx gocontext.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	s, err := c.NewStream(ctx, &grpc.StreamDesc{}, method, opts...)
+	if err != nil {
+		return err
+	}
+	if err := s.SendMsg(args); err != nil {
+		return err
+	}
+	if err := s.CloseSend(); err != nil {
+		return err
+	}
+	if err := s.RecvMsg(reply); err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.Internal, "server closed the stream without sending a message")
+		}
+		return err
+	}
+	return nil
+}
+
+// NewStream implements grpc.ClientConnInterface interface.
+func (c *httpConn) NewStream(ctx gocontext.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	s := &httpStream{
+		ctx:           ctx,
+		conn:          c,
+		method:        method,
+		unary:         !desc.ClientStreams && !desc.ServerStreams,
+		serverStreams: desc.ServerStreams,
+	}
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			s.headerAddrs = append(s.headerAddrs, o.HeaderAddr)
+		case grpc.TrailerCallOption:
+			s.trailerAddrs = append(s.trailerAddrs, o.TrailerAddr)
+		case grpc.PeerCallOption:
+			s.peerAddrs = append(s.peerAddrs, o.PeerAddr)
+		case codecCallOption:
+			s.codec = o.codec
+			s.types = o.types
+		case grpc.CompressorCallOption:
+			if o.CompressorType != compressionGzip {
+				return nil, status.Errorf(codes.Internal, "unsupported compression %q", o.CompressorType)
+			}
+			s.compression = o.CompressorType
+		case grpc.MaxSendMsgSizeCallOption:
+			s.maxSendMsgSize = &o.MaxSendMsgSize
+		case grpc.MaxRecvMsgSizeCallOption:
+			s.maxRecvMsgSize = &o.MaxRecvMsgSize
+		}
+	}
+	return s, nil
+}
+
+type httpStream struct {
+	ctx            gocontext.Context
+	conn           *httpConn
+	method         string
+	unary          bool
+	serverStreams  bool
+	headerAddrs    []*metadata.MD
+	trailerAddrs   []*metadata.MD
+	peerAddrs      []*peer.Peer
+	compression    string
+	codec          string
+	types          *grpcproto.TypeResolver
+	maxSendMsgSize *int
+	maxRecvMsgSize *int
+
+	reqMsgs [][]byte
+
+	resp        *http.Response
+	body        *bufio.Reader
+	compressed  bool
+	header      metadata.MD
+	trailer     metadata.MD
+	unaryRecved bool
+	finished    bool
+	err         error
+}
+
+// Header implements grpc.ClientStream interface.
+func (s *httpStream) Header() (metadata.MD, error) {
+	if err := s.send(); err != nil {
+		return nil, err
+	}
+	return s.header, nil
+}
+
+// Trailer implements grpc.ClientStream interface.
+func (s *httpStream) Trailer() metadata.MD {
+	return s.trailer
+}
+
+// CloseSend implements grpc.ClientStream interface.
+func (s *httpStream) CloseSend() error {
+	return s.send()
+}
+
+// Context implements grpc.ClientStream interface.
+func (s *httpStream) Context() gocontext.Context {
+	return s.ctx
+}
+
+// SendMsg implements grpc.ClientStream interface.
+func (s *httpStream) SendMsg(m any) error {
+	if s.resp != nil || s.err != nil {
+		return io.EOF
+	}
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "expected proto.Message but got %T", m)
+	}
+	b, err := s.marshal(msg)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal message: %s", err)
+	}
+	if s.maxSendMsgSize != nil && len(b) > *s.maxSendMsgSize {
+		return status.Errorf(codes.ResourceExhausted, "trying to send message larger than max (%d vs. %d)", len(b), *s.maxSendMsgSize)
+	}
+	s.reqMsgs = append(s.reqMsgs, b)
+	return nil
+}
+
+// RecvMsg implements grpc.ClientStream interface.
+func (s *httpStream) RecvMsg(m any) error {
+	if err := s.send(); err != nil {
+		return err
+	}
+	if s.finished {
+		return s.finish(s.err)
+	}
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "expected proto.Message but got %T", m)
+	}
+	b, err := s.recv()
+	if err != nil {
+		return s.finish(err)
+	}
+	if s.maxRecvMsgSize != nil && len(b) > *s.maxRecvMsgSize {
+		return s.finish(status.Errorf(codes.ResourceExhausted, "received message larger than max (%d vs. %d)", len(b), *s.maxRecvMsgSize))
+	}
+	if err := s.unmarshal(b, msg); err != nil {
+		return s.finish(status.Errorf(codes.Internal, "failed to unmarshal message: %s", err))
+	}
+	if !s.serverStreams {
+		// read the rest of the stream to receive the trailer and status
+		if _, err := s.recv(); !errors.Is(err, io.EOF) {
+			if err == nil {
+				err = status.Error(codes.Internal, "received multiple response messages for non-server-streaming RPC")
+			}
+			return s.finish(err)
+		}
+		_ = s.finish(io.EOF)
+	}
+	return nil
+}
+
+func (s *httpStream) marshal(msg proto.Message) ([]byte, error) {
+	if s.codec == CodecJSON {
+		opts := protojson.MarshalOptions{}
+		if s.types != nil {
+			opts.Resolver = s.types
+		}
+		return opts.Marshal(msg)
+	}
+	return proto.Marshal(msg)
+}
+
+func (s *httpStream) unmarshal(b []byte, msg proto.Message) error {
+	if s.codec == CodecJSON {
+		// ignore unknown fields like the binary format
+		opts := protojson.UnmarshalOptions{DiscardUnknown: true}
+		if s.types != nil {
+			opts.Resolver = s.types
+		}
+		return opts.Unmarshal(b, msg)
+	}
+	return proto.Unmarshal(b, msg)
+}
+
+// finish closes the response body and sets the header and trailer to the call options.
+func (s *httpStream) finish(err error) error {
+	if !s.finished {
+		s.finished = true
+		s.err = err
+		if s.resp != nil {
+			s.resp.Body.Close()
+		}
+		for _, addr := range s.headerAddrs {
+			*addr = s.header
+		}
+		for _, addr := range s.trailerAddrs {
+			*addr = s.trailer
+		}
+	}
+	return s.err
+}
+
+func (s *httpStream) send() error {
+	if s.resp != nil || s.finished {
+		return nil
+	}
+	req, err := s.newRequest()
+	if err != nil {
+		return s.finish(err)
+	}
+	var remoteAddr net.Addr
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			remoteAddr = info.Conn.RemoteAddr()
+		},
+	}))
+	resp, err := s.conn.client.Do(req)
+	if err != nil {
+		if ctxErr := s.ctx.Err(); ctxErr != nil {
+			return s.finish(status.FromContextError(ctxErr).Err())
+		}
+		return s.finish(status.Error(codes.Unavailable, err.Error()))
+	}
+	p := peer.Peer{Addr: remoteAddr}
+	if resp.TLS != nil {
+		p.AuthInfo = credentials.TLSInfo{State: *resp.TLS}
+	}
+	for _, addr := range s.peerAddrs {
+		*addr = p
+	}
+	s.resp = resp
+	s.body = bufio.NewReader(resp.Body)
+	s.header = metadata.MD{}
+	s.trailer = metadata.MD{}
+	for k, vs := range resp.Header {
+		k = strings.ToLower(k)
+		if s.isConnectUnary() && strings.HasPrefix(k, "trailer-") {
+			s.trailer.Append(strings.TrimPrefix(k, "trailer-"), decodeMetadataValues(k, vs)...)
+			continue
+		}
+		s.header.Append(k, decodeMetadataValues(k, vs)...)
+	}
+	switch s.conn.transport {
+	case TransportConnect:
+		if s.unary {
+			s.compressed = resp.Header.Get("Content-Encoding") == compressionGzip
+		} else {
+			s.compressed = resp.Header.Get("Connect-Content-Encoding") == compressionGzip
+		}
+	case TransportGRPCWeb:
+		s.compressed = resp.Header.Get("Grpc-Encoding") == compressionGzip
+		// trailers-only response
+		if resp.Header.Get("Grpc-Status") != "" {
+			s.trailer = s.header
+			return s.finish(grpcWebStatus(s.header))
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		if s.isConnectUnary() {
+			return s.finish(s.connectUnaryError())
+		}
+		return s.finish(status.Errorf(httpStatusToCode(resp.StatusCode), "unexpected HTTP status code %d", resp.StatusCode))
+	}
+	return nil
+}
+
+func (s *httpStream) isConnectUnary() bool {
+	return s.conn.transport == TransportConnect && s.unary
+}
+
+func (s *httpStream) newRequest() (*http.Request, error) {
+	var body bytes.Buffer
+	if s.isConnectUnary() {
+		var b []byte
+		if len(s.reqMsgs) > 0 {
+			b = s.reqMsgs[0]
+		}
+		if s.compression != "" {
+			var err error
+			b, err = gzipCompress(b)
+			if err != nil {
+				return nil, err
+			}
+		}
+		body.Write(b)
+	} else {
+		for _, b := range s.reqMsgs {
+			if err := writeEnvelope(&body, b, s.compression); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	u := s.conn.baseURL.JoinPath(s.method)
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, u.String(), &body)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create request: %s", err)
+	}
+	if md, ok := metadata.FromOutgoingContext(s.ctx); ok {
+		for k, vs := range md {
+			for _, v := range vs {
+				if strings.HasSuffix(k, "-bin") {
+					v = base64.RawStdEncoding.EncodeToString([]byte(v))
+				}
+				req.Header.Add(k, v)
+			}
+		}
+	}
+	deadline, hasDeadline := s.ctx.Deadline()
+	switch s.conn.transport {
+	case TransportConnect:
+		req.Header.Set("Connect-Protocol-Version", "1")
+		codec := CodecProto
+		if s.codec != "" {
+			codec = s.codec
+		}
+		if s.unary {
+			req.Header.Set("Content-Type", "application/"+codec)
+			if s.compression != "" {
+				req.Header.Set("Content-Encoding", s.compression)
+			}
+		} else {
+			req.Header.Set("Content-Type", "application/connect+"+codec)
+			if s.compression != "" {
+				req.Header.Set("Connect-Content-Encoding", s.compression)
+			}
+		}
+		if hasDeadline {
+			req.Header.Set("Connect-Timeout-Ms", strconv.FormatInt(max(time.Until(deadline).Milliseconds(), 1), 10))
+		}
+	case TransportGRPCWeb:
+		req.Header.Set("Content-Type", "application/grpc-web+proto")
+		req.Header.Set("X-Grpc-Web", "1")
+		if s.compression != "" {
+			req.Header.Set("Grpc-Encoding", s.compression)
+		}
+		if hasDeadline {
+			req.Header.Set("Grpc-Timeout", fmt.Sprintf("%dm", max(time.Until(deadline).Milliseconds(), 1)))
+		}
+	}
+	return req, nil
+}
+
+// recv reads the next message.
+// It returns io.EOF if the stream has finished successfully.
+func (s *httpStream) recv() ([]byte, error) {
+	if s.isConnectUnary() {
+		if s.unaryRecved {
+			return nil, io.EOF
+		}
+		s.unaryRecved = true
+		b, err := io.ReadAll(s.body)
+		if err != nil {
+			return nil, s.readError(err)
+		}
+		if s.compressed {
+			return gzipDecompress(b)
+		}
+		return b, nil
+	}
+
+	flags, b, err := readEnvelope(s.body)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			if s.conn.transport == TransportGRPCWeb && len(s.trailer) == 0 {
+				return nil, status.Error(codes.Internal, "server closed the stream without sending trailers")
+			}
+			return nil, io.EOF
+		}
+		return nil, s.readError(err)
+	}
+	if flags&flagEnvelopeCompressed != 0 {
+		if !s.compressed {
+			return nil, status.Error(codes.Internal, "received compressed message without encoding")
+		}
+		b, err = gzipDecompress(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case s.conn.transport == TransportConnect && flags&flagConnectEndStream != 0:
+		return nil, s.connectEndStream(b)
+	case s.conn.transport == TransportGRPCWeb && flags&flagGRPCWebTrailer != 0:
+		trailer, err := parseGRPCWebTrailer(b)
+		if err != nil {
+			return nil, err
+		}
+		s.trailer = trailer
+		if err := grpcWebStatus(trailer); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return b, nil
+}
+
+func (s *httpStream) readError(err error) error {
+	if ctxErr := s.ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+	return status.Errorf(codes.Internal, "failed to read response: %s", err)
+}
+
+type connectError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"details"`
+}
+
+func (e *connectError) status() *status.Status {
+	code, ok := connectCodes[e.Code]
+	if !ok {
+		code = codes.Unknown
+	}
+	st := &spb.Status{
+		Code:    int32(code),
+		Message: e.Message,
+	}
+	for _, d := range e.Details {
+		b, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(d.Value, "="))
+		if err != nil {
+			continue
+		}
+		st.Details = append(st.Details, &anypb.Any{
+			TypeUrl: "type.googleapis.com/" + d.Type,
+			Value:   b,
+		})
+	}
+	return status.FromProto(st)
+}
+
+func (s *httpStream) connectUnaryError() error {
+	b, err := io.ReadAll(s.body)
+	if err != nil {
+		return s.readError(err)
+	}
+	if s.compressed {
+		if b, err = gzipDecompress(b); err != nil {
+			return err
+		}
+	}
+	var e connectError
+	if err := json.Unmarshal(b, &e); err != nil || e.Code == "" {
+		return status.Errorf(httpStatusToCode(s.resp.StatusCode), "unexpected HTTP status code %d", s.resp.StatusCode)
+	}
+	return e.status().Err()
+}
+
+func (s *httpStream) connectEndStream(b []byte) error {
+	var end struct {
+		Error    *connectError       `json:"error"`
+		Metadata map[string][]string `json:"metadata"`
+	}
+	if err := json.Unmarshal(b, &end); err != nil {
+		return status.Errorf(codes.Internal, "failed to unmarshal end of stream message: %s", err)
+	}
+	for k, vs := range end.Metadata {
+		k = strings.ToLower(k)
+		s.trailer.Append(k, decodeMetadataValues(k, vs)...)
+	}
+	if end.Error != nil {
+		return end.Error.status().Err()
+	}
+	return io.EOF
+}
+
+func writeEnvelope(w io.Writer, b []byte, compression string) error {
+	var flags byte
+	if compression != "" {
+		var err error
+		b, err = gzipCompress(b)
+		if err != nil {
+			return err
+		}
+		flags |= flagEnvelopeCompressed
+	}
+	prefix := make([]byte, 5)
+	prefix[0] = flags
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(b)))
+	if _, err := w.Write(prefix); err != nil {
+		return status.Errorf(codes.Internal, "failed to write message: %s", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return status.Errorf(codes.Internal, "failed to write message: %s", err)
+	}
+	return nil
+}
+
+func readEnvelope(r io.Reader) (byte, []byte, error) {
+	prefix := make([]byte, 5)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, nil, status.Error(codes.Internal, "received incomplete message")
+		}
+		return 0, nil, err
+	}
+	b := make([]byte, binary.BigEndian.Uint32(prefix[1:]))
+	if _, err := io.ReadFull(r, b); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, nil, status.Error(codes.Internal, "received incomplete message")
+		}
+		return 0, nil, err
+	}
+	return prefix[0], b, nil
+}
+
+func gzipCompress(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to compress message: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to compress message: %s", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func gzipDecompress(b []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to decompress message: %s", err)
+	}
+	defer r.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to decompress message: %s", err)
+	}
+	return out, nil
+}
+
+func parseGRPCWebTrailer(b []byte) (metadata.MD, error) {
+	r := textproto.NewReader(bufio.NewReader(io.MultiReader(bytes.NewReader(b), strings.NewReader("\r\n"))))
+	h, err := r.ReadMIMEHeader()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, status.Errorf(codes.Internal, "failed to parse trailer: %s", err)
+	}
+	md := metadata.MD{}
+	for k, vs := range h {
+		k = strings.ToLower(k)
+		md.Append(k, decodeMetadataValues(k, vs)...)
+	}
+	return md, nil
+}
+
+func grpcWebStatus(md metadata.MD) error {
+	vs := md.Get("grpc-status")
+	if len(vs) == 0 {
+		return status.Error(codes.Internal, "server closed the stream without sending status")
+	}
+	c, err := strconv.ParseUint(vs[0], 10, 32)
+	if err != nil {
+		return status.Errorf(codes.Internal, "invalid grpc-status %q", vs[0])
+	}
+	if bin := md.Get("grpc-status-details-bin"); len(bin) > 0 {
+		var st spb.Status
+		if err := proto.Unmarshal([]byte(bin[0]), &st); err == nil {
+			return status.FromProto(&st).Err()
+		}
+	}
+	var msg string
+	if vs := md.Get("grpc-message"); len(vs) > 0 {
+		msg, err = url.PathUnescape(vs[0])
+		if err != nil {
+			msg = vs[0]
+		}
+	}
+	if codes.Code(c) == codes.OK {
+		return nil
+	}
+	return status.Error(codes.Code(c), msg)
+}
+
+func decodeMetadataValues(k string, vs []string) []string {
+	if !strings.HasSuffix(k, "-bin") {
+		return vs
+	}
+	decoded := make([]string, len(vs))
+	for i, v := range vs {
+		b, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(v, "="))
+		if err != nil {
+			decoded[i] = v
+			continue
+		}
+		decoded[i] = string(b)
+	}
+	return decoded
+}
+
+// httpStatusToCode converts the HTTP status code to the gRPC status code.
+// ref. https://github.com/grpc/grpc/blob/master/doc/http-grpc-status-mapping.md
+func httpStatusToCode(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest:
+		return codes.Internal
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
+
+var connectCodes = map[string]codes.Code{
+	"canceled":            codes.Canceled,
+	"unknown":             codes.Unknown,
+	"invalid_argument":    codes.InvalidArgument,
+	"deadline_exceeded":   codes.DeadlineExceeded,
+	"not_found":           codes.NotFound,
+	"already_exists":      codes.AlreadyExists,
+	"permission_denied":   codes.PermissionDenied,
+	"resource_exhausted":  codes.ResourceExhausted,
+	"failed_precondition": codes.FailedPrecondition,
+	"aborted":             codes.Aborted,
+	"out_of_range":        codes.OutOfRange,
+	"unimplemented":       codes.Unimplemented,
+	"internal":            codes.Internal,
+	"unavailable":         codes.Unavailable,
+	"data_loss":           codes.DataLoss,
+	"unauthenticated":     codes.Unauthenticated,
+}
@@ -0,0 +1,631 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/internal/netutil"
+	"github.com/scenarigo/scenarigo/internal/ptr"
+	testpb "github.com/scenarigo/scenarigo/testdata/gen/pb/test"
+)
+
+// testHTTPTransportServer is a minimal server implementation of the Connect and gRPC-Web protocols for testing.
+type testHTTPTransportServer struct {
+	t *testing.T
+}
+
+func (s *testHTTPTransportServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.t.Helper()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.t.Errorf("failed to read request: %s", err)
+		return
+	}
+	isConnect := r.Header.Get("Connect-Protocol-Version") == "1"
+	isUnary := r.URL.Path == fmt.Sprintf("/%s/Echo", testpb.Test_ServiceDesc.ServiceName)
+	if !isConnect && r.Header.Get("X-Grpc-Web") != "1" {
+		s.t.Errorf("unknown protocol: %v", r.Header)
+		return
+	}
+
+	// the Connect protocol supports the JSON codec
+	isJSON := strings.HasSuffix(r.Header.Get("Content-Type"), "json")
+	unmarshal, marshal := proto.Unmarshal, proto.Marshal
+	codec := "proto"
+	if isJSON {
+		unmarshal, marshal = protojson.Unmarshal, protojson.Marshal
+		codec = "json"
+	}
+
+	var reqs []*testpb.EchoRequest
+	if isConnect && isUnary {
+		if got, expect := r.Header.Get("Content-Type"), "application/"+codec; got != expect {
+			s.t.Errorf("expect content type %q but got %q", expect, got)
+		}
+		var req testpb.EchoRequest
+		if err := unmarshal(body, &req); err != nil {
+			s.t.Errorf("failed to unmarshal request: %s", err)
+			return
+		}
+		reqs = append(reqs, &req)
+	} else {
+		buf := bytes.NewBuffer(body)
+		for buf.Len() > 0 {
+			_, b, err := readEnvelope(buf)
+			if err != nil {
+				s.t.Errorf("failed to read request: %s", err)
+				return
+			}
+			var req testpb.EchoRequest
+			if err := unmarshal(b, &req); err != nil {
+				s.t.Errorf("failed to unmarshal request: %s", err)
+				return
+			}
+			reqs = append(reqs, &req)
+		}
+	}
+	if len(reqs) == 0 {
+		s.t.Error("no request message")
+		return
+	}
+	req := reqs[0]
+
+	w.Header().Set("Test-Header", r.Header.Get("Test-Header"))
+	var resps []*testpb.EchoResponse
+	var code codes.Code
+	var msg string
+	switch {
+	case req.GetMessageBody() == "error":
+		code, msg = codes.InvalidArgument, "invalid message body"
+	case isUnary:
+		resps = append(resps, &testpb.EchoResponse{MessageId: req.GetMessageId(), MessageBody: req.GetMessageBody()})
+	default:
+		for i, w := range strings.Fields(req.GetMessageBody()) {
+			resps = append(resps, &testpb.EchoResponse{MessageId: fmt.Sprintf("%s-%d", req.GetMessageId(), i), MessageBody: w})
+		}
+	}
+
+	switch {
+	case isConnect && isUnary:
+		if code != codes.OK {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"code": "invalid_argument", "message": msg})
+			return
+		}
+		b, _ := marshal(resps[0])
+		w.Header().Set("Content-Type", "application/"+codec)
+		w.Header().Set("Trailer-Test-Trailer", "trailer")
+		_, _ = w.Write(b)
+	case isConnect:
+		w.Header().Set("Content-Type", "application/connect+"+codec)
+		var buf bytes.Buffer
+		for _, resp := range resps {
+			b, _ := marshal(resp)
+			_ = writeEnvelope(&buf, b, "")
+		}
+		end := map[string]any{"metadata": map[string][]string{"test-trailer": {"trailer"}}}
+		if code != codes.OK {
+			end["error"] = map[string]string{"code": "invalid_argument", "message": msg}
+		}
+		b, _ := json.Marshal(end)
+		prefix := []byte{flagConnectEndStream, 0, 0, 0, byte(len(b))}
+		buf.Write(prefix)
+		buf.Write(b)
+		_, _ = w.Write(buf.Bytes())
+	default:
+		w.Header().Set("Content-Type", "application/grpc-web+proto")
+		var buf bytes.Buffer
+		for _, resp := range resps {
+			b, _ := proto.Marshal(resp)
+			_ = writeEnvelope(&buf, b, "")
+		}
+		trailer := fmt.Sprintf("grpc-status: %d\r\ngrpc-message: %s\r\ntest-trailer: trailer\r\ntest-trailer-bin: %s\r\n", code, strings.ReplaceAll(msg, " ", "%20"), base64.StdEncoding.EncodeToString([]byte("binary")))
+		prefix := []byte{flagGRPCWebTrailer, 0, 0, 0, byte(len(trailer))}
+		buf.Write(prefix)
+		buf.WriteString(trailer)
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+func withCodec(opts *RequestOptions, codec string) *RequestOptions {
+	opts.Codec = codec
+	return opts
+}
+
+func TestProtoClient_HTTPTransport(t *testing.T) {
+	opts := func(transport string) *RequestOptions {
+		return &RequestOptions{
+			Transport: transport,
+			Proto: &ProtoOption{
+				Files: []string{
+					"../../testdata/proto/test/test.proto",
+				},
+			},
+			Auth: &AuthOption{
+				Insecure: ptr.To(true),
+			},
+		}
+	}
+	tests := map[string]struct {
+		request        *Request
+		expectCode     codes.Code
+		expectMessage  string
+		expectMessages []*testpb.EchoResponse
+		expectResponse *testpb.EchoResponse
+		expectHeader   map[string][]string
+		expectTrailer  map[string][]string
+	}{
+		"connect unary": {
+			request: &Request{
+				Method:   "Echo",
+				Metadata: map[string]string{"test-header": "header"},
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: opts(TransportConnect),
+			},
+			expectCode:     codes.OK,
+			expectResponse: &testpb.EchoResponse{MessageId: "1", MessageBody: "hello"},
+			expectHeader:   map[string][]string{"test-header": {"header"}},
+			expectTrailer:  map[string][]string{"test-trailer": {"trailer"}},
+		},
+		"connect unary error": {
+			request: &Request{
+				Method: "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageBody", Value: "error"},
+				},
+				Options: opts(TransportConnect),
+			},
+			expectCode:    codes.InvalidArgument,
+			expectMessage: "invalid message body",
+		},
+		"connect server streaming": {
+			request: &Request{
+				Method: "ServerStreamingEcho",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello world"},
+				},
+				Options: opts(TransportConnect),
+			},
+			expectCode: codes.OK,
+			expectMessages: []*testpb.EchoResponse{
+				{MessageId: "1-0", MessageBody: "hello"},
+				{MessageId: "1-1", MessageBody: "world"},
+			},
+			expectTrailer: map[string][]string{"test-trailer": {"trailer"}},
+		},
+		"connect server streaming error": {
+			request: &Request{
+				Method: "ServerStreamingEcho",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageBody", Value: "error"},
+				},
+				Options: opts(TransportConnect),
+			},
+			expectCode:    codes.InvalidArgument,
+			expectMessage: "invalid message body",
+		},
+		"connect unary with JSON codec": {
+			request: &Request{
+				Method: "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: withCodec(opts(TransportConnect), CodecJSON),
+			},
+			expectCode:     codes.OK,
+			expectResponse: &testpb.EchoResponse{MessageId: "1", MessageBody: "hello"},
+			expectTrailer:  map[string][]string{"test-trailer": {"trailer"}},
+		},
+		"connect server streaming with JSON codec": {
+			request: &Request{
+				Method: "ServerStreamingEcho",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello world"},
+				},
+				Options: withCodec(opts(TransportConnect), CodecJSON),
+			},
+			expectCode: codes.OK,
+			expectMessages: []*testpb.EchoResponse{
+				{MessageId: "1-0", MessageBody: "hello"},
+				{MessageId: "1-1", MessageBody: "world"},
+			},
+			expectTrailer: map[string][]string{"test-trailer": {"trailer"}},
+		},
+		"grpc-web unary": {
+			request: &Request{
+				Method: "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: opts(TransportGRPCWeb),
+			},
+			expectCode:     codes.OK,
+			expectResponse: &testpb.EchoResponse{MessageId: "1", MessageBody: "hello"},
+			expectTrailer: map[string][]string{
+				"test-trailer":     {"trailer"},
+				"test-trailer-bin": {"binary"},
+			},
+		},
+		"grpc-web unary error": {
+			request: &Request{
+				Method: "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageBody", Value: "error"},
+				},
+				Options: opts(TransportGRPCWeb),
+			},
+			expectCode:    codes.InvalidArgument,
+			expectMessage: "invalid message body",
+		},
+		"grpc-web server streaming": {
+			request: &Request{
+				Method: "ServerStreamingEcho",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello world"},
+				},
+				Options: opts(TransportGRPCWeb),
+			},
+			expectCode: codes.OK,
+			expectMessages: []*testpb.EchoResponse{
+				{MessageId: "1-0", MessageBody: "hello"},
+				{MessageId: "1-1", MessageBody: "world"},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(&testHTTPTransportServer{t: t})
+			t.Cleanup(srv.Close)
+			t.Cleanup(func() { _ = connPool.closeConnection(srv.URL) })
+
+			test.request.Target = srv.URL
+			if test.request.Method == "Echo" {
+				test.request.Service = testpb.Test_ServiceDesc.ServiceName
+			} else {
+				test.request.Service = testpb.StreamTest_ServiceDesc.ServiceName
+			}
+			_, result, err := test.request.Invoke(context.FromT(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			resp, ok := result.(*response)
+			if !ok {
+				t.Fatalf("failed to type conversion from %s to *response", reflect.TypeOf(result))
+			}
+			if got := resp.Status.Code(); got != test.expectCode {
+				t.Fatalf("expected code is %s but got %s: %s", test.expectCode, got, resp.Status.Err())
+			}
+			if got := resp.Status.Message(); got != test.expectMessage {
+				t.Errorf("expected message is %q but got %q", test.expectMessage, got)
+			}
+			if test.expectResponse != nil {
+				if resp.Message == nil {
+					t.Fatal("no message")
+				}
+				if diff := cmp.Diff(test.expectResponse, resp.Message.Message, protocmp.Transform()); diff != "" {
+					t.Errorf("differs: (-want +got)\n%s", diff)
+				}
+			}
+			expectMsgs := make([]proto.Message, len(test.expectMessages))
+			for i, m := range test.expectMessages {
+				expectMsgs[i] = m
+			}
+			gotMsgs := make([]proto.Message, len(resp.Messages))
+			for i, m := range resp.Messages {
+				gotMsgs[i] = m.Message
+			}
+			if diff := cmp.Diff(expectMsgs, gotMsgs, protocmp.Transform()); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+			if test.expectHeader != nil {
+				if resp.Header == nil {
+					t.Fatal("no header")
+				}
+				for k, v := range test.expectHeader {
+					if diff := cmp.Diff(v, metadata.MD(*resp.Header).Get(k)); diff != "" {
+						t.Errorf("header %q differs: (-want +got)\n%s", k, diff)
+					}
+				}
+			}
+			if test.expectTrailer != nil {
+				if resp.Trailer == nil {
+					t.Fatal("no trailer")
+				}
+				for k, v := range test.expectTrailer {
+					if diff := cmp.Diff(v, metadata.MD(*resp.Trailer).Get(k)); diff != "" {
+						t.Errorf("trailer %q differs: (-want +got)\n%s", k, diff)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestProtoClient_HTTPTransport_TLS(t *testing.T) {
+	srv := httptest.NewTLSServer(&testHTTPTransportServer{t: t})
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { _ = connPool.closeConnection(srv.URL) })
+	caCert := filepath.Join(t.TempDir(), "ca.crt")
+	if err := os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, transport := range []string{TransportConnect, TransportGRPCWeb} {
+		t.Run(transport, func(t *testing.T) {
+			req := &Request{
+				Target:  srv.URL,
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Message: yaml.MapSlice{
+					yaml.MapItem{Key: "messageId", Value: "1"},
+					yaml.MapItem{Key: "messageBody", Value: "hello"},
+				},
+				Options: &RequestOptions{
+					Transport: transport,
+					Proto: &ProtoOption{
+						Files: []string{
+							"../../testdata/proto/test/test.proto",
+						},
+					},
+					Auth: &AuthOption{
+						TLS: &TLSOption{
+							Certificate: caCert,
+						},
+					},
+				},
+			}
+			_, result, err := req.Invoke(context.FromT(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			resp, ok := result.(*response)
+			if !ok {
+				t.Fatalf("failed to type conversion from %s to *response", reflect.TypeOf(result))
+			}
+			if got := resp.Status.Code(); got != codes.OK {
+				t.Fatalf("expected code is %s but got %s: %s", codes.OK, got, resp.Status.Err())
+			}
+			if resp.TLS == nil {
+				t.Fatal("no TLS connection state")
+			}
+			if got, expect := len(resp.TLS.Certificates), 1; got != expect {
+				t.Errorf("expect %d certificates but got %d", expect, got)
+			}
+		})
+	}
+}
+
+func TestProtoClient_HTTPTransport_InvalidCodec(t *testing.T) {
+	tests := map[string]struct {
+		transport string
+		codec     string
+		expect    string
+	}{
+		"unsupported codec": {
+			transport: TransportConnect,
+			codec:     "xml",
+			expect:    `.options.codec: unsupported codec "xml"`,
+		},
+		"JSON codec with gRPC": {
+			codec:  CodecJSON,
+			expect: `.options.codec: codec "json" is supported only by the connect transport`,
+		},
+		"JSON codec with gRPC-Web": {
+			transport: TransportGRPCWeb,
+			codec:     CodecJSON,
+			expect:    `.options.codec: codec "json" is supported only by the connect transport`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := &Request{
+				Target:  "localhost:8080",
+				Service: testpb.Test_ServiceDesc.ServiceName,
+				Method:  "Echo",
+				Options: &RequestOptions{
+					Transport: test.transport,
+					Codec:     test.codec,
+				},
+			}
+			_, _, err := req.Invoke(context.FromT(t))
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if got := err.Error(); !strings.Contains(got, test.expect) {
+				t.Errorf("expect error %q but got %q", test.expect, got)
+			}
+		})
+	}
+}
+
+func TestProtoClient_HTTPTransport_InvalidTransport(t *testing.T) {
+	req := &Request{
+		Target:  "localhost:8080",
+		Service: testpb.Test_ServiceDesc.ServiceName,
+		Method:  "Echo",
+		Options: &RequestOptions{
+			Transport: "invalid",
+		},
+	}
+	_, _, err := req.Invoke(context.FromT(t))
+	if err == nil {
+		t.Fatal("no error")
+	}
+	if got, expect := err.Error(), `.options.transport: unsupported transport "invalid"`; !strings.Contains(got, expect) {
+		t.Errorf("expect error %q but got %q", expect, got)
+	}
+}
+
+func TestProtoClient_HTTPTransport_UnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "grpc.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	srv := httptest.NewUnstartedServer(&testHTTPTransportServer{t: t})
+	srv.Listener = l
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	target := "unix://" + socket
+	t.Cleanup(func() { _ = connPool.closeConnection(target) })
+	req := &Request{
+		Target:  target,
+		Service: testpb.Test_ServiceDesc.ServiceName,
+		Method:  "Echo",
+		Message: yaml.MapSlice{
+			yaml.MapItem{Key: "messageId", Value: "1"},
+			yaml.MapItem{Key: "messageBody", Value: "hello"},
+		},
+		Options: &RequestOptions{
+			Transport: TransportConnect,
+			Proto: &ProtoOption{
+				Files: []string{
+					"../../testdata/proto/test/test.proto",
+				},
+			},
+			Auth: &AuthOption{
+				Insecure: ptr.To(true),
+			},
+		},
+	}
+	_, result, err := req.Invoke(context.FromT(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp, ok := result.(*response)
+	if !ok {
+		t.Fatalf("failed to type conversion from %s to *response", reflect.TypeOf(result))
+	}
+	if got := resp.Status.Code(); got != codes.OK {
+		t.Fatalf("expected code is %s but got %s: %s", codes.OK, got, resp.Status.Err())
+	}
+	if resp.Message == nil {
+		t.Fatal("no message")
+	}
+	if diff := cmp.Diff(&testpb.EchoResponse{MessageId: "1", MessageBody: "hello"}, resp.Message.Message, protocmp.Transform()); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func TestProtoClient_HTTPTransport_Resolve(t *testing.T) {
+	srv := httptest.NewServer(&testHTTPTransportServer{t: t})
+	t.Cleanup(srv.Close)
+
+	target := "http://connect.example.com"
+	t.Cleanup(func() { _ = connPool.closeConnection(target) })
+	req := &Request{
+		Target:  target,
+		Service: testpb.Test_ServiceDesc.ServiceName,
+		Method:  "Echo",
+		Message: yaml.MapSlice{
+			yaml.MapItem{Key: "messageId", Value: "1"},
+			yaml.MapItem{Key: "messageBody", Value: "hello"},
+		},
+		Options: &RequestOptions{
+			Transport: TransportConnect,
+			Proto: &ProtoOption{
+				Files: []string{
+					"../../testdata/proto/test/test.proto",
+				},
+			},
+			Auth: &AuthOption{
+				Insecure: ptr.To(true),
+			},
+		},
+	}
+	addr := srv.Listener.Addr().String()
+	ctx, result, err := req.Invoke(context.FromT(t).WithResolver(netutil.Resolver{"connect.example.com:80": addr}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp, ok := result.(*response)
+	if !ok {
+		t.Fatalf("failed to type conversion from %s to *response", reflect.TypeOf(result))
+	}
+	if got := resp.Status.Code(); got != codes.OK {
+		t.Fatalf("expected code is %s but got %s: %s", codes.OK, got, resp.Status.Err())
+	}
+	dump, ok := ctx.Request().(*RequestExtractor)
+	if !ok {
+		t.Fatalf("failed to type conversion from %s to *RequestExtractor", reflect.TypeOf(ctx.Request()))
+	}
+	if got := dump.ResolvedAddress; got != addr {
+		t.Errorf("expect resolved address %q but got %q", addr, got)
+	}
+}
+
+func TestNewHTTPConn_UnixSocket(t *testing.T) {
+	tests := map[string]struct {
+		target     string
+		expectHost string
+		expectErr  string
+	}{
+		"absolute path": {
+			target:     "unix:///tmp/grpc.sock",
+			expectHost: "unix:///tmp/grpc.sock",
+		},
+		"relative path": {
+			target:     "unix:grpc.sock",
+			expectHost: "unix:grpc.sock",
+		},
+		"TCP": {
+			target:     "localhost:8080",
+			expectHost: "localhost:8080",
+		},
+		"no path": {
+			target:    "unix://",
+			expectErr: `.target: invalid Unix domain socket target "unix://"`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			conn, err := newHTTPConn(TransportConnect, test.target, &AuthOption{Insecure: ptr.To(true)}, nil)
+			if test.expectErr != "" {
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if got := err.Error(); !strings.Contains(got, test.expectErr) {
+					t.Errorf("expect error %q but got %q", test.expectErr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := conn.host(); got != test.expectHost {
+				t.Errorf("expect host %q but got %q", test.expectHost, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,400 @@
+package http
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/errors"
+)
+
+const redacted = "*****"
+
+// AuthOption represents an option for the request authentication.
+// Only one of the authentication methods can be specified.
+type AuthOption struct {
+	Basic  *BasicAuthOption  `yaml:"basic,omitempty"`
+	Bearer *BearerAuthOption `yaml:"bearer,omitempty"`
+	OAuth2 *OAuth2Option     `yaml:"oauth2,omitempty"`
+	HMAC   *HMACOption       `yaml:"hmac,omitempty"`
+	SigV4  *SigV4Option      `yaml:"sigv4,omitempty"`
+}
+
+// BasicAuthOption represents an option for the basic authentication.
+type BasicAuthOption struct {
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+}
+
+// BearerAuthOption represents an option for the bearer token authentication.
+type BearerAuthOption struct {
+	Token string `yaml:"token,omitempty"`
+}
+
+// OAuth2Option represents an option for the OAuth 2.0 client credentials grant.
+// The access token is cached until it expires.
+type OAuth2Option struct {
+	TokenURL     string   `yaml:"tokenURL,omitempty"`
+	ClientID     string   `yaml:"clientID,omitempty"`
+	ClientSecret string   `yaml:"clientSecret,omitempty"`
+	Scopes       []string `yaml:"scopes,omitempty"`
+	// Params is the additional parameters of the token request (e.g., audience).
+	Params map[string]string `yaml:"params,omitempty"`
+	// AuthStyle is the way to send the client credentials: header (default) or params.
+	AuthStyle string `yaml:"authStyle,omitempty"`
+}
+
+// HMACOption represents an option for the HMAC request signing.
+// The signature is calculated over "<method>\n<path and query>\n<body>".
+type HMACOption struct {
+	Key string `yaml:"key,omitempty"`
+	// Algorithm must be one of sha256 (default), sha512, sha1.
+	Algorithm string `yaml:"algorithm,omitempty"`
+	// Header is the header name to set the signature (default: X-Signature).
+	Header string `yaml:"header,omitempty"`
+	// Prefix is prepended to the signature (e.g., "sha256=").
+	Prefix string `yaml:"prefix,omitempty"`
+	// Encoding must be one of hex (default), base64.
+	Encoding string `yaml:"encoding,omitempty"`
+}
+
+var hmacAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// clone returns a deep copy of o to execute templates without modifying the original.
+func (o *AuthOption) clone() *AuthOption {
+	c := *o
+	if o.Basic != nil {
+		basic := *o.Basic
+		c.Basic = &basic
+	}
+	if o.Bearer != nil {
+		bearer := *o.Bearer
+		c.Bearer = &bearer
+	}
+	if o.OAuth2 != nil {
+		oauth2 := *o.OAuth2
+		oauth2.Scopes = slices.Clone(o.OAuth2.Scopes)
+		oauth2.Params = maps.Clone(o.OAuth2.Params)
+		c.OAuth2 = &oauth2
+	}
+	if o.HMAC != nil {
+		h := *o.HMAC
+		c.HMAC = &h
+	}
+	if o.SigV4 != nil {
+		sigv4 := *o.SigV4
+		c.SigV4 = &sigv4
+	}
+	return &c
+}
+
+func (o *AuthOption) validate() error {
+	var n int
+	for _, set := range []bool{o.Basic != nil, o.Bearer != nil, o.OAuth2 != nil, o.HMAC != nil, o.SigV4 != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return errors.New("one of basic, bearer, oauth2, hmac, or sigv4 must be specified")
+	}
+	switch {
+	case o.Basic != nil:
+		if o.Basic.Username == "" {
+			return errors.ErrorPath("basic.username", "username is required")
+		}
+	case o.Bearer != nil:
+		if o.Bearer.Token == "" {
+			return errors.ErrorPath("bearer.token", "token is required")
+		}
+	case o.OAuth2 != nil:
+		if o.OAuth2.TokenURL == "" {
+			return errors.ErrorPath("oauth2.tokenURL", "token URL is required")
+		}
+		if o.OAuth2.ClientID == "" {
+			return errors.ErrorPath("oauth2.clientID", "client ID is required")
+		}
+		switch o.OAuth2.AuthStyle {
+		case "", "header", "params":
+		default:
+			return errors.ErrorPathf("oauth2.authStyle", "auth style must be header or params but got %q", o.OAuth2.AuthStyle)
+		}
+	case o.HMAC != nil:
+		if o.HMAC.Key == "" {
+			return errors.ErrorPath("hmac.key", "key is required")
+		}
+		if _, ok := hmacAlgorithms[o.HMAC.Algorithm]; !ok && o.HMAC.Algorithm != "" {
+			return errors.ErrorPathf("hmac.algorithm", "unsupported algorithm %q", o.HMAC.Algorithm)
+		}
+		switch o.HMAC.Encoding {
+		case "", "hex", "base64":
+		default:
+			return errors.ErrorPathf("hmac.encoding", "encoding must be hex or base64 but got %q", o.HMAC.Encoding)
+		}
+	case o.SigV4 != nil:
+		if err := o.SigV4.validate(); err != nil {
+			return errors.WithPath(err, "sigv4")
+		}
+	}
+	return nil
+}
+
+// apply sets the credentials to req and returns the header names which contain the credentials.
+func (o *AuthOption) apply(ctx *context.Context, req *http.Request) ([]string, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	switch {
+	case o.Basic != nil:
+		req.SetBasicAuth(o.Basic.Username, o.Basic.Password)
+		return []string{"Authorization"}, nil
+	case o.Bearer != nil:
+		req.Header.Set("Authorization", "Bearer "+o.Bearer.Token)
+		return []string{"Authorization"}, nil
+	case o.OAuth2 != nil:
+		tok, err := httpProtocol.tokens.get(ctx, o.OAuth2)
+		if err != nil {
+			return nil, errors.WithPath(err, "oauth2")
+		}
+		req.Header.Set("Authorization", tok.tokenType+" "+tok.accessToken)
+		return []string{"Authorization"}, nil
+	case o.SigV4 != nil:
+		names, err := o.SigV4.sign(req)
+		if err != nil {
+			return nil, errors.WithPath(err, "sigv4")
+		}
+		return names, nil
+	default:
+		sig, err := o.HMAC.sign(req)
+		if err != nil {
+			return nil, errors.WithPath(err, "hmac")
+		}
+		header := o.HMAC.Header
+		if header == "" {
+			header = "X-Signature"
+		}
+		req.Header.Set(header, o.HMAC.Prefix+sig)
+		return []string{header}, nil
+	}
+}
+
+func (o *HMACOption) sign(req *http.Request) (string, error) {
+	var body []byte
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get request body")
+		}
+		defer rc.Close()
+		body, err = io.ReadAll(rc)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read request body")
+		}
+	}
+	newHash := hmacAlgorithms["sha256"]
+	if o.Algorithm != "" {
+		newHash = hmacAlgorithms[o.Algorithm]
+	}
+	mac := hmac.New(newHash, []byte(o.Key))
+	mac.Write([]byte(req.Method + "\n" + req.URL.RequestURI() + "\n"))
+	mac.Write(body)
+	sum := mac.Sum(nil)
+	if o.Encoding == "base64" {
+		return base64.StdEncoding.EncodeToString(sum), nil
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// redactHeader returns a copy of h whose values of the given names are redacted.
+// The authentication scheme (e.g., Bearer) is kept to show which method is used.
+// Only the signature is redacted for AWS Signature Version 4.
+func redactHeader(h http.Header, names []string) http.Header {
+	if len(names) == 0 {
+		return h
+	}
+	h = h.Clone()
+	for _, name := range names {
+		vs := h.Values(name)
+		if len(vs) == 0 {
+			continue
+		}
+		redactedValues := make([]string, len(vs))
+		for i, v := range vs {
+			scheme, _, ok := strings.Cut(v, " ")
+			switch {
+			case ok && name == "Authorization" && scheme == sigV4Algorithm:
+				redactedValues[i] = redactSigV4Signature(v)
+			case ok && name == "Authorization":
+				redactedValues[i] = scheme + " " + redacted
+			default:
+				redactedValues[i] = redacted
+			}
+		}
+		h[http.CanonicalHeaderKey(name)] = redactedValues
+	}
+	return h
+}
+
+type oauth2Token struct {
+	accessToken string
+	tokenType   string
+	expiry      time.Time
+}
+
+// expiryDelta is the margin to refresh the token before it expires.
+const expiryDelta = 10 * time.Second
+
+func (t *oauth2Token) valid() bool {
+	return t.expiry.IsZero() || time.Now().Add(expiryDelta).Before(t.expiry)
+}
+
+// tokenCache caches the OAuth 2.0 access tokens during a run.
+type tokenCache struct {
+	m       sync.Mutex
+	entries map[string]*tokenEntry
+}
+
+// tokenEntry holds the token of a cache key.
+// Its mutex serializes the fetches of the same token without blocking the ones of the other tokens.
+type tokenEntry struct {
+	m   sync.Mutex
+	tok *oauth2Token
+}
+
+func (c *tokenCache) get(ctx *context.Context, o *OAuth2Option) (*oauth2Token, error) {
+	key := tokenKey(o)
+	c.m.Lock()
+	if c.entries == nil {
+		c.entries = map[string]*tokenEntry{}
+	}
+	e, ok := c.entries[key]
+	if !ok {
+		e = &tokenEntry{}
+		c.entries[key] = e
+	}
+	c.m.Unlock()
+
+	e.m.Lock()
+	defer e.m.Unlock()
+	if e.tok != nil && e.tok.valid() {
+		return e.tok, nil
+	}
+	tok, err := fetchToken(ctx, tokenClient(), o)
+	if err != nil {
+		return nil, err
+	}
+	e.tok = tok
+	return tok, nil
+}
+
+// tokenKey returns the cache key of the token which identifies the token request.
+func tokenKey(o *OAuth2Option) string {
+	params := url.Values{}
+	for k, v := range o.Params {
+		params.Set(k, v)
+	}
+	// Encode sorts the parameters by key
+	return strings.Join([]string{o.TokenURL, o.ClientID, o.ClientSecret, strings.Join(o.Scopes, " "), o.AuthStyle, params.Encode()}, "\x00")
+}
+
+func (c *tokenCache) clear() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.entries = nil
+}
+
+// tokenClient returns the client to fetch tokens.
+// It uses the transport configured by protocols.http.client but not the settings of the step (e.g., the custom client and the cookie jar).
+func tokenClient() *http.Client {
+	c := httpProtocol.getClient()
+	client := &http.Client{
+		Transport: c.getRoundTripper("", nil),
+	}
+	if c != nil {
+		client.Timeout = c.timeout
+	}
+	return client
+}
+
+func fetchToken(ctx *context.Context, client *http.Client, o *OAuth2Option) (*oauth2Token, error) {
+	params := url.Values{}
+	for k, v := range o.Params {
+		params.Set(k, v)
+	}
+	params.Set("grant_type", "client_credentials")
+	if len(o.Scopes) > 0 {
+		params.Set("scope", strings.Join(o.Scopes, " "))
+	}
+	if o.AuthStyle == "params" {
+		params.Set("client_id", o.ClientID)
+		params.Set("client_secret", o.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx.RequestContext(), http.MethodPost, o.TokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, errors.WrapPath(err, "tokenURL", "failed to create token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if o.AuthStyle != "params" {
+		req.SetBasicAuth(url.QueryEscape(o.ClientID), url.QueryEscape(o.ClientSecret))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Errorf("failed to fetch token: %s", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Errorf("failed to read token response: %s", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("failed to fetch token: %s: %s", resp.Status, string(b))
+	}
+
+	var res struct {
+		AccessToken string      `json:"access_token"`
+		TokenType   string      `json:"token_type"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(b)).Decode(&res); err != nil {
+		return nil, errors.Errorf("failed to decode token response: %s", err)
+	}
+	if res.AccessToken == "" {
+		return nil, errors.New("token response doesn't contain access_token")
+	}
+	tok := &oauth2Token{
+		accessToken: res.AccessToken,
+		tokenType:   res.TokenType,
+	}
+	if tok.tokenType == "" || strings.EqualFold(tok.tokenType, "bearer") {
+		tok.tokenType = "Bearer"
+	}
+	if res.ExpiresIn != "" {
+		sec, err := res.ExpiresIn.Int64()
+		if err != nil {
+			return nil, errors.Errorf("invalid expires_in: %s", res.ExpiresIn)
+		}
+		if sec > 0 {
+			tok.expiry = time.Now().Add(time.Duration(sec) * time.Second)
+		}
+	}
+	return tok, nil
+}
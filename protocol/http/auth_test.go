@@ -0,0 +1,448 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/scenarigo/scenarigo/context"
+)
+
+func TestAuthOption_Validate(t *testing.T) {
+	tests := map[string]struct {
+		auth   *AuthOption
+		expect string
+	}{
+		"ok": {
+			auth: &AuthOption{Bearer: &BearerAuthOption{Token: "TOKEN"}},
+		},
+		"empty": {
+			auth:   &AuthOption{},
+			expect: "one of basic, bearer, oauth2, hmac, or sigv4 must be specified",
+		},
+		"multiple methods": {
+			auth: &AuthOption{
+				Basic:  &BasicAuthOption{Username: "user"},
+				Bearer: &BearerAuthOption{Token: "TOKEN"},
+			},
+			expect: "one of basic, bearer, oauth2, hmac, or sigv4 must be specified",
+		},
+		"no username": {
+			auth:   &AuthOption{Basic: &BasicAuthOption{Password: "pass"}},
+			expect: ".basic.username: username is required",
+		},
+		"no token": {
+			auth:   &AuthOption{Bearer: &BearerAuthOption{}},
+			expect: ".bearer.token: token is required",
+		},
+		"no token URL": {
+			auth:   &AuthOption{OAuth2: &OAuth2Option{ClientID: "id"}},
+			expect: ".oauth2.tokenURL: token URL is required",
+		},
+		"no client ID": {
+			auth:   &AuthOption{OAuth2: &OAuth2Option{TokenURL: "http://localhost/token"}},
+			expect: ".oauth2.clientID: client ID is required",
+		},
+		"invalid auth style": {
+			auth:   &AuthOption{OAuth2: &OAuth2Option{TokenURL: "http://localhost/token", ClientID: "id", AuthStyle: "body"}},
+			expect: `.oauth2.authStyle: auth style must be header or params but got "body"`,
+		},
+		"no key": {
+			auth:   &AuthOption{HMAC: &HMACOption{}},
+			expect: ".hmac.key: key is required",
+		},
+		"unsupported algorithm": {
+			auth:   &AuthOption{HMAC: &HMACOption{Key: "secret", Algorithm: "md5"}},
+			expect: `.hmac.algorithm: unsupported algorithm "md5"`,
+		},
+		"no region": {
+			auth:   &AuthOption{SigV4: &SigV4Option{Service: "execute-api", AccessKeyID: "AKID", SecretAccessKey: "SECRET"}},
+			expect: ".sigv4.region: region is required",
+		},
+		"no secret access key": {
+			auth:   &AuthOption{SigV4: &SigV4Option{Region: "us-east-1", Service: "execute-api", AccessKeyID: "AKID"}},
+			expect: ".sigv4.secretAccessKey: secret access key is required",
+		},
+		"invalid encoding": {
+			auth:   &AuthOption{HMAC: &HMACOption{Key: "secret", Encoding: "base32"}},
+			expect: `.hmac.encoding: encoding must be hex or base64 but got "base32"`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.auth.validate()
+			if test.expect == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if got := err.Error(); !strings.Contains(got, test.expect) {
+				t.Errorf("%q doesn't contain %q", got, test.expect)
+			}
+		})
+	}
+}
+
+func TestRequest_Invoke_Auth(t *testing.T) {
+	hmacSum := func(key, msg string, sha512Hash bool) []byte {
+		newHash := sha256.New
+		if sha512Hash {
+			newHash = sha512.New
+		}
+		mac := hmac.New(newHash, []byte(key))
+		mac.Write([]byte(msg))
+		return mac.Sum(nil)
+	}
+
+	m := http.NewServeMux()
+	m.HandleFunc("/echo", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Authorization", req.Header.Get("Authorization"))
+		w.Header().Set("X-Sig", req.Header.Get("X-Signature")+req.Header.Get("X-Hub-Signature"))
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(m)
+	t.Cleanup(srv.Close)
+
+	tests := map[string]struct {
+		option     string
+		auth       *AuthOption
+		vars       map[string]string
+		method     string
+		body       interface{}
+		expectAuth string
+		expectSig  string
+		expectDump map[string]string
+	}{
+		"basic": {
+			auth:       &AuthOption{Basic: &BasicAuthOption{Username: "user", Password: "{{vars.password}}"}},
+			vars:       map[string]string{"password": "pass"},
+			expectAuth: "Basic dXNlcjpwYXNz",
+			expectDump: map[string]string{"Authorization": "Basic *****"},
+		},
+		"bearer": {
+			auth:       &AuthOption{Bearer: &BearerAuthOption{Token: "{{vars.token}}"}},
+			vars:       map[string]string{"token": "TOKEN"},
+			expectAuth: "Bearer TOKEN",
+			expectDump: map[string]string{"Authorization": "Bearer *****"},
+		},
+		"default": {
+			option:     "auth:\n  bearer:\n    token: DEFAULT",
+			expectAuth: "Bearer DEFAULT",
+			expectDump: map[string]string{"Authorization": "Bearer *****"},
+		},
+		"override default": {
+			option:     "auth:\n  bearer:\n    token: DEFAULT",
+			auth:       &AuthOption{Bearer: &BearerAuthOption{Token: "TOKEN"}},
+			expectAuth: "Bearer TOKEN",
+			expectDump: map[string]string{"Authorization": "Bearer *****"},
+		},
+		"hmac": {
+			auth:       &AuthOption{HMAC: &HMACOption{Key: "secret"}},
+			method:     http.MethodPost,
+			body:       map[string]string{"message": "hello"},
+			expectSig:  hex.EncodeToString(hmacSum("secret", "POST\n/echo?q=1\n{\"message\": \"hello\"}\n", false)),
+			expectDump: map[string]string{"X-Signature": "*****"},
+		},
+		"hmac (options)": {
+			auth: &AuthOption{HMAC: &HMACOption{
+				Key:       "secret",
+				Algorithm: "sha512",
+				Header:    "X-Hub-Signature",
+				Prefix:    "sha512=",
+				Encoding:  "base64",
+			}},
+			expectSig:  "sha512=" + base64.StdEncoding.EncodeToString(hmacSum("secret", "GET\n/echo?q=1\n", true)),
+			expectDump: map[string]string{"X-Hub-Signature": "*****"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			setClientOption(t, test.option)
+			ctx := context.FromT(t)
+			if test.vars != nil {
+				ctx = ctx.WithVars(test.vars)
+			}
+			req := &Request{
+				Method: test.method,
+				URL:    srv.URL + "/echo?q=1",
+				Body:   test.body,
+				Auth:   test.auth,
+			}
+			ctx, res, err := req.Invoke(ctx)
+			if err != nil {
+				t.Fatalf("failed to invoke: %s", err)
+			}
+			resp, ok := res.(response)
+			if !ok {
+				t.Fatalf("failed to convert from %T to response", res)
+			}
+			if got, expect := http.Header(resp.Header).Get("X-Authorization"), test.expectAuth; got != expect {
+				t.Errorf("expect Authorization %q but got %q", expect, got)
+			}
+			if got, expect := http.Header(resp.Header).Get("X-Sig"), test.expectSig; got != expect {
+				t.Errorf("expect signature %q but got %q", expect, got)
+			}
+			dump, ok := ctx.Request().(*RequestExtractor)
+			if !ok {
+				t.Fatalf("failed to convert from %T to *RequestExtractor", ctx.Request())
+			}
+			h, ok := dump.Header.(http.Header)
+			if !ok {
+				t.Fatalf("failed to convert from %T to http.Header", dump.Header)
+			}
+			for k, v := range test.expectDump {
+				if got := h.Get(k); got != v {
+					t.Errorf("expect %s %q in the request dump but got %q", k, v, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRequest_Invoke_OAuth2(t *testing.T) {
+	var (
+		fetched   atomic.Int32
+		expiresIn = 3600
+	)
+	m := http.NewServeMux()
+	m.HandleFunc("/oauth/token", func(w http.ResponseWriter, req *http.Request) {
+		fetched.Add(1)
+		if err := req.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if req.PostForm.Get("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"unsupported_grant_type"}`))
+			return
+		}
+		id, secret, ok := req.BasicAuth()
+		if !ok {
+			id, secret = req.PostForm.Get("client_id"), req.PostForm.Get("client_secret")
+		}
+		if id != "CLIENT_ID" || secret != "CLIENT_SECRET" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fmt.Sprintf(
+			`{"access_token":"TOKEN-%d-%s-%s","token_type":"bearer","expires_in":%d}`,
+			fetched.Load(), req.PostForm.Get("scope"), req.PostForm.Get("audience"), expiresIn,
+		)))
+	})
+	var (
+		slowTokenRequested = make(chan struct{})
+		releaseSlowToken   = make(chan struct{})
+	)
+	m.HandleFunc("/oauth/slow-token", func(w http.ResponseWriter, req *http.Request) {
+		close(slowTokenRequested)
+		<-releaseSlowToken
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"SLOW-TOKEN"}`))
+	})
+	m.HandleFunc("/echo", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Authorization", req.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(m)
+	t.Cleanup(srv.Close)
+
+	invoke := func(t *testing.T, auth *AuthOption) (string, error) {
+		t.Helper()
+		_, res, err := (&Request{URL: srv.URL + "/echo", Auth: auth}).Invoke(context.FromT(t))
+		if err != nil {
+			return "", err
+		}
+		resp, ok := res.(response)
+		if !ok {
+			t.Fatalf("failed to convert from %T to response", res)
+		}
+		return http.Header(resp.Header).Get("X-Authorization"), nil
+	}
+
+	t.Run("cache", func(t *testing.T) {
+		t.Cleanup(func() { httpProtocol.tokens.clear(); fetched.Store(0) })
+		auth := &AuthOption{OAuth2: &OAuth2Option{
+			TokenURL:     srv.URL + "/oauth/token",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+			Scopes:       []string{"read", "write"},
+			Params:       map[string]string{"audience": "api"},
+		}}
+		for range 2 {
+			got, err := invoke(t, auth)
+			if err != nil {
+				t.Fatalf("failed to invoke: %s", err)
+			}
+			if expect := "Bearer TOKEN-1-read write-api"; got != expect {
+				t.Errorf("expect %q but got %q", expect, got)
+			}
+		}
+		if got := fetched.Load(); got != 1 {
+			t.Errorf("expect the token is fetched once but fetched %d times", got)
+		}
+	})
+
+	t.Run("cache for each audience", func(t *testing.T) {
+		t.Cleanup(func() { httpProtocol.tokens.clear(); fetched.Store(0) })
+		for _, test := range []struct {
+			audience string
+			expect   string
+		}{
+			{audience: "api", expect: "Bearer TOKEN-1--api"},
+			{audience: "admin", expect: "Bearer TOKEN-2--admin"},
+			// the token of the first audience is reused
+			{audience: "api", expect: "Bearer TOKEN-1--api"},
+		} {
+			got, err := invoke(t, &AuthOption{OAuth2: &OAuth2Option{
+				TokenURL:     srv.URL + "/oauth/token",
+				ClientID:     "CLIENT_ID",
+				ClientSecret: "CLIENT_SECRET",
+				Params:       map[string]string{"audience": test.audience},
+			}})
+			if err != nil {
+				t.Fatalf("failed to invoke: %s", err)
+			}
+			if got != test.expect {
+				t.Errorf("expect %q but got %q", test.expect, got)
+			}
+		}
+		if got := fetched.Load(); got != 2 {
+			t.Errorf("expect the token is fetched for each audience but fetched %d times", got)
+		}
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		t.Cleanup(func() { httpProtocol.tokens.clear(); fetched.Store(0); expiresIn = 3600 })
+		expiresIn = 1 // shorter than expiryDelta
+		auth := &AuthOption{OAuth2: &OAuth2Option{
+			TokenURL:     srv.URL + "/oauth/token",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+			AuthStyle:    "params",
+		}}
+		for i := range 2 {
+			got, err := invoke(t, auth)
+			if err != nil {
+				t.Fatalf("failed to invoke: %s", err)
+			}
+			if expect := fmt.Sprintf("Bearer TOKEN-%d--", i+1); got != expect {
+				t.Errorf("expect %q but got %q", expect, got)
+			}
+		}
+	})
+
+	t.Run("not use the client of the step", func(t *testing.T) {
+		t.Cleanup(func() { httpProtocol.tokens.clear(); fetched.Store(0) })
+		var sent atomic.Int32
+		client := &http.Client{
+			Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				sent.Add(1)
+				return http.DefaultTransport.RoundTrip(req)
+			}),
+		}
+		ctx := context.FromT(t).WithVars(map[string]interface{}{"client": client})
+		_, _, err := (&Request{
+			Client: "{{vars.client}}",
+			URL:    srv.URL + "/echo",
+			Auth: &AuthOption{OAuth2: &OAuth2Option{
+				TokenURL:     srv.URL + "/oauth/token",
+				ClientID:     "CLIENT_ID",
+				ClientSecret: "CLIENT_SECRET",
+			}},
+		}).Invoke(ctx)
+		if err != nil {
+			t.Fatalf("failed to invoke: %s", err)
+		}
+		if got := fetched.Load(); got != 1 {
+			t.Errorf("expect the token is fetched once but fetched %d times", got)
+		}
+		if got := sent.Load(); got != 1 {
+			t.Errorf("expect only the request of the step is sent by the client but sent %d requests", got)
+		}
+	})
+
+	t.Run("fetch without blocking the other tokens", func(t *testing.T) {
+		t.Cleanup(func() { httpProtocol.tokens.clear(); fetched.Store(0) })
+		done := make(chan error, 1)
+		go func() {
+			_, err := invoke(t, &AuthOption{OAuth2: &OAuth2Option{
+				TokenURL: srv.URL + "/oauth/slow-token",
+				ClientID: "CLIENT_ID",
+			}})
+			done <- err
+		}()
+		<-slowTokenRequested
+		got, err := invoke(t, &AuthOption{OAuth2: &OAuth2Option{
+			TokenURL:     srv.URL + "/oauth/token",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "CLIENT_SECRET",
+		}})
+		close(releaseSlowToken)
+		if err != nil {
+			t.Fatalf("failed to invoke: %s", err)
+		}
+		if expect := "Bearer TOKEN-1--"; got != expect {
+			t.Errorf("expect %q but got %q", expect, got)
+		}
+		if err := <-done; err != nil {
+			t.Fatalf("failed to invoke with the slow token: %s", err)
+		}
+	})
+
+	t.Run("invalid client", func(t *testing.T) {
+		t.Cleanup(func() { httpProtocol.tokens.clear(); fetched.Store(0) })
+		_, err := invoke(t, &AuthOption{OAuth2: &OAuth2Option{
+			TokenURL:     srv.URL + "/oauth/token",
+			ClientID:     "CLIENT_ID",
+			ClientSecret: "INVALID",
+		}})
+		if err == nil {
+			t.Fatal("no error")
+		}
+		if got, expect := err.Error(), `.auth.oauth2: failed to fetch token: 401 Unauthorized: {"error":"invalid_client"}`; !strings.Contains(got, expect) {
+			t.Errorf("%q doesn't contain %q", got, expect)
+		}
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRedactHeader(t *testing.T) {
+	h := http.Header{
+		"Authorization": []string{"Bearer TOKEN"},
+		"X-Api-Key":     []string{"KEY"},
+		"Content-Type":  []string{"application/json"},
+	}
+	got := redactHeader(h, []string{"Authorization", "X-Api-Key"})
+	expect := http.Header{
+		"Authorization": []string{"Bearer *****"},
+		"X-Api-Key":     []string{"*****"},
+		"Content-Type":  []string{"application/json"},
+	}
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Errorf("differs (-want +got):\n%s", diff)
+	}
+	if got, expect := h.Get("Authorization"), "Bearer TOKEN"; got != expect {
+		t.Errorf("the original header is modified: expect %q but got %q", expect, got)
+	}
+}
@@ -0,0 +1,289 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/internal/filepathutil"
+	"github.com/scenarigo/scenarigo/internal/netutil"
+	"github.com/scenarigo/scenarigo/schema"
+)
+
+var tlsVers = map[string]uint16{
+	tls.VersionName(tls.VersionTLS10): tls.VersionTLS10,
+	tls.VersionName(tls.VersionTLS11): tls.VersionTLS11,
+	tls.VersionName(tls.VersionTLS12): tls.VersionTLS12,
+	tls.VersionName(tls.VersionTLS13): tls.VersionTLS13,
+}
+
+// ClientOption represents an option for the HTTP client.
+type ClientOption struct {
+	// Timeout is the time limit for a request including redirects and reading the response body (e.g., 30s).
+	Timeout *schema.Duration `yaml:"timeout,omitempty"`
+	// Proxy is the URL of the proxy server.
+	// If not specified, the proxy is determined by the environment variables (HTTP_PROXY, HTTPS_PROXY, and NO_PROXY).
+	Proxy     string           `yaml:"proxy,omitempty"`
+	TLS       *TLSOption       `yaml:"tls,omitempty"`
+	Redirect  *RedirectOption  `yaml:"redirect,omitempty"`
+	Transport *TransportOption `yaml:"transport,omitempty"`
+	// CookieJar enables the cookie jar which is shared across the steps of a scenario.
+	// The cookies in the jar can be referred as {{cookies.<name>}}.
+	CookieJar bool `yaml:"cookieJar,omitempty"`
+}
+
+// TLSOption represents a TLS option.
+type TLSOption struct {
+	MinVersion string `yaml:"minVersion,omitempty"`
+	MaxVersion string `yaml:"maxVersion,omitempty"`
+	// Certificate is the file path of the CA certificates to verify the server certificate.
+	// The relative file paths are resolved from the directory of the configuration file.
+	Certificate       string `yaml:"certificate,omitempty"`
+	ClientCertificate string `yaml:"clientCertificate,omitempty"`
+	ClientKey         string `yaml:"clientKey,omitempty"`
+	ServerName        string `yaml:"serverName,omitempty"`
+	Skip              bool   `yaml:"skip,omitempty"`
+}
+
+// RedirectOption represents an option for the redirect policy.
+type RedirectOption struct {
+	// Disabled disables following redirects. The redirect response is returned as it is.
+	Disabled bool `yaml:"disabled,omitempty"`
+	// Max is the maximum number of redirects to follow (default: 10).
+	Max *int `yaml:"max,omitempty"`
+}
+
+// TransportOption represents an option for the connections.
+type TransportOption struct {
+	MaxIdleConns        *int             `yaml:"maxIdleConns,omitempty"`
+	MaxIdleConnsPerHost *int             `yaml:"maxIdleConnsPerHost,omitempty"`
+	MaxConnsPerHost     *int             `yaml:"maxConnsPerHost,omitempty"`
+	IdleConnTimeout     *schema.Duration `yaml:"idleConnTimeout,omitempty"`
+	DisableKeepAlives   bool             `yaml:"disableKeepAlives,omitempty"`
+	DisableHTTP2        bool             `yaml:"disableHTTP2,omitempty"`
+	// H2C sends the requests to http:// URLs with HTTP/2 over cleartext (prior knowledge) instead of HTTP/1.1.
+	H2C bool `yaml:"h2c,omitempty"`
+}
+
+// client holds the HTTP client settings built from the option.
+type client struct {
+	transport     *http.Transport
+	h2c           bool
+	roundTripper  http.RoundTripper
+	timeout       time.Duration
+	checkRedirect func(*http.Request, []*http.Request) error
+	cookieJar     bool
+
+	m sync.Mutex
+	// resolverRoundTrippers caches the round trippers for the resolvers by the string representations.
+	resolverRoundTrippers map[string]http.RoundTripper
+}
+
+func (o *ClientOption) build(root string) (*client, error) {
+	c := &client{}
+	if o == nil {
+		return c, nil
+	}
+	c.cookieJar = o.CookieJar
+	if o.Timeout != nil {
+		c.timeout = time.Duration(*o.Timeout)
+	}
+	if o.Redirect != nil {
+		if o.Redirect.Max != nil && *o.Redirect.Max < 0 {
+			return nil, errors.ErrorPathf("redirect.max", "max must be greater than or equal to 0 but got %d", *o.Redirect.Max)
+		}
+		c.checkRedirect = o.Redirect.checkRedirect()
+	}
+	if o.Proxy == "" && o.TLS == nil && o.Transport == nil {
+		return c, nil
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	if o.Proxy != "" {
+		u, err := url.Parse(o.Proxy)
+		if err != nil {
+			return nil, errors.WrapPath(err, "proxy", "invalid proxy URL")
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+	if o.TLS != nil {
+		cfg, err := o.TLS.build(root)
+		if err != nil {
+			return nil, errors.WithPath(err, "tls")
+		}
+		t.TLSClientConfig = cfg
+	}
+	if o.Transport != nil {
+		if err := o.Transport.apply(t); err != nil {
+			return nil, errors.WithPath(err, "transport")
+		}
+		if o.Transport.H2C {
+			if o.Transport.DisableHTTP2 {
+				return nil, errors.ErrorPath("transport.h2c", "h2c can't be enabled with disableHTTP2")
+			}
+			c.h2c = true
+		}
+	}
+	c.transport = t
+	c.roundTripper = t
+	if c.h2c {
+		c.roundTripper = newH2CRoundTripper(t, t.DialContext)
+	}
+	return c, nil
+}
+
+// getRoundTripper returns the round tripper to send requests.
+// If socket is specified, it returns a new one which connects to the Unix domain socket.
+// The caller must close its idle connections after use.
+// If resolver is specified, it returns the one which connects to the overridden addresses.
+// It is created once for each resolver and reused to keep the connections alive.
+func (c *client) getRoundTripper(socket string, resolver netutil.Resolver) http.RoundTripper {
+	if socket == "" && len(resolver) == 0 {
+		if c == nil || c.roundTripper == nil {
+			return http.DefaultTransport
+		}
+		return c.roundTripper
+	}
+	if socket != "" {
+		return c.newRoundTripper(unixSocketTransport(c.baseTransport(), socket))
+	}
+	key := resolver.String()
+	c.m.Lock()
+	defer c.m.Unlock()
+	if rt, ok := c.resolverRoundTrippers[key]; ok {
+		return rt
+	}
+	rt := c.newRoundTripper(resolverTransport(c.baseTransport(), resolver))
+	if c.resolverRoundTrippers == nil {
+		c.resolverRoundTrippers = map[string]http.RoundTripper{}
+	}
+	c.resolverRoundTrippers[key] = rt
+	return rt
+}
+
+func (c *client) baseTransport() *http.Transport {
+	if c != nil && c.transport != nil {
+		return c.transport
+	}
+	return http.DefaultTransport.(*http.Transport) //nolint:forcetypeassert
+}
+
+func (c *client) newRoundTripper(t *http.Transport) http.RoundTripper {
+	if c != nil && c.h2c {
+		return newH2CRoundTripper(t, t.DialContext)
+	}
+	return t
+}
+
+func (c *client) closeIdleConnections() {
+	if c == nil {
+		return
+	}
+	if c.roundTripper != nil {
+		closeIdleConnections(c.roundTripper)
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	for _, rt := range c.resolverRoundTrippers {
+		closeIdleConnections(rt)
+	}
+}
+
+// build builds the TLS config. The relative file paths are resolved from the root directory.
+func (o *TLSOption) build(root string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if o.MinVersion != "" {
+		v, ok := tlsVers[o.MinVersion]
+		if !ok {
+			return nil, errors.ErrorPathf("minVersion", "invalid minimum TLS version %s", o.MinVersion)
+		}
+		cfg.MinVersion = v
+	}
+	if o.MaxVersion != "" {
+		v, ok := tlsVers[o.MaxVersion]
+		if !ok {
+			return nil, errors.ErrorPathf("maxVersion", "invalid maximum TLS version %s", o.MaxVersion)
+		}
+		cfg.MaxVersion = v
+	}
+	if o.Certificate != "" {
+		b, err := os.ReadFile(filepathutil.From(root, o.Certificate))
+		if err != nil {
+			return nil, errors.WrapPath(err, "certificate", "failed to read certificate")
+		}
+		cp := x509.NewCertPool()
+		if !cp.AppendCertsFromPEM(b) {
+			return nil, errors.ErrorPath("certificate", "failed to append certificate")
+		}
+		cfg.RootCAs = cp
+	}
+	switch {
+	case o.ClientCertificate != "" && o.ClientKey == "":
+		return nil, errors.ErrorPath("clientCertificate", "client certificate must be specified with client key")
+	case o.ClientCertificate == "" && o.ClientKey != "":
+		return nil, errors.ErrorPath("clientKey", "client key must be specified with client certificate")
+	case o.ClientCertificate != "":
+		cert, err := tls.LoadX509KeyPair(filepathutil.From(root, o.ClientCertificate), filepathutil.From(root, o.ClientKey))
+		if err != nil {
+			return nil, errors.WrapPath(err, "clientCertificate", "failed to load client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if o.ServerName != "" {
+		cfg.ServerName = o.ServerName
+	}
+	if o.Skip {
+		cfg.InsecureSkipVerify = true
+	}
+	return cfg, nil
+}
+
+// checkRedirect returns the redirect policy.
+// It returns nil to use the default policy which stops after 10 redirects.
+func (o *RedirectOption) checkRedirect() func(*http.Request, []*http.Request) error {
+	if o.Disabled {
+		return func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	if o.Max == nil {
+		return nil
+	}
+	maxRedirects := *o.Max
+	return func(_ *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return errors.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+}
+
+func (o *TransportOption) apply(t *http.Transport) error {
+	if o.MaxIdleConns != nil {
+		t.MaxIdleConns = *o.MaxIdleConns
+	}
+	if o.MaxIdleConnsPerHost != nil {
+		t.MaxIdleConnsPerHost = *o.MaxIdleConnsPerHost
+	}
+	if o.MaxConnsPerHost != nil {
+		t.MaxConnsPerHost = *o.MaxConnsPerHost
+	}
+	if o.IdleConnTimeout != nil {
+		t.IdleConnTimeout = time.Duration(*o.IdleConnTimeout)
+	}
+	if o.DisableKeepAlives {
+		t.DisableKeepAlives = true
+	}
+	if o.DisableHTTP2 {
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/scenarigo/scenarigo/errors"
+)
+
+// curlCommand returns the curl command line which sends the same request as req.
+// The header is passed separately to use the one whose credentials are redacted.
+// The flags (e.g., --unix-socket) are added after the URL as they are.
+// The parts of multipart/form-data bodies are written as -F options and binary bodies are omitted.
+func curlCommand(req *http.Request, header http.Header, flags ...string) (string, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return "", err
+	}
+	form, isForm := curlFormArgs(header.Get("Content-Type"), body)
+
+	args := []string{
+		fmt.Sprintf("curl -X %s %s", req.Method, shellQuote(req.URL.String())),
+	}
+	args = append(args, flags...)
+	if req.Host != "" && req.Host != req.URL.Host {
+		args = append(args, "-H "+shellQuote("Host: "+req.Host))
+	}
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		// curl sets the Content-Type header which has its own boundary for -F options
+		if isForm && http.CanonicalHeaderKey(k) == "Content-Type" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range header[k] {
+			args = append(args, "-H "+shellQuote(k+": "+v))
+		}
+	}
+	switch {
+	case isForm:
+		args = append(args, form...)
+	case isBinary(body):
+		// the comment must be at the end of the command line
+		args = append(args, fmt.Sprintf("--data-binary @- # binary body (%d bytes) is omitted", len(body)))
+	case len(body) > 0:
+		args = append(args, "--data-binary "+shellQuote(string(body)))
+	}
+	return strings.Join(args, " \\\n  "), nil
+}
+
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.GetBody == nil {
+		return nil, nil
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get request body")
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read request body")
+	}
+	return b, nil
+}
+
+// curlFormArgs returns the -F options to send the multipart/form-data body.
+// The files are referred by their filenames since the contents are not written in the command line.
+// It returns false if the body is not a valid multipart/form-data body.
+func curlFormArgs(contentType string, body []byte) ([]string, bool) {
+	mt, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mt != "multipart/form-data" || params["boundary"] == "" {
+		return nil, false
+	}
+	var args []string
+	r := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if errors.Is(err, io.EOF) {
+			return args, true
+		}
+		if err != nil {
+			return nil, false
+		}
+		if p.FileName() != "" {
+			v := p.FormName() + "=@" + p.FileName()
+			if ct := p.Header.Get("Content-Type"); ct != "" {
+				v += ";type=" + ct
+			}
+			args = append(args, "-F "+shellQuote(v))
+			continue
+		}
+		b, err := io.ReadAll(p)
+		if err != nil {
+			return nil, false
+		}
+		// --form-string doesn't treat the values which start with @ or < as files
+		args = append(args, "--form-string "+shellQuote(p.FormName()+"="+string(b)))
+	}
+}
+
+// isBinary reports whether b is a binary data which can't be written in the command line as it is.
+// Tabs and newlines are allowed since they can be written with ANSI-C quoting.
+func isBinary(b []byte) bool {
+	if !utf8.Valid(b) {
+		return true
+	}
+	return strings.ContainsFunc(string(b), func(r rune) bool {
+		return isControl(r) && r != '\t' && r != '\n' && r != '\r'
+	})
+}
+
+// shellQuote quotes s as a word of the shell (e.g., bash, zsh).
+// It uses ANSI-C quoting ($'...') if s contains control characters (e.g., newlines) or invalid UTF-8 sequences
+// to write the command in a line.
+func shellQuote(s string) string {
+	if utf8.ValidString(s) && !strings.ContainsFunc(s, isControl) {
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+	var b strings.Builder
+	b.WriteString("$'")
+	for i := range len(s) {
+		c := s[i]
+		switch {
+		case c == '\\' || c == '\'':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteString("'")
+	return b.String()
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCurlCommand(t *testing.T) {
+	tests := map[string]struct {
+		method string
+		url    string
+		host   string
+		header http.Header
+		body   []byte
+		flags  []string
+		expect string
+	}{
+		"GET": {
+			method: http.MethodGet,
+			url:    "http://localhost/echo?q=a%20b",
+			expect: `curl -X GET 'http://localhost/echo?q=a%20b'`,
+		},
+		"headers": {
+			method: http.MethodGet,
+			url:    "http://localhost/echo",
+			host:   "example.com",
+			header: http.Header{
+				"X-B": []string{"it's"},
+				"X-A": []string{"1", "2"},
+			},
+			expect: `curl -X GET 'http://localhost/echo' \
+  -H 'Host: example.com' \
+  -H 'X-A: 1' \
+  -H 'X-A: 2' \
+  -H 'X-B: it'\''s'`,
+		},
+		"body": {
+			method: http.MethodPost,
+			url:    "http://localhost/echo",
+			body:   []byte(`{"message":"it's"}`),
+			expect: `curl -X POST 'http://localhost/echo' \
+  --data-binary '{"message":"it'\''s"}'`,
+		},
+		"body with control characters": {
+			method: http.MethodPost,
+			url:    "http://localhost/echo",
+			body:   []byte("a\tb\\c'd\r\n"),
+			expect: `curl -X POST 'http://localhost/echo' \
+  --data-binary $'a\tb\\c\'d\r\n'`,
+		},
+		"binary body": {
+			method: http.MethodPost,
+			url:    "http://localhost/echo",
+			header: http.Header{
+				"Content-Type": []string{"application/octet-stream"},
+			},
+			body: []byte("\x00\xff"),
+			expect: `curl -X POST 'http://localhost/echo' \
+  -H 'Content-Type: application/octet-stream' \
+  --data-binary @- # binary body (2 bytes) is omitted`,
+		},
+		"multipart body": {
+			method: http.MethodPost,
+			url:    "http://localhost/upload",
+			header: http.Header{
+				"Content-Type": []string{"multipart/form-data; boundary=BOUNDARY"},
+				"X-A":          []string{"1"},
+			},
+			body: []byte(strings.Join([]string{
+				"--BOUNDARY",
+				`Content-Disposition: form-data; name="name"`,
+				"",
+				"@it's",
+				"--BOUNDARY",
+				`Content-Disposition: form-data; name="file"; filename="image.png"`,
+				"Content-Type: image/png",
+				"",
+				"\x89PNG\x00",
+				"--BOUNDARY--",
+				"",
+			}, "\r\n")),
+			expect: `curl -X POST 'http://localhost/upload' \
+  -H 'X-A: 1' \
+  --form-string 'name=@it'\''s' \
+  -F 'file=@image.png;type=image/png'`,
+		},
+		"flags": {
+			method: http.MethodGet,
+			url:    "http://localhost/echo",
+			header: http.Header{
+				"X-A": []string{"1"},
+			},
+			flags: []string{"--unix-socket '/tmp/app.sock'", "--connect-to 'localhost:80:127.0.0.1:8080'", "--http2-prior-knowledge"},
+			expect: `curl -X GET 'http://localhost/echo' \
+  --unix-socket '/tmp/app.sock' \
+  --connect-to 'localhost:80:127.0.0.1:8080' \
+  --http2-prior-knowledge \
+  -H 'X-A: 1'`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var body io.Reader
+			if test.body != nil {
+				body = bytes.NewReader(test.body)
+			}
+			req, err := http.NewRequest(test.method, test.url, body)
+			if err != nil {
+				t.Fatalf("failed to create request: %s", err)
+			}
+			req.Host = test.host
+			got, err := curlCommand(req, test.header, test.flags...)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.expect {
+				t.Errorf("expect:\n%s\nbut got:\n%s", test.expect, got)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/scenarigo/scenarigo/context"
 	"github.com/scenarigo/scenarigo/errors"
 	"github.com/scenarigo/scenarigo/internal/assertutil"
+	"github.com/scenarigo/scenarigo/internal/fileutil"
 )
 
 // Expect represents expected response values.
@@ -15,6 +16,13 @@ type Expect struct {
 	Code   string        `yaml:"code,omitempty"`
 	Header yaml.MapSlice `yaml:"header,omitempty"`
 	Body   interface{}   `yaml:"body,omitempty"`
+	// BodyFile is the path of the file which has the expected body instead of body. The path is relative to the scenario file.
+	// JSON and YAML files are decoded as body, and the contents of the other files are expected as a string.
+	BodyFile string `yaml:"bodyFile,omitempty"`
+	// Timing is the expected time spent on each phase of the request (e.g., ttfb: '{{$ < duration("200ms")}}').
+	Timing interface{} `yaml:"timing,omitempty"`
+	// TLS is the expected state of the TLS connection (e.g., certificates[0].expiresIn: '{{$ > duration("336h")}}').
+	TLS interface{} `yaml:"tls,omitempty"`
 }
 
 // Build implements protocol.AssertionBuilder interface.
@@ -34,11 +42,37 @@ func (e *Expect) Build(ctx *context.Context) (assert.Assertion, error) {
 		return nil, errors.WrapPathf(err, "header", "invalid expect header")
 	}
 
-	assertion, err := assert.Build(ctx.RequestContext(), e.Body, assert.FromTemplate(ctx))
+	body := e.Body
+	if e.BodyFile != "" {
+		if e.Body != nil {
+			return nil, errors.ErrorPath("bodyFile", "body and bodyFile can't be specified at the same time")
+		}
+		body, err = fileutil.Load(ctx, e.BodyFile, yaml.UseOrderedMap())
+		if err != nil {
+			return nil, errors.WithPath(err, "bodyFile")
+		}
+	}
+	assertion, err := assert.Build(ctx.RequestContext(), body, assert.FromTemplate(ctx))
 	if err != nil {
 		return nil, errors.WrapPathf(err, "body", "invalid expect response body")
 	}
 
+	timingAssertion := assert.Nop()
+	if e.Timing != nil {
+		timingAssertion, err = assert.Build(ctx.RequestContext(), e.Timing, assert.FromTemplate(ctx))
+		if err != nil {
+			return nil, errors.WrapPathf(err, "timing", "invalid expect timing")
+		}
+	}
+
+	tlsAssertion := assert.Nop()
+	if e.TLS != nil {
+		tlsAssertion, err = assert.Build(ctx.RequestContext(), e.TLS, assert.FromTemplate(ctx))
+		if err != nil {
+			return nil, errors.WrapPathf(err, "tls", "invalid expect tls")
+		}
+	}
+
 	return assert.AssertionFunc(func(v interface{}) error {
 		res, ok := v.(response)
 		if !ok {
@@ -53,6 +87,15 @@ func (e *Expect) Build(ctx *context.Context) (assert.Assertion, error) {
 		if err := assertion.Assert(res.Body); err != nil {
 			return errors.WithPath(err, "body")
 		}
+		if err := timingAssertion.Assert(res.Timing); err != nil {
+			return errors.WithPath(err, "timing")
+		}
+		if e.TLS != nil && res.TLS == nil {
+			return errors.ErrorPath("tls", "not a TLS connection")
+		}
+		if err := tlsAssertion.Assert(res.TLS); err != nil {
+			return errors.WithPath(err, "tls")
+		}
 		return nil
 	}), nil
 }
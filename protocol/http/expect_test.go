@@ -2,9 +2,11 @@ package http
 
 import (
 	"testing"
+	"time"
 
 	"github.com/goccy/go-yaml"
 	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/internal/tlsutil"
 )
 
 func TestExpect_Build(t *testing.T) {
@@ -117,6 +119,79 @@ func TestExpect_Build(t *testing.T) {
 					Status: "200 OK",
 				},
 			},
+			"timing": {
+				expect: &Expect{
+					Timing: yaml.MapSlice{
+						yaml.MapItem{
+							Key:   "ttfb",
+							Value: `{{assert.lessThan(duration("200ms"))}}`,
+						},
+						yaml.MapItem{
+							Key:   "total",
+							Value: `{{$ < duration("1s")}}`,
+						},
+					},
+				},
+				response: response{
+					Status: "200 OK",
+					Timing: &Timing{
+						TTFB:  100 * time.Millisecond,
+						Total: 150 * time.Millisecond,
+					},
+				},
+			},
+			"tls": {
+				expect: &Expect{
+					TLS: yaml.MapSlice{
+						yaml.MapItem{
+							Key:   "version",
+							Value: "TLS 1.3",
+						},
+						yaml.MapItem{
+							Key: "certificates",
+							Value: []interface{}{
+								yaml.MapSlice{
+									yaml.MapItem{
+										Key:   "expiresIn",
+										Value: `{{$ > duration("336h")}}`,
+									},
+								},
+							},
+						},
+					},
+				},
+				response: response{
+					Status: "200 OK",
+					TLS: &tlsutil.ConnectionState{
+						Version: "TLS 1.3",
+						Certificates: []*tlsutil.Certificate{
+							{ExpiresIn: 30 * 24 * time.Hour},
+						},
+					},
+				},
+			},
+			"body file": {
+				vars: map[string]string{"id": "1"},
+				expect: &Expect{
+					BodyFile: "testdata/expect_body.json",
+				},
+				response: response{
+					Status: "200 OK",
+					Body: map[string]interface{}{
+						"id":   "1",
+						"tags": []interface{}{"a", "b"},
+					},
+				},
+			},
+			"body file (text)": {
+				expect: &Expect{
+					BodyFile: "testdata/expect_body.txt",
+				},
+				response: response{
+					Status: "200 OK",
+					Body:   "hello",
+				},
+			},
 		}
 		for name, test := range tests {
 			t.Run(name, func(t *testing.T) {
@@ -229,6 +304,87 @@ func TestExpect_Build(t *testing.T) {
 				},
 				expectAssertError: true,
 			},
+			"slow response": {
+				expect: &Expect{
+					Timing: yaml.MapSlice{
+						yaml.MapItem{
+							Key:   "ttfb",
+							Value: `{{assert.lessThan(duration("200ms"))}}`,
+						},
+					},
+				},
+				response: response{
+					Status: "200 OK",
+					Timing: &Timing{
+						TTFB:  300 * time.Millisecond,
+						Total: 350 * time.Millisecond,
+					},
+				},
+				expectAssertError: true,
+			},
+			"expiring certificate": {
+				expect: &Expect{
+					TLS: yaml.MapSlice{
+						yaml.MapItem{
+							Key: "certificates",
+							Value: []interface{}{
+								yaml.MapSlice{
+									yaml.MapItem{
+										Key:   "expiresIn",
+										Value: `{{$ > duration("336h")}}`,
+									},
+								},
+							},
+						},
+					},
+				},
+				response: response{
+					Status: "200 OK",
+					TLS: &tlsutil.ConnectionState{
+						Certificates: []*tlsutil.Certificate{
+							{ExpiresIn: 24 * time.Hour},
+						},
+					},
+				},
+				expectAssertError: true,
+			},
+			"not a TLS connection": {
+				expect: &Expect{
+					TLS: yaml.MapSlice{
+						yaml.MapItem{
+							Key:   "version",
+							Value: "TLS 1.3",
+						},
+					},
+				},
+				response: response{
+					Status: "200 OK",
+				},
+				expectAssertError: true,
+			},
+			"both body and body file": {
+				expect: &Expect{
+					Body:     "hello",
+					BodyFile: "testdata/expect_body.txt",
+				},
+				expectBuildError: true,
+			},
+			"body file not found": {
+				expect: &Expect{
+					BodyFile: "testdata/not-found.json",
+				},
+				expectBuildError: true,
+			},
+			"wrong body (body file)": {
+				expect: &Expect{
+					BodyFile: "testdata/expect_body.txt",
+				},
+				response: response{
+					Status: "200 OK",
+					Body:   "bye",
+				},
+				expectAssertError: true,
+			},
 		}
 		for name, test := range tests {
 			t.Run(name, func(t *testing.T) {
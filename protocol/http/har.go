@@ -0,0 +1,171 @@
+package http
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/internal/har"
+)
+
+// harExchange represents an HTTP exchange to record as a HAR entry.
+type harExchange struct {
+	req    *http.Request
+	header http.Header // the request header whose credentials are redacted
+	timing *timingRecorder
+	resp   *http.Response
+	body   []byte
+	err    error
+}
+
+// recordHAR records the exchange if the context has the HAR recorder.
+// The secrets are masked by the recorder when the entries are exported.
+func recordHAR(ctx *context.Context, ex *harExchange) {
+	rec := ctx.HARRecorder()
+	if rec == nil {
+		return
+	}
+
+	start, total, timings := ex.timing.har()
+	entry := har.Entry{
+		StartedDateTime: start,
+		Time:            total,
+		Request:         harRequest(ex.req, ex.header),
+		Timings:         timings,
+		Comment:         ctx.Reporter().Name(),
+	}
+	if ex.resp != nil {
+		entry.Response = harResponse(ex.resp, ex.body)
+	} else {
+		entry.Response = har.Response{
+			Cookies:     []har.Cookie{},
+			Headers:     []har.NameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		}
+	}
+	if ex.err != nil {
+		entry.Response.Error = ex.err.Error()
+	}
+	rec.Add(entry)
+}
+
+// har returns the started time, the total time, and the time of each phase in the HAR format.
+func (r *timingRecorder) har() (time.Time, float64, har.Timings) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	ms := func(start, end time.Time) float64 {
+		if start.IsZero() || end.IsZero() {
+			return -1
+		}
+		return har.Milliseconds(end.Sub(start))
+	}
+	timings := har.Timings{
+		Blocked: -1,
+		DNS:     ms(r.dnsStart, r.dnsDone),
+		Connect: ms(r.connectStart, r.connectDone),
+		Send:    max(ms(r.gotConn, r.wroteRequest), 0),
+		Wait:    max(ms(r.wroteRequest, r.firstByte), 0),
+		Receive: max(ms(r.firstByte, r.end), 0),
+		SSL:     ms(r.tlsStart, r.tlsDone),
+	}
+	if timings.SSL > 0 && timings.Connect > 0 {
+		// the connect time includes the SSL time in the HAR format
+		timings.Connect += timings.SSL
+	}
+	return r.start, har.Milliseconds(r.end.Sub(r.start)), timings
+}
+
+func harRequest(req *http.Request, header http.Header) har.Request {
+	r := har.Request{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: req.Proto,
+		Cookies:     harCookies(req.Cookies()),
+		Headers:     harHeaders(header),
+		QueryString: []har.NameValue{},
+		HeadersSize: -1,
+	}
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range query[k] {
+			r.QueryString = append(r.QueryString, har.NameValue{Name: k, Value: v})
+		}
+	}
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			defer rc.Close()
+			if b, err := io.ReadAll(rc); err == nil && len(b) > 0 {
+				r.PostData = &har.PostData{
+					MimeType: header.Get("Content-Type"),
+				}
+				if utf8.Valid(b) {
+					r.PostData.Text = string(b)
+				} else {
+					r.PostData.Text = base64.StdEncoding.EncodeToString(b)
+					r.PostData.Encoding = "base64"
+				}
+				r.BodySize = len(b)
+			}
+		}
+	}
+	return r
+}
+
+func harResponse(resp *http.Response, body []byte) har.Response {
+	r := har.Response{
+		Status:      resp.StatusCode,
+		StatusText:  strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode)+" "),
+		HTTPVersion: resp.Proto,
+		Cookies:     harCookies(resp.Cookies()),
+		Headers:     harHeaders(resp.Header),
+		Content: har.Content{
+			Size:     len(body),
+			MimeType: resp.Header.Get("Content-Type"),
+		},
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	if utf8.Valid(body) {
+		r.Content.Text = string(body)
+	} else {
+		r.Content.Text = base64.StdEncoding.EncodeToString(body)
+		r.Content.Encoding = "base64"
+	}
+	return r
+}
+
+func harHeaders(h http.Header) []har.NameValue {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	headers := []har.NameValue{}
+	for _, k := range keys {
+		for _, v := range h[k] {
+			headers = append(headers, har.NameValue{Name: k, Value: v})
+		}
+	}
+	return headers
+}
+
+func harCookies(cookies []*http.Cookie) []har.Cookie {
+	c := make([]har.Cookie, len(cookies))
+	for i, cookie := range cookies {
+		c[i] = har.Cookie{Name: cookie.Name, Value: cookie.Value}
+	}
+	return c
+}
@@ -0,0 +1,144 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/internal/har"
+)
+
+func TestRequest_Invoke_HAR(t *testing.T) {
+	m := http.NewServeMux()
+	m.HandleFunc("/echo", func(w http.ResponseWriter, req *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session_id", Value: "SECRET"})
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("token: SECRET"))
+	})
+	m.HandleFunc("/binary", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte{0xff, 0xfe})
+	})
+	srv := httptest.NewServer(m)
+	t.Cleanup(srv.Close)
+
+	t.Run("success", func(t *testing.T) {
+		rec := har.NewRecorder()
+		ctx := context.FromT(t).WithHARRecorder(rec).WithSecrets(map[string]string{"token": "SECRET"})
+		req := &Request{
+			Method: http.MethodPost,
+			URL:    srv.URL + "/echo?q=SECRET",
+			Header: map[string]string{"Content-Type": "text/plain"},
+			Body:   "{{secrets.token}}",
+			Auth:   &AuthOption{Bearer: &BearerAuthOption{Token: "TOKEN"}},
+		}
+		if _, _, err := req.Invoke(ctx); err != nil {
+			t.Fatalf("failed to invoke: %s", err)
+		}
+		entries := rec.HAR().Log.Entries
+		if got := len(entries); got != 1 {
+			t.Fatalf("expect 1 entry but got %d", got)
+		}
+		entry := entries[0]
+		if got, expect := entry.Request.URL, srv.URL+"/echo?q={{secrets.token}}"; got != expect {
+			t.Errorf("expect %q but got %q", expect, got)
+		}
+		if diff := cmp.Diff([]har.NameValue{{Name: "q", Value: "{{secrets.token}}"}}, entry.Request.QueryString); diff != "" {
+			t.Errorf("differs (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]har.NameValue{
+			{Name: "Authorization", Value: "Bearer *****"},
+			{Name: "Content-Type", Value: "text/plain"},
+			{Name: "User-Agent", Value: defaultUserAgent},
+		}, entry.Request.Headers); diff != "" {
+			t.Errorf("differs (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(&har.PostData{MimeType: "text/plain", Text: "{{secrets.token}}"}, entry.Request.PostData); diff != "" {
+			t.Errorf("differs (-want +got):\n%s", diff)
+		}
+		if got, expect := entry.Response.StatusText, "Created"; got != expect {
+			t.Errorf("expect %q but got %q", expect, got)
+		}
+		if diff := cmp.Diff([]har.Cookie{{Name: "session_id", Value: "{{secrets.token}}"}}, entry.Response.Cookies); diff != "" {
+			t.Errorf("differs (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(har.Content{Size: 13, MimeType: "text/plain", Text: "token: {{secrets.token}}"}, entry.Response.Content); diff != "" {
+			t.Errorf("differs (-want +got):\n%s", diff)
+		}
+		if entry.Time < 0 || entry.Timings.Wait < 0 || entry.Timings.Receive < 0 {
+			t.Errorf("invalid timings: %+v", entry.Timings)
+		}
+		if got, expect := entry.Comment, t.Name(); got != expect {
+			t.Errorf("expect comment %q but got %q", expect, got)
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		rec := har.NewRecorder()
+		ctx := context.FromT(t).WithHARRecorder(rec)
+		if _, _, err := (&Request{URL: srv.URL + "/binary"}).Invoke(ctx); err != nil {
+			t.Fatalf("failed to invoke: %s", err)
+		}
+		if diff := cmp.Diff(har.Content{
+			Size:     2,
+			MimeType: "application/octet-stream",
+			Text:     "//4=",
+			Encoding: "base64",
+		}, rec.HAR().Log.Entries[0].Response.Content); diff != "" {
+			t.Errorf("differs (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("secrets bound after the request", func(t *testing.T) {
+		rec := har.NewRecorder()
+		ctx := context.FromT(t).WithHARRecorder(rec)
+		if _, _, err := (&Request{URL: srv.URL + "/echo"}).Invoke(ctx); err != nil {
+			t.Fatalf("failed to invoke: %s", err)
+		}
+		ctx.WithSecrets(map[string]string{"token": "SECRET"})
+		if got, expect := rec.HAR().Log.Entries[0].Response.Content.Text, "token: {{secrets.token}}"; got != expect {
+			t.Errorf("expect %q but got %q", expect, got)
+		}
+	})
+
+	t.Run("binary request body", func(t *testing.T) {
+		rec := har.NewRecorder()
+		ctx := context.FromT(t).WithHARRecorder(rec)
+		req := &Request{
+			Method: http.MethodPost,
+			URL:    srv.URL + "/echo",
+			Header: map[string]string{"Content-Type": "text/plain"},
+			Body:   []byte{0xff, 0xfe},
+		}
+		if _, _, err := req.Invoke(ctx); err != nil {
+			t.Fatalf("failed to invoke: %s", err)
+		}
+		if diff := cmp.Diff(&har.PostData{
+			MimeType: "text/plain",
+			Text:     "//4=",
+			Encoding: "base64",
+		}, rec.HAR().Log.Entries[0].Request.PostData); diff != "" {
+			t.Errorf("differs (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("failed to send request", func(t *testing.T) {
+		rec := har.NewRecorder()
+		ctx := context.FromT(t).WithHARRecorder(rec)
+		if _, _, err := (&Request{URL: "http://127.0.0.1:0"}).Invoke(ctx); err == nil {
+			t.Fatal("no error")
+		}
+		entries := rec.HAR().Log.Entries
+		if got := len(entries); got != 1 {
+			t.Fatalf("expect 1 entry but got %d", got)
+		}
+		if got, expect := entries[0].Response.Error, "connection refused"; !strings.Contains(got, expect) {
+			t.Errorf("%q doesn't contain %q", got, expect)
+		}
+	})
+}
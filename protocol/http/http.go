@@ -2,18 +2,34 @@ package http
 
 import (
 	"bytes"
+	"sync"
 
 	"github.com/goccy/go-yaml"
+	"github.com/scenarigo/scenarigo/errors"
 	"github.com/scenarigo/scenarigo/protocol"
 )
 
+var httpProtocol = &HTTP{}
+
 // Register registers http protocol.
 func Register() {
-	protocol.Register(&HTTP{})
+	protocol.Register(httpProtocol)
 }
 
 // HTTP is a protocol type for the scenarigo step.
-type HTTP struct{}
+type HTTP struct {
+	m      sync.Mutex
+	client *client
+	auth   *AuthOption
+	tokens tokenCache
+}
+
+// Option represents an option for HTTP.
+type Option struct {
+	Client *ClientOption `yaml:"client,omitempty"`
+	// Auth is the default authentication for the requests which don't have auth.
+	Auth *AuthOption `yaml:"auth,omitempty"`
+}
 
 // Name implements protocol.Protocol interface.
 func (p *HTTP) Name() string {
@@ -21,10 +37,51 @@ func (p *HTTP) Name() string {
 }
 
 // UnmarshalOption implements protocol.Protocol interface.
-func (p *HTTP) UnmarshalOption(_ []byte) error {
+func (p *HTTP) UnmarshalOption(b []byte) error {
+	return p.UnmarshalOptionWithRootDir(b, "")
+}
+
+// UnmarshalOptionWithRootDir implements protocol.RootDirOptionUnmarshaler interface.
+// The relative file paths in the option (e.g., TLS certificates) are resolved from the root directory.
+func (p *HTTP) UnmarshalOptionWithRootDir(b []byte, root string) error {
+	var opt Option
+	if err := yaml.UnmarshalWithOptions(b, &opt, yaml.Strict()); err != nil {
+		return err
+	}
+	c, err := opt.Client.build(root)
+	if err != nil {
+		return errors.WithPath(err, "client")
+	}
+	if opt.Auth != nil {
+		if err := opt.Auth.validate(); err != nil {
+			return errors.WithPath(err, "auth")
+		}
+	}
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.client.closeIdleConnections()
+	p.client = c
+	p.auth = opt.Auth
 	return nil
 }
 
+// getClient returns the client configured by the option.
+// It returns the default one if the option is not unmarshaled.
+func (p *HTTP) getClient() *client {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.client == nil {
+		p.client = &client{}
+	}
+	return p.client
+}
+
+func (p *HTTP) getAuth() *AuthOption {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.auth
+}
+
 // UnmarshalRequest implements protocol.Protocol interface.
 func (p *HTTP) UnmarshalRequest(b []byte) (protocol.Invoker, error) {
 	var r Request
@@ -46,3 +103,13 @@ func (p *HTTP) UnmarshalExpect(b []byte) (protocol.AssertionBuilder, error) {
 	}
 	return &e, nil
 }
+
+// Close implements protocol.Closer interface.
+// It closes the idle connections of the client configured by the option and discards the cached OAuth 2.0 tokens.
+func (p *HTTP) Close() error {
+	p.tokens.clear()
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.client.closeIdleConnections()
+	return nil
+}
@@ -1,9 +1,14 @@
 package http
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/scenarigo/scenarigo/internal/testutil"
 	"github.com/scenarigo/scenarigo/protocol"
 )
 
@@ -18,6 +23,164 @@ func TestHTTP(t *testing.T) {
 	}
 }
 
+func TestHTTP_UnmarshalOption(t *testing.T) {
+	_, cert, key := testutil.GenerateCert(t)
+	t.Run("ok", func(t *testing.T) {
+		tests := map[string]struct {
+			yaml          string
+			expectTimeout time.Duration
+			expectTLS     bool
+		}{
+			"empty": {},
+			"timeout": {
+				yaml:          "client:\n  timeout: 10s",
+				expectTimeout: 10 * time.Second,
+			},
+			"all": {
+				yaml: fmt.Sprintf(`
+client:
+  timeout: 1m
+  proxy: http://localhost:8080
+  tls:
+    minVersion: TLS 1.2
+    maxVersion: TLS 1.3
+    certificate: %s
+    clientCertificate: %s
+    clientKey: %s
+    serverName: localhost
+    skip: true
+  redirect:
+    max: 3
+  transport:
+    maxIdleConns: 10
+    maxIdleConnsPerHost: 2
+    maxConnsPerHost: 5
+    idleConnTimeout: 30s
+    disableKeepAlives: true
+    disableHTTP2: true
+`, cert, cert, key),
+				expectTimeout: time.Minute,
+				expectTLS:     true,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				p := &HTTP{}
+				if err := p.UnmarshalOption([]byte(test.yaml)); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				c := p.getClient()
+				if got, expect := c.timeout, test.expectTimeout; got != expect {
+					t.Errorf("expect timeout %s but got %s", expect, got)
+				}
+				if got, expect := c.transport != nil && c.transport.TLSClientConfig != nil, test.expectTLS; got != expect {
+					t.Errorf("expect TLS config %t but got %t", expect, got)
+				}
+				if err := p.Close(); err != nil {
+					t.Fatalf("failed to close: %s", err)
+				}
+			})
+		}
+	})
+
+	t.Run("ng", func(t *testing.T) {
+		tests := map[string]struct {
+			yaml   string
+			expect string
+		}{
+			"unknown field": {
+				yaml:   "a: b",
+				expect: `unknown field "a"`,
+			},
+			"invalid timeout": {
+				yaml:   "client:\n  timeout: 1",
+				expect: `time: missing unit in duration "1"`,
+			},
+			"invalid proxy": {
+				yaml:   "client:\n  proxy: ':'",
+				expect: ".client.proxy: invalid proxy URL",
+			},
+			"invalid TLS version": {
+				yaml:   "client:\n  tls:\n    minVersion: TLS 0",
+				expect: ".client.tls.minVersion: invalid minimum TLS version TLS 0",
+			},
+			"certificate not found": {
+				yaml:   "client:\n  tls:\n    certificate: not-found.crt",
+				expect: ".client.tls.certificate: failed to read certificate",
+			},
+			"no client key": {
+				yaml:   fmt.Sprintf("client:\n  tls:\n    clientCertificate: %s", cert),
+				expect: ".client.tls.clientCertificate: client certificate must be specified with client key",
+			},
+			"no client certificate": {
+				yaml:   fmt.Sprintf("client:\n  tls:\n    clientKey: %s", key),
+				expect: ".client.tls.clientKey: client key must be specified with client certificate",
+			},
+			"invalid max redirects": {
+				yaml:   "client:\n  redirect:\n    max: -1",
+				expect: ".client.redirect.max: max must be greater than or equal to 0 but got -1",
+			},
+			"invalid idle connection timeout": {
+				yaml:   "client:\n  transport:\n    idleConnTimeout: 1",
+				expect: `time: missing unit in duration "1"`,
+			},
+			"h2c with HTTP/2 disabled": {
+				yaml:   "client:\n  transport:\n    disableHTTP2: true\n    h2c: true",
+				expect: ".client.transport.h2c: h2c can't be enabled with disableHTTP2",
+			},
+			"multiple auth methods": {
+				yaml:   "auth:\n  basic:\n    username: user\n  bearer:\n    token: TOKEN",
+				expect: ".auth: one of basic, bearer, oauth2, hmac, or sigv4 must be specified",
+			},
+			"invalid auth": {
+				yaml:   "auth:\n  oauth2:\n    clientID: CLIENT_ID",
+				expect: ".auth.oauth2.tokenURL: token URL is required",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				p := &HTTP{}
+				err := p.UnmarshalOption([]byte(test.yaml))
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if got := err.Error(); !strings.Contains(got, test.expect) {
+					t.Errorf("%q doesn't contain %q", got, test.expect)
+				}
+			})
+		}
+	})
+}
+
+func TestHTTP_UnmarshalOptionWithRootDir(t *testing.T) {
+	_, cert, key := testutil.GenerateCert(t)
+	root := filepath.Dir(cert)
+	t.Run("ok", func(t *testing.T) {
+		p := &HTTP{}
+		yaml := fmt.Sprintf("client:\n  tls:\n    certificate: %s\n    clientCertificate: %s\n    clientKey: %s", filepath.Base(cert), filepath.Base(cert), filepath.Base(key))
+		if err := p.UnmarshalOptionWithRootDir([]byte(yaml), root); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		c := p.getClient()
+		if c.transport == nil || c.transport.TLSClientConfig == nil {
+			t.Fatal("TLS config is not set")
+		}
+		if got, expect := len(c.transport.TLSClientConfig.Certificates), 1; got != expect {
+			t.Errorf("expect %d client certificates but got %d", expect, got)
+		}
+	})
+	t.Run("ng", func(t *testing.T) {
+		p := &HTTP{}
+		err := p.UnmarshalOptionWithRootDir([]byte("client:\n  tls:\n    certificate: not-found.crt"), root)
+		if err == nil {
+			t.Fatal("no error")
+		}
+		if got, expect := err.Error(), filepath.Join(root, "not-found.crt"); !strings.Contains(got, expect) {
+			t.Errorf("%q doesn't contain %q", got, expect)
+		}
+	})
+}
+
 func TestHTTP_UnmarshalRequest(t *testing.T) {
 	t.Run("ok", func(t *testing.T) {
 		tests := map[string]struct {
@@ -61,6 +224,11 @@ func TestHTTP_UnmarshalRequest(t *testing.T) {
 method: GET
 method: GET`),
 			},
+			"invalid SSE timeout": {
+				bytes: []byte(`
+sse:
+  timeout: 1`),
+			},
 		}
 		for name, test := range tests {
 			t.Run(name, func(t *testing.T) {
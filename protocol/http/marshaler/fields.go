@@ -0,0 +1,55 @@
+package marshaler
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/internal/reflectutil"
+)
+
+var mapSliceType = reflect.TypeOf(yaml.MapSlice{})
+
+type field struct {
+	name  string
+	value reflect.Value
+}
+
+// mapFields returns the fields of the map v.
+// The order of fields is preserved if v is yaml.MapSlice, otherwise the fields are sorted by name.
+func mapFields(v interface{}) ([]field, error) {
+	if ms, ok := v.(yaml.MapSlice); ok {
+		fields := make([]field, 0, len(ms))
+		for _, item := range ms {
+			name, err := reflectutil.ConvertString(reflect.ValueOf(item.Key))
+			if err != nil {
+				return nil, errors.Errorf("expected key is string but got %T", item.Key)
+			}
+			fields = append(fields, field{name: name, value: reflect.ValueOf(item.Value)})
+		}
+		return fields, nil
+	}
+
+	rv := reflectutil.Elem(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil, errors.New("invalid value")
+	}
+	if rv.Kind() != reflect.Map {
+		return nil, errors.Errorf("expected map but got %T", rv.Interface())
+	}
+	fields := make([]field, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		name, err := reflectutil.ConvertString(iter.Key())
+		if err != nil {
+			return nil, errors.Errorf("expected key is string but got %T", iter.Key().Interface())
+		}
+		fields = append(fields, field{name: name, value: iter.Value()})
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].name < fields[j].name
+	})
+	return fields, nil
+}
@@ -6,6 +6,8 @@ import (
 	"sync"
 
 	"github.com/pkg/errors"
+
+	grpcproto "github.com/scenarigo/scenarigo/protocol/grpc/proto"
 )
 
 // Default is the default request marshaler.
@@ -50,3 +52,34 @@ type RequestMarshaler interface {
 	MediaType() string
 	Marshal(v interface{}) ([]byte, error)
 }
+
+// MarshalOptions represents options to marshal the HTTP request body.
+type MarshalOptions struct {
+	// ContentType is the value of the Content-Type header.
+	ContentType string
+	// BaseDir is the directory to resolve relative file paths.
+	BaseDir string
+	// ProtoMessage is the full name of the message type to marshal the body as protocol buffers.
+	// If it is empty, the proto parameter of ContentType is used.
+	ProtoMessage string
+	// ProtoTypes resolves the message types of protocol buffers.
+	// If it is nil, the types linked into the binary (e.g., the well-known types) are used.
+	ProtoTypes *grpcproto.TypeResolver
+}
+
+// Body represents a marshaled HTTP request body.
+type Body struct {
+	Data []byte
+	// ContentType is the value of the Content-Type header to send (e.g., with the boundary parameter).
+	// If it is empty, the header is sent as it is.
+	ContentType string
+	// Dump is the value to show as the request body instead of the original value if it is not nil.
+	Dump interface{}
+}
+
+// RequestMarshalerWithOptions is the interface that marshals the HTTP request body with options.
+// If the marshaler implements this interface, MarshalWithOptions is used instead of Marshal.
+type RequestMarshalerWithOptions interface {
+	RequestMarshaler
+	MarshalWithOptions(v interface{}, opts *MarshalOptions) (*Body, error)
+}
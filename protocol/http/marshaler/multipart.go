@@ -0,0 +1,214 @@
+package marshaler
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/internal/filepathutil"
+	"github.com/scenarigo/scenarigo/internal/reflectutil"
+)
+
+func init() {
+	if err := Register(&multipartMarshaler{}); err != nil {
+		panic(err)
+	}
+}
+
+type multipartMarshaler struct{}
+
+// MultipartPart represents a part of multipart/form-data body.
+// A field value which is not a map is sent as a simple form field.
+type MultipartPart struct {
+	// Value is the content of the part.
+	Value string `yaml:"value,omitempty"`
+	// File is the path of the file to upload. The relative path is resolved from the scenario file.
+	File string `yaml:"file,omitempty"`
+	// Filename is the filename of the part (default: the base name of File).
+	Filename string `yaml:"filename,omitempty"`
+	// ContentType is the Content-Type of the part (default: detected from the file extension).
+	ContentType string `yaml:"contentType,omitempty"`
+}
+
+// MultipartPartDump represents a part of multipart/form-data body in the request dump.
+type MultipartPartDump struct {
+	Name        string `yaml:"name"`
+	Filename    string `yaml:"filename,omitempty"`
+	ContentType string `yaml:"contentType,omitempty"`
+	Value       string `yaml:"value,omitempty"` // only for form fields
+	Size        int    `yaml:"size,omitempty"`  // only for files
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// MediaType implements RequestMarshaler interface.
+func (m *multipartMarshaler) MediaType() string {
+	return "multipart/form-data"
+}
+
+// Marshal implements RequestMarshaler interface.
+func (m *multipartMarshaler) Marshal(v interface{}) ([]byte, error) {
+	b, err := m.MarshalWithOptions(v, &MarshalOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return b.Data, nil
+}
+
+// MarshalWithOptions implements RequestMarshalerWithOptions interface.
+func (m *multipartMarshaler) MarshalWithOptions(v interface{}, opts *MarshalOptions) (*Body, error) {
+	fields, err := mapFields(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if _, params, err := mime.ParseMediaType(opts.ContentType); err == nil && params["boundary"] != "" {
+		if err := w.SetBoundary(params["boundary"]); err != nil {
+			return nil, errors.Wrap(err, "invalid boundary")
+		}
+	}
+	dump := []MultipartPartDump{}
+	for _, f := range fields {
+		values, isList := multipartValues(f.value)
+		for i, pv := range values {
+			path := f.name
+			if isList {
+				path = fmt.Sprintf("%s[%d]", f.name, i)
+			}
+			p, err := multipartPart(pv)
+			if err != nil {
+				return nil, errors.WithPath(err, path)
+			}
+			d, err := writePart(w, f.name, p, opts.BaseDir)
+			if err != nil {
+				return nil, errors.WithPath(err, path)
+			}
+			dump = append(dump, *d)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to write multipart body")
+	}
+	return &Body{
+		Data:        buf.Bytes(),
+		ContentType: w.FormDataContentType(),
+		Dump:        dump,
+	}, nil
+}
+
+// multipartValues returns the values of the field. A list is converted to multiple parts with the same name.
+func multipartValues(v reflect.Value) ([]reflect.Value, bool) {
+	e := reflectutil.Elem(v)
+	if !e.IsValid() {
+		return []reflect.Value{v}, false
+	}
+	if (e.Kind() == reflect.Slice || e.Kind() == reflect.Array) && e.Type().Elem().Kind() != reflect.Uint8 {
+		values := make([]reflect.Value, 0, e.Len())
+		for i := range e.Len() {
+			values = append(values, e.Index(i))
+		}
+		return values, true
+	}
+	return []reflect.Value{e}, false
+}
+
+func multipartPart(v reflect.Value) (*MultipartPart, error) {
+	v = reflectutil.Elem(v)
+	if !v.IsValid() {
+		return nil, errors.New("value is nil")
+	}
+	if v.Kind() != reflect.Map {
+		s, err := reflectutil.ConvertString(v)
+		if err != nil {
+			return nil, err
+		}
+		return &MultipartPart{Value: s}, nil
+	}
+	m, err := reflectutil.ConvertStringsMap(v)
+	if err != nil {
+		return nil, err
+	}
+	p := &MultipartPart{}
+	for k, vs := range m {
+		if len(vs) != 1 {
+			return nil, errors.ErrorPathf(k, "expected string but got %d values", len(vs))
+		}
+		switch k {
+		case "value":
+			p.Value = vs[0]
+		case "file":
+			p.File = vs[0]
+		case "filename":
+			p.Filename = vs[0]
+		case "contentType":
+			p.ContentType = vs[0]
+		default:
+			return nil, errors.ErrorPathf(k, "unknown field %q", k)
+		}
+	}
+	if p.File != "" && p.Value != "" {
+		return nil, errors.New("value and file can not be specified at the same time")
+	}
+	return p, nil
+}
+
+func writePart(w *multipart.Writer, name string, p *MultipartPart, baseDir string) (*MultipartPartDump, error) {
+	content := []byte(p.Value)
+	filename := p.Filename
+	contentType := p.ContentType
+	if p.File != "" {
+		b, err := os.ReadFile(filepathutil.From(baseDir, p.File))
+		if err != nil {
+			return nil, errors.WrapPath(err, "file", "failed to read file")
+		}
+		content = b
+		if filename == "" {
+			filename = filepath.Base(p.File)
+		}
+	}
+	isFile := filename != ""
+	if isFile && contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(filename))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+	}
+
+	h := textproto.MIMEHeader{}
+	disposition := fmt.Sprintf(`form-data; name="%s"`, quoteEscaper.Replace(name))
+	if isFile {
+		disposition += fmt.Sprintf(`; filename="%s"`, quoteEscaper.Replace(filename))
+	}
+	h.Set("Content-Disposition", disposition)
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	pw, err := w.CreatePart(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create part")
+	}
+	if _, err := pw.Write(content); err != nil {
+		return nil, errors.Wrap(err, "failed to write part")
+	}
+
+	d := &MultipartPartDump{
+		Name:        name,
+		Filename:    filename,
+		ContentType: contentType,
+	}
+	if isFile {
+		d.Size = len(content)
+	} else {
+		d.Value = p.Value
+	}
+	return d, nil
+}
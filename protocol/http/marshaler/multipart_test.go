@@ -0,0 +1,215 @@
+package marshaler
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+)
+
+type testPart struct {
+	Name        string
+	Filename    string
+	ContentType string
+	Content     string
+}
+
+func TestMultipart_MarshalWithOptions(t *testing.T) {
+	m := multipartMarshaler{}
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			v           interface{}
+			contentType string
+			expect      []testPart
+			expectDump  []MultipartPartDump
+		}{
+			"fields": {
+				v: map[string]interface{}{
+					"name": "scenarigo",
+					"age":  10,
+				},
+				expect: []testPart{
+					{Name: "age", Content: "10"},
+					{Name: "name", Content: "scenarigo"},
+				},
+				expectDump: []MultipartPartDump{
+					{Name: "age", Value: "10"},
+					{Name: "name", Value: "scenarigo"},
+				},
+			},
+			"ordered fields": {
+				v: yaml.MapSlice{
+					{Key: "name", Value: "scenarigo"},
+					{Key: "age", Value: 10},
+				},
+				expect: []testPart{
+					{Name: "name", Content: "scenarigo"},
+					{Name: "age", Content: "10"},
+				},
+				expectDump: []MultipartPartDump{
+					{Name: "name", Value: "scenarigo"},
+					{Name: "age", Value: "10"},
+				},
+			},
+			"files": {
+				v: map[string]interface{}{
+					"file": map[string]interface{}{
+						"file": "testdata/hello.json",
+					},
+					"files": []interface{}{
+						map[string]interface{}{
+							"file":        "testdata/hello.json",
+							"filename":    "greeting",
+							"contentType": "application/octet-stream",
+						},
+						map[string]interface{}{
+							"value":    `{"message":"hi"}`,
+							"filename": "inline.json",
+						},
+					},
+					"meta": map[string]interface{}{
+						"value":       `{"id":1}`,
+						"contentType": "application/json",
+					},
+				},
+				expect: []testPart{
+					{Name: "file", Filename: "hello.json", ContentType: "application/json", Content: `{"message":"hello"}`},
+					{Name: "files", Filename: "greeting", ContentType: "application/octet-stream", Content: `{"message":"hello"}`},
+					{Name: "files", Filename: "inline.json", ContentType: "application/json", Content: `{"message":"hi"}`},
+					{Name: "meta", ContentType: "application/json", Content: `{"id":1}`},
+				},
+				expectDump: []MultipartPartDump{
+					{Name: "file", Filename: "hello.json", ContentType: "application/json", Size: 19},
+					{Name: "files", Filename: "greeting", ContentType: "application/octet-stream", Size: 19},
+					{Name: "files", Filename: "inline.json", ContentType: "application/json", Size: 16},
+					{Name: "meta", ContentType: "application/json", Value: `{"id":1}`},
+				},
+			},
+			"specify boundary": {
+				v: map[string]interface{}{
+					"name": "scenarigo",
+				},
+				contentType: "multipart/form-data; boundary=test-boundary",
+				expect: []testPart{
+					{Name: "name", Content: "scenarigo"},
+				},
+				expectDump: []MultipartPartDump{
+					{Name: "name", Value: "scenarigo"},
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				b, err := m.MarshalWithOptions(test.v, &MarshalOptions{
+					ContentType: test.contentType,
+					BaseDir:     ".",
+				})
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				mt, params, err := mime.ParseMediaType(b.ContentType)
+				if err != nil {
+					t.Fatalf("invalid content type: %s", err)
+				}
+				if mt != "multipart/form-data" {
+					t.Fatalf("unexpected media type: %s", mt)
+				}
+				if test.contentType != "" {
+					if got, expect := b.ContentType, test.contentType; got != expect {
+						t.Errorf("expect %q but got %q", expect, got)
+					}
+				}
+				if diff := cmp.Diff(test.expect, readParts(t, b.Data, params["boundary"])); diff != "" {
+					t.Errorf("parts differ (-want +got):\n%s", diff)
+				}
+				if diff := cmp.Diff(test.expectDump, b.Dump); diff != "" {
+					t.Errorf("dump differs (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			v      interface{}
+			expect string
+		}{
+			"nil": {
+				v:      nil,
+				expect: "invalid value",
+			},
+			"not map": {
+				v:      "test",
+				expect: "expected map but got string",
+			},
+			"file not found": {
+				v: map[string]interface{}{
+					"files": []interface{}{
+						map[string]interface{}{
+							"file": "testdata/not-found.txt",
+						},
+					},
+				},
+				expect: ".files[0].file: failed to read file",
+			},
+			"unknown field": {
+				v: map[string]interface{}{
+					"file": map[string]interface{}{
+						"path": "testdata/hello.json",
+					},
+				},
+				expect: `.file.path: unknown field "path"`,
+			},
+			"both value and file": {
+				v: map[string]interface{}{
+					"file": map[string]interface{}{
+						"value": "hello",
+						"file":  "testdata/hello.json",
+					},
+				},
+				expect: ".file: value and file can not be specified at the same time",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := m.MarshalWithOptions(test.v, &MarshalOptions{BaseDir: "."})
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if got := err.Error(); !strings.Contains(got, test.expect) {
+					t.Errorf("%q doesn't contain %q", got, test.expect)
+				}
+			})
+		}
+	})
+}
+
+func readParts(t *testing.T, b []byte, boundary string) []testPart {
+	t.Helper()
+	var parts []testPart
+	r := multipart.NewReader(bytes.NewReader(b), boundary)
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read part: %s", err)
+		}
+		content, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatalf("failed to read part: %s", err)
+		}
+		parts = append(parts, testPart{
+			Name:        p.FormName(),
+			Filename:    p.FileName(),
+			ContentType: p.Header.Get("Content-Type"),
+			Content:     string(content),
+		})
+	}
+	return parts
+}
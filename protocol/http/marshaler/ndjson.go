@@ -0,0 +1,51 @@
+package marshaler
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/internal/reflectutil"
+)
+
+func init() {
+	for _, mediaType := range []string{"application/x-ndjson", "application/jsonl", "application/x-jsonlines"} {
+		if err := Register(&ndjsonMarshaler{mediaType: mediaType}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// ndjsonMarshaler marshals a list into newline-delimited JSON (JSON Lines).
+type ndjsonMarshaler struct {
+	mediaType string
+}
+
+// MediaType implements RequestMarshaler interface.
+func (m *ndjsonMarshaler) MediaType() string {
+	return m.mediaType
+}
+
+// Marshal implements RequestMarshaler interface.
+func (m *ndjsonMarshaler) Marshal(v interface{}) ([]byte, error) {
+	rv := reflectutil.Elem(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil, errors.New("invalid value")
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, errors.Errorf("expected list but got %T", rv.Interface())
+	}
+	var buf bytes.Buffer
+	for i := range rv.Len() {
+		var line bytes.Buffer
+		if err := yaml.NewEncoder(&line, yaml.JSON()).Encode(rv.Index(i).Interface()); err != nil {
+			return nil, errors.WrapPathf(err, fmt.Sprintf("[%d]", i), "failed to marshal")
+		}
+		buf.Write(bytes.TrimRight(line.Bytes(), "\n"))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
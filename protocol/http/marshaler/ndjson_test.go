@@ -0,0 +1,76 @@
+package marshaler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+)
+
+func TestNDJSON_Marshal(t *testing.T) {
+	m := ndjsonMarshaler{mediaType: "application/x-ndjson"}
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			v      interface{}
+			expect string
+		}{
+			"empty": {
+				v:      []interface{}{},
+				expect: "",
+			},
+			"values": {
+				v: []interface{}{
+					map[string]interface{}{"id": 1, "tags": []string{"a", "b"}},
+					yaml.MapSlice{
+						{Key: "name", Value: "foo\nbar"},
+						{Key: "nested", Value: map[string]interface{}{"ok": true}},
+					},
+					"text",
+					nil,
+				},
+				expect: `{"id": 1, "tags": ["a", "b"]}
+{"name": "foo\nbar", "nested": {"ok": true}}
+"text"
+null
+`,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				b, err := m.Marshal(test.v)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if got := string(b); got != test.expect {
+					t.Errorf("expect %q but got %q", test.expect, got)
+				}
+			})
+		}
+	})
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			v      interface{}
+			expect string
+		}{
+			"nil": {
+				v:      nil,
+				expect: "invalid value",
+			},
+			"not list": {
+				v:      map[string]interface{}{"id": 1},
+				expect: "expected list but got map[string]interface {}",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := m.Marshal(test.v)
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if got := err.Error(); !strings.Contains(got, test.expect) {
+					t.Errorf("%q doesn't contain %q", got, test.expect)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package marshaler
+
+import (
+	"bytes"
+	"mime"
+	"reflect"
+
+	"github.com/goccy/go-yaml"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/internal/reflectutil"
+	grpcproto "github.com/scenarigo/scenarigo/protocol/grpc/proto"
+)
+
+func init() {
+	for _, mediaType := range []string{"application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf"} {
+		if err := Register(&protobufMarshaler{mediaType: mediaType}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// protobufMarshaler marshals a value which has the same structure as the JSON representation of the message
+// into the binary wire format. A string or []byte value is sent as it is.
+type protobufMarshaler struct {
+	mediaType string
+}
+
+// MediaType implements RequestMarshaler interface.
+func (m *protobufMarshaler) MediaType() string {
+	return m.mediaType
+}
+
+// Marshal implements RequestMarshaler interface.
+func (m *protobufMarshaler) Marshal(v interface{}) ([]byte, error) {
+	b, err := m.MarshalWithOptions(v, &MarshalOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return b.Data, nil
+}
+
+// MarshalWithOptions implements RequestMarshalerWithOptions interface.
+func (m *protobufMarshaler) MarshalWithOptions(v interface{}, opts *MarshalOptions) (*Body, error) {
+	rv := reflectutil.Elem(reflect.ValueOf(v))
+	if rv.IsValid() && (rv.Kind() == reflect.String || (rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8)) {
+		s, err := reflectutil.ConvertString(rv)
+		if err != nil {
+			return nil, err
+		}
+		return &Body{Data: []byte(s)}, nil
+	}
+
+	types := opts.ProtoTypes
+	if types == nil {
+		types = grpcproto.NewTypeResolver()
+	}
+	name := protoMessageName(opts.ProtoMessage, opts.ContentType)
+	if name == "" {
+		return nil, errors.New("message type is not specified")
+	}
+	mt, err := types.FindMessageByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, errors.Errorf("failed to find message type %q: %s", name, err)
+	}
+	msg := mt.New().Interface()
+	if rv.IsValid() {
+		var buf bytes.Buffer
+		if err := yaml.NewEncoder(&buf, yaml.JSON()).Encode(v); err != nil {
+			return nil, err
+		}
+		if err := (protojson.UnmarshalOptions{Resolver: types}).Unmarshal(buf.Bytes(), msg); err != nil {
+			return nil, errors.Errorf("failed to convert into %s: %s", name, err)
+		}
+	}
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Body{Data: b}, nil
+}
+
+// protoMessageName returns the full name of the message type.
+// If name is empty, it returns the proto parameter of the media type (e.g., application/x-protobuf; proto=example.Message).
+func protoMessageName(name, mediaType string) string {
+	if name != "" {
+		return name
+	}
+	if _, params, err := mime.ParseMediaType(mediaType); err == nil {
+		return params["proto"]
+	}
+	return ""
+}
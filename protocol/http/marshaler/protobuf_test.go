@@ -0,0 +1,122 @@
+package marshaler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	testpb "github.com/scenarigo/scenarigo/testdata/gen/pb/test"
+)
+
+func TestProtobuf_MarshalWithOptions(t *testing.T) {
+	m := protobufMarshaler{mediaType: "application/x-protobuf"}
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			v      interface{}
+			opts   *MarshalOptions
+			expect proto.Message
+			raw    string
+		}{
+			"message option": {
+				v: map[string]interface{}{
+					"messageId":   "1",
+					"messageBody": "hello",
+				},
+				opts: &MarshalOptions{
+					ContentType:  "application/x-protobuf",
+					ProtoMessage: "scenarigo.testdata.test.EchoRequest",
+				},
+				expect: &testpb.EchoRequest{
+					MessageId:   "1",
+					MessageBody: "hello",
+				},
+			},
+			"proto parameter": {
+				v: map[string]interface{}{
+					"messageId": "1",
+				},
+				opts: &MarshalOptions{
+					ContentType: "application/x-protobuf; proto=scenarigo.testdata.test.EchoRequest",
+				},
+				expect: &testpb.EchoRequest{
+					MessageId: "1",
+				},
+			},
+			"string": {
+				v:    "\x0a\x011",
+				opts: &MarshalOptions{},
+				raw:  "\x0a\x011",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				b, err := m.MarshalWithOptions(test.v, test.opts)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if test.expect == nil {
+					if got, expect := string(b.Data), test.raw; got != expect {
+						t.Errorf("expect %q but got %q", expect, got)
+					}
+					return
+				}
+				var got testpb.EchoRequest
+				if err := proto.Unmarshal(b.Data, &got); err != nil {
+					t.Fatalf("failed to unmarshal: %s", err)
+				}
+				if diff := cmp.Diff(test.expect, &got, protocmp.Transform()); diff != "" {
+					t.Errorf("differs (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			v      interface{}
+			opts   *MarshalOptions
+			expect string
+		}{
+			"no message type": {
+				v: map[string]interface{}{
+					"messageId": "1",
+				},
+				opts: &MarshalOptions{
+					ContentType: "application/x-protobuf",
+				},
+				expect: "message type is not specified",
+			},
+			"unknown message type": {
+				v: map[string]interface{}{
+					"messageId": "1",
+				},
+				opts: &MarshalOptions{
+					ProtoMessage: "scenarigo.testdata.test.Unknown",
+				},
+				expect: `failed to find message type "scenarigo.testdata.test.Unknown"`,
+			},
+			"unknown field": {
+				v: map[string]interface{}{
+					"unknown": "1",
+				},
+				opts: &MarshalOptions{
+					ProtoMessage: "scenarigo.testdata.test.EchoRequest",
+				},
+				expect: "failed to convert into scenarigo.testdata.test.EchoRequest",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := m.MarshalWithOptions(test.v, test.opts)
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if !strings.Contains(err.Error(), test.expect) {
+					t.Errorf("expect error %q but got %q", test.expect, err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package marshaler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/internal/reflectutil"
+)
+
+func init() {
+	for _, mediaType := range []string{"application/xml", "text/xml", "application/soap+xml"} {
+		if err := Register(&xmlMarshaler{mediaType: mediaType}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+const (
+	xmlAttributePrefix = "@"
+	xmlTextKey         = "#text"
+)
+
+// xmlMarshaler marshals a tree which has the same structure as the XML response unmarshaler returns.
+// A string or []byte value is sent as it is.
+type xmlMarshaler struct {
+	mediaType string
+}
+
+// MediaType implements RequestMarshaler interface.
+func (m *xmlMarshaler) MediaType() string {
+	return m.mediaType
+}
+
+// Marshal implements RequestMarshaler interface.
+func (m *xmlMarshaler) Marshal(v interface{}) ([]byte, error) {
+	rv := reflectutil.Elem(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil, errors.New("invalid value")
+	}
+	if rv.Kind() == reflect.String || (rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8) {
+		s, err := reflectutil.ConvertString(rv)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	}
+
+	fields, err := mapFields(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 1 {
+		return nil, errors.Errorf("expected a single root element but got %d elements", len(fields))
+	}
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	if err := encodeXMLElement(enc, fields[0].name, fields[0].value); err != nil {
+		return nil, errors.WithPath(err, fields[0].name)
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeXMLElement(enc *xml.Encoder, name string, v reflect.Value) error {
+	e := reflectutil.Elem(v)
+	if !e.IsValid() {
+		return enc.EncodeElement("", xml.StartElement{Name: xml.Name{Local: name}})
+	}
+	if e.Kind() != reflect.Map && e.Type() != mapSliceType {
+		if (e.Kind() == reflect.Slice || e.Kind() == reflect.Array) && e.Type().Elem().Kind() != reflect.Uint8 {
+			for i := range e.Len() {
+				if err := encodeXMLElement(enc, name, e.Index(i)); err != nil {
+					return errors.WithPath(err, fmt.Sprintf("[%d]", i))
+				}
+			}
+			return nil
+		}
+		s, err := reflectutil.ConvertString(e)
+		if err != nil {
+			return err
+		}
+		return enc.EncodeElement(s, xml.StartElement{Name: xml.Name{Local: name}})
+	}
+
+	fields, err := mapFields(e.Interface())
+	if err != nil {
+		return err
+	}
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	var (
+		text     *string
+		children []field
+	)
+	for _, f := range fields {
+		switch {
+		case strings.HasPrefix(f.name, xmlAttributePrefix):
+			s, err := reflectutil.ConvertString(f.value)
+			if err != nil {
+				return errors.WithPath(err, f.name)
+			}
+			start.Attr = append(start.Attr, xml.Attr{
+				Name:  xml.Name{Local: strings.TrimPrefix(f.name, xmlAttributePrefix)},
+				Value: s,
+			})
+		case f.name == xmlTextKey:
+			s, err := reflectutil.ConvertString(f.value)
+			if err != nil {
+				return errors.WithPath(err, f.name)
+			}
+			text = &s
+		default:
+			children = append(children, f)
+		}
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if text != nil {
+		if err := enc.EncodeToken(xml.CharData(*text)); err != nil {
+			return err
+		}
+	}
+	for _, c := range children {
+		if err := encodeXMLElement(enc, c.name, c.value); err != nil {
+			return errors.WithPath(err, c.name)
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
@@ -0,0 +1,111 @@
+package marshaler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+)
+
+func TestXML_Marshal(t *testing.T) {
+	m := xmlMarshaler{mediaType: "application/xml"}
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			v      interface{}
+			expect string
+		}{
+			"string": {
+				v:      "<message>hello</message>",
+				expect: "<message>hello</message>",
+			},
+			"text": {
+				v: map[string]interface{}{
+					"message": "hello",
+				},
+				expect: xmlHeader + "<message>hello</message>",
+			},
+			"attributes and children": {
+				v: map[string]interface{}{
+					"user": map[string]interface{}{
+						"@id":  1,
+						"name": "alice",
+						"role": []interface{}{"admin", "editor"},
+						"note": map[string]interface{}{
+							"@lang": "en",
+							"#text": "a < b",
+						},
+						"empty": nil,
+					},
+				},
+				expect: xmlHeader + `<user id="1"><empty></empty><name>alice</name><note lang="en">a &lt; b</note><role>admin</role><role>editor</role></user>`,
+			},
+			"ordered": {
+				v: yaml.MapSlice{
+					{
+						Key: "soap:Envelope",
+						Value: yaml.MapSlice{
+							{Key: "@xmlns:soap", Value: "http://schemas.xmlsoap.org/soap/envelope/"},
+							{Key: "soap:Body", Value: yaml.MapSlice{
+								{Key: "GetUser", Value: yaml.MapSlice{
+									{Key: "Name", Value: "alice"},
+									{Key: "Age", Value: 20},
+								}},
+							}},
+						},
+					},
+				},
+				expect: xmlHeader + `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><GetUser><Name>alice</Name><Age>20</Age></GetUser></soap:Body></soap:Envelope>`,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				b, err := m.Marshal(test.v)
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if got := string(b); got != test.expect {
+					t.Errorf("expect %q but got %q", test.expect, got)
+				}
+			})
+		}
+	})
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			v      interface{}
+			expect string
+		}{
+			"nil": {
+				v:      nil,
+				expect: "invalid value",
+			},
+			"multiple root elements": {
+				v: map[string]interface{}{
+					"foo": "1",
+					"bar": "2",
+				},
+				expect: "expected a single root element but got 2 elements",
+			},
+			"invalid attribute": {
+				v: map[string]interface{}{
+					"user": map[string]interface{}{
+						"@id": []int{1},
+					},
+				},
+				expect: ".user.@id: expected string but got []int",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := m.Marshal(test.v)
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if got := err.Error(); !strings.Contains(got, test.expect) {
+					t.Errorf("%q doesn't contain %q", got, test.expect)
+				}
+			})
+		}
+	})
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"path/filepath"
+
+	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/protocol/grpc"
+	grpcproto "github.com/scenarigo/scenarigo/protocol/grpc/proto"
+)
+
+// ProtoOption represents an option to encode and decode the bodies of Content-Type: application/x-protobuf.
+// The message types are resolved from the proto files or the descriptor sets in the same way as the grpc protocol.
+type ProtoOption struct {
+	grpc.ProtoOption `yaml:",inline"`
+	// Request is the full name of the message type of the request body.
+	// If not specified, the proto parameter of the Content-Type header is used (e.g., application/x-protobuf; proto=example.Request).
+	Request string `yaml:"request,omitempty"`
+	// Response is the full name of the message type of the response body.
+	// If not specified, the proto parameter of the Content-Type header of the response is used.
+	Response string `yaml:"response,omitempty"`
+}
+
+// protoCodec holds the message types to encode and decode the bodies.
+type protoCodec struct {
+	request  string
+	response string
+	types    *grpcproto.TypeResolver
+}
+
+func (o *ProtoOption) build(ctx *context.Context) (*protoCodec, error) {
+	c := &protoCodec{}
+	if o == nil {
+		return c, nil
+	}
+	opt, err := context.ExecuteTemplate(ctx, o)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute template")
+	}
+	c.request = opt.Request
+	c.response = opt.Response
+
+	protoOpt := opt.ProtoOption.ResolvePaths(filepath.Dir(ctx.ScenarioFilepath()))
+	c.types, err = protoOpt.TypeResolver(ctx.RequestContext())
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -3,22 +3,31 @@ package http
 import (
 	"bytes"
 	"compress/gzip"
+	gocontext "context"
 	"fmt"
 	"io"
 	"mime"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
+	"path/filepath"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/goccy/go-yaml"
 	"github.com/mattn/go-encoding"
 	"github.com/scenarigo/scenarigo/context"
 	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/internal/artifact"
+	"github.com/scenarigo/scenarigo/internal/fileutil"
+	"github.com/scenarigo/scenarigo/internal/netutil"
 	"github.com/scenarigo/scenarigo/internal/queryutil"
 	"github.com/scenarigo/scenarigo/internal/reflectutil"
+	"github.com/scenarigo/scenarigo/internal/tlsutil"
 	"github.com/scenarigo/scenarigo/protocol/http/marshaler"
 	"github.com/scenarigo/scenarigo/protocol/http/unmarshaler"
+	"github.com/scenarigo/scenarigo/reporter"
 	"github.com/scenarigo/scenarigo/version"
 )
 
@@ -32,6 +41,23 @@ type Request struct {
 	Query  interface{} `yaml:"query,omitempty"`
 	Header interface{} `yaml:"header,omitempty"`
 	Body   interface{} `yaml:"body,omitempty"`
+	// BodyFile is the path of the file to send as the body instead of body. The path is relative to the scenario file.
+	// JSON and YAML files are decoded and encoded again according to the Content-Type header as body, and the other files are sent as a string.
+	BodyFile string `yaml:"bodyFile,omitempty"`
+	// Auth is the authentication of the request. If not specified, protocols.http.auth is used.
+	Auth *AuthOption `yaml:"auth,omitempty"`
+	// SSE is the option to read the response of Content-Type: text/event-stream.
+	// The response is read as a list of events only if it is specified.
+	SSE *SSEOption `yaml:"sse,omitempty"`
+	// Proto is the option to encode and decode the bodies of Content-Type: application/x-protobuf.
+	Proto *ProtoOption `yaml:"proto,omitempty"`
+	// Socket is the path of the Unix domain socket to connect instead of the host of the URL.
+	Socket string `yaml:"socket,omitempty"`
+	// ResolvedAddress is the address connected instead of the host of the URL by the resolve configuration.
+	// It is set only to the request dump.
+	ResolvedAddress string `yaml:"resolvedAddress,omitempty"`
+	// Save is the option to save the raw response body to a file in the artifacts directory.
+	Save *artifact.SaveOption `yaml:"save,omitempty"`
 }
 
 // RequestExtractor represents a request dump.
@@ -55,6 +81,11 @@ type response struct {
 	StatusCode int                 `yaml:"statusCode,omitempty"`
 	Header     map[string][]string `yaml:"header,omitempty"`
 	Body       interface{}         `yaml:"body,omitempty"`
+	// Timing and TLS are not dumped to the logs since they are too verbose and change every time.
+	Timing *Timing                  `yaml:"timing,omitempty"`
+	TLS    *tlsutil.ConnectionState `yaml:"tls,omitempty"`
+	// Artifact is the path of the file saved by the save option.
+	Artifact string `yaml:"artifact,omitempty"`
 }
 
 // ResponseExtractor represents a response dump.
@@ -92,21 +123,70 @@ func (r *Request) addIndent(s string, indentNum int) string {
 
 // Invoke implements protocol.Invoker interface.
 func (r *Request) Invoke(ctx *context.Context) (*context.Context, interface{}, error) {
-	client, err := r.buildClient(ctx)
+	if r.BodyFile != "" {
+		if r.Body != nil {
+			return ctx, nil, errors.ErrorPath("bodyFile", "body and bodyFile can't be specified at the same time")
+		}
+		body, err := fileutil.Load(ctx, r.BodyFile)
+		if err != nil {
+			return ctx, nil, errors.WithPath(err, "bodyFile")
+		}
+		req := *r
+		req.Body, req.BodyFile = body, ""
+		return req.Invoke(ctx)
+	}
+	socket, err := r.buildSocket(ctx)
+	if err != nil {
+		return ctx, nil, err
+	}
+	resolver := r.resolver(ctx, socket)
+	client, err := r.buildClient(ctx, socket, resolver)
 	if err != nil {
 		return ctx, nil, errors.WithPath(err, "client")
 	}
-	req, reqBody, err := r.buildRequest(ctx)
+	if socket != "" {
+		// the transport for the socket is created for each request
+		defer client.CloseIdleConnections()
+	}
+	codec, err := r.Proto.build(ctx)
+	if err != nil {
+		return ctx, nil, errors.WithPath(err, "proto")
+	}
+	req, reqBody, err := r.buildRequest(ctx, codec)
+	if err != nil {
+		return ctx, nil, err
+	}
+	if client.Jar != nil {
+		if cookies := req.Cookies(); len(cookies) > 0 {
+			// the cookies in the Cookie header take precedence over the ones in the jar
+			c := *client
+			c.Jar = newOverrideCookieJar(client.Jar, cookies)
+			client = &c
+		}
+	}
+
+	sse, err := r.SSE.build()
+	if err != nil {
+		return ctx, nil, errors.WithPath(err, "sse")
+	}
+
+	// the authentication must be the last transformation of the request
+	// since the signing methods (hmac, sigv4) need the final URL, header, and body
+	credentialHeaders, err := r.authenticate(ctx, req)
 	if err != nil {
 		return ctx, nil, err
 	}
 
+	header := redactHeader(req.Header, credentialHeaders)
+	resolvedAddr, _ := resolver.Resolve(hostPort(req.URL))
 	//nolint:exhaustruct
 	reqDump := &Request{
-		Method: req.Method,
-		URL:    req.URL.String(),
-		Header: req.Header,
-		Body:   reqBody,
+		Method:          req.Method,
+		URL:             req.URL.String(),
+		Header:          header,
+		Body:            reqBody,
+		Socket:          socket,
+		ResolvedAddress: resolvedAddr,
 	}
 	ctx = ctx.WithRequest((*RequestExtractor)(reqDump))
 	if b, err := yaml.Marshal(reqDump); err == nil {
@@ -114,49 +194,150 @@ func (r *Request) Invoke(ctx *context.Context) (*context.Context, interface{}, e
 	} else {
 		ctx.Reporter().Logf("failed to dump request:\n%s", err)
 	}
+	if cmd, err := curlCommand(req, header, r.curlFlags(req.URL, socket, resolvedAddr)...); err == nil {
+		ctx.Reporter().Logf("curl:\n%s", r.addIndent(cmd, indentNum))
+		reporter.AddAttribute(ctx.Reporter(), "curl", cmd)
+	} else {
+		ctx.Reporter().Logf("failed to create curl command:\n%s", err)
+	}
 
-	resp, err := client.Do(req)
+	tr := newTimingRecorder()
+	ex := &harExchange{
+		req:    req,
+		header: header,
+		timing: tr,
+	}
+	reqCtx, cancel := gocontext.WithCancel(req.Context())
+	defer cancel()
+	resp, err := client.Do(req.WithContext(httptrace.WithClientTrace(reqCtx, tr.clientTrace())))
 	if err != nil {
+		tr.finish()
+		ex.err = err
+		recordHAR(ctx, ex)
 		return ctx, nil, errors.Errorf("failed to send request: %s", err)
 	}
 	defer resp.Body.Close()
-
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return ctx, nil, errors.Errorf("failed to read response body: %s", err)
-	}
+	ex.resp = resp
 
 	rvalue := response{
 		Status:     resp.Status,
 		StatusCode: resp.StatusCode,
 		Header:     resp.Header,
 		Body:       nil,
+		TLS:        tlsutil.NewConnectionState(resp.TLS, time.Now()),
+	}
+	if r.SSE != nil && isEventStream(resp.Header) {
+		var body io.Reader = resp.Body
+		var raw bytes.Buffer
+		if r.Save != nil {
+			// keep the raw stream to save it as it is
+			body = io.TeeReader(resp.Body, &raw)
+		}
+		events, err := sse.read(body, cancel)
+		tr.finish()
+		ex.err = err
+		recordHAR(ctx, ex)
+		if err != nil {
+			return ctx, nil, err
+		}
+		rvalue.Body = events
+		rvalue.Timing = r.timing(ctx, tr)
+		return r.setResponse(ctx, rvalue, raw.Bytes())
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	tr.finish()
+	ex.body, ex.err = b, err
+	recordHAR(ctx, ex)
+	if err != nil {
+		return ctx, nil, errors.Errorf("failed to read response body: %s", err)
 	}
+	rvalue.Timing = r.timing(ctx, tr)
 	if len(b) > 0 {
-		unmarshaler := unmarshaler.Get(resp.Header.Get("Content-Type"))
+		contentType := resp.Header.Get("Content-Type")
+		um := unmarshaler.Get(contentType)
 		var respBody interface{}
-		if err := unmarshaler.Unmarshal(b, &respBody); err != nil {
-			return ctx, nil, errors.Errorf("failed to unmarshal response body as %s: %s: %s", unmarshaler.MediaType(), string(b), err)
+		if umo, ok := um.(unmarshaler.ResponseUnmarshalerWithOptions); ok {
+			err = umo.UnmarshalWithOptions(b, &respBody, &unmarshaler.UnmarshalOptions{
+				ContentType:  contentType,
+				ProtoMessage: codec.response,
+				ProtoTypes:   codec.types,
+			})
+		} else {
+			err = um.Unmarshal(b, &respBody)
+		}
+		if err != nil {
+			return ctx, nil, errors.Errorf("failed to unmarshal response body as %s: %s: %s", um.MediaType(), string(b), err)
 		}
 		rvalue.Body = respBody
 	}
+	return r.setResponse(ctx, rvalue, b)
+}
+
+// timing returns the time spent on each phase of the request and adds it to the test report.
+func (r *Request) timing(ctx *context.Context, tr *timingRecorder) *Timing {
+	t := tr.timing()
+	reporter.SetTiming(ctx.Reporter(), t.report())
+	return t
+}
+
+func (r *Request) setResponse(ctx *context.Context, rvalue response, raw []byte) (*context.Context, interface{}, error) {
 	ctx = ctx.WithResponse((*ResponseExtractor)(&rvalue))
-	if b, err := yaml.Marshal(rvalue); err == nil {
+	dump := rvalue
+	dump.Timing = nil
+	dump.TLS = nil
+	if b, err := yaml.Marshal(dump); err == nil {
 		ctx.Reporter().Logf("response:\n%s", r.addIndent(string(b), indentNum))
 	} else {
 		ctx.Reporter().Logf("failed to dump response:\n%s", err)
 	}
+	// rvalue is referred by the context, so the path can be used by the templates of the step (e.g., {{response.artifact}})
+	path, err := r.Save.Save(ctx, raw)
+	if err != nil {
+		return ctx, nil, errors.WithPath(err, "save")
+	}
+	rvalue.Artifact = path
 	return ctx, rvalue, nil
 }
 
-func (r *Request) buildClient(ctx *context.Context) (*http.Client, error) {
+// authenticate sets the credentials to req and returns the header names which contain the credentials.
+func (r *Request) authenticate(ctx *context.Context, req *http.Request) ([]string, error) {
+	auth := r.Auth
+	if auth == nil {
+		auth = httpProtocol.getAuth()
+	}
+	if auth == nil {
+		return nil, nil
+	}
+	auth, err := context.ExecuteTemplate(ctx, auth.clone())
+	if err != nil {
+		return nil, errors.WrapPath(err, "auth", "failed to execute template")
+	}
+	names, err := auth.apply(ctx, req)
+	if err != nil {
+		return nil, errors.WithPath(err, "auth")
+	}
+	return names, nil
+}
+
+func (r *Request) buildClient(ctx *context.Context, socket string, resolver netutil.Resolver) (*http.Client, error) {
+	c := httpProtocol.getClient()
 	client := &http.Client{
 		Transport: &charsetRoundTripper{
 			base: &encodingRoundTripper{
-				base: http.DefaultTransport,
+				base: c.getRoundTripper(socket, resolver),
 			},
 		},
 	}
+	if c != nil {
+		client.Timeout = c.timeout
+		client.CheckRedirect = c.checkRedirect
+		if c.cookieJar {
+			if cookies := ctx.Cookies(); cookies != nil {
+				client.Jar = cookies
+			}
+		}
+	}
 	if r.Client != "" {
 		x, err := ctx.ExecuteTemplate(r.Client)
 		if err != nil {
@@ -170,6 +351,48 @@ func (r *Request) buildClient(ctx *context.Context) (*http.Client, error) {
 	return client, nil
 }
 
+// resolver returns the resolver to override the addresses to connect.
+// The Unix domain socket and the custom client take precedence over the resolve configuration.
+func (r *Request) resolver(ctx *context.Context, socket string) netutil.Resolver {
+	if socket != "" || r.Client != "" {
+		return nil
+	}
+	return ctx.Resolver()
+}
+
+// curlFlags returns the flags of the curl command to send the request in the same way.
+func (r *Request) curlFlags(u *url.URL, socket, resolvedAddr string) []string {
+	var flags []string
+	if socket != "" {
+		flags = append(flags, "--unix-socket "+shellQuote(socket))
+	}
+	if resolvedAddr != "" {
+		flags = append(flags, "--connect-to "+shellQuote(hostPort(u)+":"+resolvedAddr))
+	}
+	if c := httpProtocol.getClient(); c != nil && c.h2c && r.Client == "" {
+		flags = append(flags, "--http2-prior-knowledge")
+	}
+	return flags
+}
+
+func (r *Request) buildSocket(ctx *context.Context) (string, error) {
+	if r.Socket == "" {
+		return "", nil
+	}
+	if r.Client != "" {
+		return "", errors.ErrorPath("socket", "socket can't be used with the custom client")
+	}
+	x, err := ctx.ExecuteTemplate(r.Socket)
+	if err != nil {
+		return "", errors.WrapPath(err, "socket", "failed to execute template")
+	}
+	socket, ok := x.(string)
+	if !ok {
+		return "", errors.ErrorPathf("socket", "socket must be string but got %T", x)
+	}
+	return socket, nil
+}
+
 type charsetRoundTripper struct {
 	base http.RoundTripper
 }
@@ -198,6 +421,11 @@ func (rt *charsetRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 	return resp, err
 }
 
+// CloseIdleConnections closes the idle connections of the base round tripper.
+func (rt *charsetRoundTripper) CloseIdleConnections() {
+	closeIdleConnections(rt.base)
+}
+
 type readCloser struct {
 	io.Reader
 	io.Closer
@@ -225,7 +453,40 @@ func (rt *encodingRoundTripper) RoundTrip(req *http.Request) (*http.Response, er
 	return resp, err
 }
 
-func (r *Request) buildRequest(ctx *context.Context) (*http.Request, interface{}, error) {
+// CloseIdleConnections closes the idle connections of the base round tripper.
+func (rt *encodingRoundTripper) CloseIdleConnections() {
+	closeIdleConnections(rt.base)
+}
+
+// overrideCookieJar excludes the cookies which are overridden by the Cookie header from the jar.
+type overrideCookieJar struct {
+	http.CookieJar
+	names map[string]struct{}
+}
+
+func newOverrideCookieJar(jar http.CookieJar, cookies []*http.Cookie) *overrideCookieJar {
+	names := make(map[string]struct{}, len(cookies))
+	for _, c := range cookies {
+		names[c.Name] = struct{}{}
+	}
+	return &overrideCookieJar{
+		CookieJar: jar,
+		names:     names,
+	}
+}
+
+// Cookies implements http.CookieJar interface.
+func (j *overrideCookieJar) Cookies(u *url.URL) []*http.Cookie {
+	var cookies []*http.Cookie
+	for _, c := range j.CookieJar.Cookies(u) {
+		if _, ok := j.names[c.Name]; !ok {
+			cookies = append(cookies, c)
+		}
+	}
+	return cookies
+}
+
+func (r *Request) buildRequest(ctx *context.Context, codec *protoCodec) (*http.Request, interface{}, error) {
 	method := http.MethodGet
 	if r.Method != "" {
 		method = r.Method
@@ -265,12 +526,34 @@ func (r *Request) buildRequest(ctx *context.Context) (*http.Request, interface{}
 		}
 		body = x
 
-		marshaler := marshaler.Get(header.Get("Content-Type"))
-		b, err := marshaler.Marshal(body)
-		if err != nil {
-			return nil, nil, errors.ErrorPathf("body", "failed to marshal request body as %s: %#v: %s", marshaler.MediaType(), body, err)
+		m := marshaler.Get(header.Get("Content-Type"))
+		if mo, ok := m.(marshaler.RequestMarshalerWithOptions); ok {
+			opts := &marshaler.MarshalOptions{
+				ContentType: header.Get("Content-Type"),
+				BaseDir:     filepath.Dir(ctx.ScenarioFilepath()),
+			}
+			if codec != nil {
+				opts.ProtoMessage = codec.request
+				opts.ProtoTypes = codec.types
+			}
+			b, err := mo.MarshalWithOptions(body, opts)
+			if err != nil {
+				return nil, nil, errors.WrapPathf(err, "body", "failed to marshal request body as %s", m.MediaType())
+			}
+			if b.ContentType != "" {
+				header.Set("Content-Type", b.ContentType)
+			}
+			if b.Dump != nil {
+				body = b.Dump
+			}
+			reader = bytes.NewReader(b.Data)
+		} else {
+			b, err := m.Marshal(body)
+			if err != nil {
+				return nil, nil, errors.ErrorPathf("body", "failed to marshal request body as %s: %#v: %s", m.MediaType(), body, err)
+			}
+			reader = bytes.NewReader(b)
 		}
-		reader = bytes.NewReader(b)
 	}
 
 	req, err := http.NewRequest(strings.ToUpper(method), urlStr, reader)
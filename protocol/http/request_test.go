@@ -3,23 +3,37 @@ package http
 import (
 	"bytes"
 	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/goccy/go-yaml"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	"golang.org/x/text/encoding/japanese"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/internal/artifact"
 	"github.com/scenarigo/scenarigo/internal/queryutil"
 	"github.com/scenarigo/scenarigo/internal/testutil"
+	"github.com/scenarigo/scenarigo/protocol/grpc"
+	"github.com/scenarigo/scenarigo/protocol/http/marshaler"
 	"github.com/scenarigo/scenarigo/reporter"
+	testpb "github.com/scenarigo/scenarigo/testdata/gen/pb/test"
 	"github.com/scenarigo/scenarigo/version"
 	"github.com/zoncoen/query-go"
 )
@@ -256,6 +270,31 @@ func TestRequest_Invoke(t *testing.T) {
 		w.Header().Set("Content-Type", "application/json; charset=Shift_JIS")
 		_, _ = w.Write(b)
 	})
+	m.HandleFunc("/echo/xml", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var body struct {
+			ID      string `xml:"id,attr"`
+			Message string `xml:"message"`
+		}
+		if err := xml.NewDecoder(req.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(fmt.Sprintf(`<?xml version="1.0"?><result id="%s"><message>%s</message></result>`, body.ID, body.Message)))
+	})
+	m.HandleFunc("/echo/ndjson", func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = io.Copy(w, req.Body)
+	})
 	srv := httptest.NewServer(m)
 	defer srv.Close()
 
@@ -382,6 +421,76 @@ func TestRequest_Invoke(t *testing.T) {
 				Body: map[string]string{"message": "hey"},
 			},
 		},
+		"POST (XML)": {
+			request: &Request{
+				Method: http.MethodPost,
+				URL:    srv.URL + "/echo/xml",
+				Header: map[string][]string{"Content-Type": {"application/xml"}},
+				Body: map[string]interface{}{
+					"request": map[string]interface{}{
+						"@id":     "123",
+						"message": "hey",
+					},
+				},
+			},
+			response: response{
+				Status:     "200 OK",
+				StatusCode: 200,
+				Body: map[string]interface{}{
+					"result": map[string]interface{}{
+						"@id":     "123",
+						"message": "hey",
+					},
+				},
+			},
+			requestDump: &RequestExtractor{
+				Method: http.MethodPost,
+				URL:    srv.URL + "/echo/xml",
+				Header: http.Header{
+					"Accept-Encoding": {"gzip"},
+					"Content-Type":    {"application/xml"},
+					"User-Agent":      {fmt.Sprintf("scenarigo/%s", version.String())},
+				},
+				Body: map[string]interface{}{
+					"request": map[string]interface{}{
+						"@id":     "123",
+						"message": "hey",
+					},
+				},
+			},
+		},
+		"POST (NDJSON)": {
+			request: &Request{
+				Method: http.MethodPost,
+				URL:    srv.URL + "/echo/ndjson",
+				Header: map[string][]string{"Content-Type": {"application/x-ndjson"}},
+				Body: []interface{}{
+					map[string]interface{}{"id": 1},
+					map[string]interface{}{"id": 2},
+				},
+			},
+			response: response{
+				Status:     "200 OK",
+				StatusCode: 200,
+				Body: []interface{}{
+					map[string]interface{}{"id": json.Number("1")},
+					map[string]interface{}{"id": json.Number("2")},
+				},
+			},
+			requestDump: &RequestExtractor{
+				Method: http.MethodPost,
+				URL:    srv.URL + "/echo/ndjson",
+				Header: http.Header{
+					"Accept-Encoding": {"gzip"},
+					"Content-Type":    {"application/x-ndjson"},
+					"User-Agent":      {fmt.Sprintf("scenarigo/%s", version.String())},
+				},
+				Body: []interface{}{
+					map[string]interface{}{"id": 1},
+					map[string]interface{}{"id": 2},
+				},
+			},
+		},
 		"with vars": {
 			vars: map[string]string{
 				"url":     srv.URL + "/echo",
@@ -471,7 +580,7 @@ func TestRequest_Invoke(t *testing.T) {
 			if diff := cmp.Diff(test.requestDump, ctx.Request()); diff != "" {
 				t.Errorf("differs: (-want +got)\n%s", diff)
 			}
-			if diff := cmp.Diff((*ResponseExtractor)(&test.response), ctx.Response(), cmpopts.IgnoreFields(ResponseExtractor{}, "Header")); diff != "" {
+			if diff := cmp.Diff((*ResponseExtractor)(&test.response), ctx.Response(), cmpopts.IgnoreFields(ResponseExtractor{}, "Header", "Timing")); diff != "" {
 				t.Errorf("differs: (-want +got)\n%s", diff)
 			}
 		})
@@ -556,6 +665,717 @@ func TestRequest_Invoke_Error(t *testing.T) {
 	}
 }
 
+func TestRequest_Invoke_ClientOption(t *testing.T) {
+	caCert, cert, key := testutil.GenerateCert(t)
+	serverCert, err := tls.LoadX509KeyPair(cert, key)
+	if err != nil {
+		t.Fatalf("failed to load certificate: %s", err)
+	}
+	b, err := os.ReadFile(caCert)
+	if err != nil {
+		t.Fatalf("failed to read CA certificate: %s", err)
+	}
+	cp := x509.NewCertPool()
+	cp.AppendCertsFromPEM(b)
+
+	m := http.NewServeMux()
+	m.HandleFunc("/ok", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	m.HandleFunc("/slow", func(w http.ResponseWriter, req *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+	m.HandleFunc("/redirect/{n}", func(w http.ResponseWriter, req *http.Request) {
+		n, _ := strconv.Atoi(req.PathValue("n"))
+		if n == 0 {
+			http.Redirect(w, req, "/ok", http.StatusFound)
+			return
+		}
+		http.Redirect(w, req, fmt.Sprintf("/redirect/%d", n-1), http.StatusFound)
+	})
+	srv := httptest.NewServer(m)
+	t.Cleanup(srv.Close)
+
+	tlsSrv := httptest.NewUnstartedServer(m)
+	tlsSrv.TLS = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{serverCert},
+	}
+	tlsSrv.StartTLS()
+	t.Cleanup(tlsSrv.Close)
+
+	mtlsSrv := httptest.NewUnstartedServer(m)
+	mtlsSrv.TLS = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    cp,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	mtlsSrv.StartTLS()
+	t.Cleanup(mtlsSrv.Close)
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Host != "proxied.example.com" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(proxy.Close)
+
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			option     string
+			url        string
+			expectCode int
+		}{
+			"no option": {
+				url:        srv.URL + "/redirect/3",
+				expectCode: http.StatusOK,
+			},
+			"redirect disabled": {
+				option:     "client:\n  redirect:\n    disabled: true",
+				url:        srv.URL + "/redirect/0",
+				expectCode: http.StatusFound,
+			},
+			"max redirects": {
+				option:     "client:\n  redirect:\n    max: 2",
+				url:        srv.URL + "/redirect/1",
+				expectCode: http.StatusOK,
+			},
+			"CA certificate": {
+				option:     fmt.Sprintf("client:\n  tls:\n    certificate: %s", caCert),
+				url:        tlsSrv.URL + "/ok",
+				expectCode: http.StatusOK,
+			},
+			"skip verification": {
+				option:     "client:\n  tls:\n    skip: true",
+				url:        tlsSrv.URL + "/ok",
+				expectCode: http.StatusOK,
+			},
+			"client certificate": {
+				option:     fmt.Sprintf("client:\n  tls:\n    certificate: %s\n    clientCertificate: %s\n    clientKey: %s", caCert, cert, key),
+				url:        mtlsSrv.URL + "/ok",
+				expectCode: http.StatusOK,
+			},
+			"proxy": {
+				option:     fmt.Sprintf("client:\n  proxy: %s", proxy.URL),
+				url:        "http://proxied.example.com",
+				expectCode: http.StatusNoContent,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				setClientOption(t, test.option)
+				_, res, err := (&Request{URL: test.url}).Invoke(context.FromT(t))
+				if err != nil {
+					t.Fatalf("failed to invoke: %s", err)
+				}
+				resp, ok := res.(response)
+				if !ok {
+					t.Fatalf("failed to convert from %T to response", res)
+				}
+				if got, expect := resp.StatusCode, test.expectCode; got != expect {
+					t.Errorf("expect status code %d but got %d", expect, got)
+				}
+			})
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			option string
+			url    string
+			expect string
+		}{
+			"timeout": {
+				option: "client:\n  timeout: 10ms",
+				url:    srv.URL + "/slow",
+				expect: "Client.Timeout exceeded",
+			},
+			"too many redirects": {
+				option: "client:\n  redirect:\n    max: 1",
+				url:    srv.URL + "/redirect/1",
+				expect: "stopped after 1 redirects",
+			},
+			"unknown certificate authority": {
+				url:    tlsSrv.URL + "/ok",
+				expect: "certificate signed by unknown authority",
+			},
+			"no client certificate": {
+				option: fmt.Sprintf("client:\n  tls:\n    certificate: %s", caCert),
+				url:    mtlsSrv.URL + "/ok",
+				expect: "failed to send request",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				setClientOption(t, test.option)
+				_, _, err := (&Request{URL: test.url}).Invoke(context.FromT(t))
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if got := err.Error(); !strings.Contains(got, test.expect) {
+					t.Errorf("%q doesn't contain %q", got, test.expect)
+				}
+			})
+		}
+	})
+}
+
+func TestRequest_Invoke_CookieJar(t *testing.T) {
+	m := http.NewServeMux()
+	m.HandleFunc("/login", func(w http.ResponseWriter, req *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session_id", Value: "xxx"})
+		http.SetCookie(w, &http.Cookie{Name: "lang", Value: "en"})
+		w.WriteHeader(http.StatusOK)
+	})
+	m.HandleFunc("/echo", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Cookie", req.Header.Get("Cookie"))
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(m)
+	t.Cleanup(srv.Close)
+
+	tests := map[string]struct {
+		option       string
+		header       map[string]string
+		expectCookie string
+		expectJar    bool
+	}{
+		"disabled": {
+			expectCookie: "",
+		},
+		"enabled": {
+			option:       "client:\n  cookieJar: true",
+			expectCookie: "session_id=xxx; lang=en",
+			expectJar:    true,
+		},
+		"override by header": {
+			option: "client:\n  cookieJar: true",
+			header: map[string]string{
+				"Cookie": "session_id=yyy",
+			},
+			expectCookie: "session_id=yyy; lang=en",
+			expectJar:    true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			setClientOption(t, test.option)
+			ctx := context.FromT(t).WithCookies(context.NewCookies())
+			if _, _, err := (&Request{URL: srv.URL + "/login"}).Invoke(ctx); err != nil {
+				t.Fatalf("failed to invoke: %s", err)
+			}
+			v, ok := ctx.Cookies().Get("session_id")
+			if ok != test.expectJar {
+				t.Fatalf("expect %t but got %t", test.expectJar, ok)
+			}
+			if ok && v != "xxx" {
+				t.Errorf("expect %q but got %q", "xxx", v)
+			}
+
+			req := &Request{URL: srv.URL + "/echo"}
+			if test.header != nil {
+				req.Header = test.header
+			}
+			_, res, err := req.Invoke(ctx)
+			if err != nil {
+				t.Fatalf("failed to invoke: %s", err)
+			}
+			resp, ok := res.(response)
+			if !ok {
+				t.Fatalf("failed to convert from %T to response", res)
+			}
+			if got, expect := http.Header(resp.Header).Get("X-Cookie"), test.expectCookie; got != expect {
+				t.Errorf("expect cookie %q but got %q", expect, got)
+			}
+		})
+	}
+}
+
+func TestRequest_Invoke_Multipart(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+		fh := req.MultipartForm.File["file"][0]
+		f, err := fh.Open()
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+		b, err := io.ReadAll(f)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"name":        req.FormValue("name"),
+			"filename":    fh.Filename,
+			"contentType": fh.Header.Get("Content-Type"),
+			"content":     string(b),
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "avatar.png"), []byte("PNG"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	req := &Request{
+		Method: http.MethodPost,
+		URL:    srv.URL,
+		Header: map[string]string{
+			"Content-Type": "multipart/form-data",
+		},
+		Body: map[string]interface{}{
+			"name": "scenarigo",
+			"file": map[string]interface{}{
+				"file": "avatar.png",
+			},
+		},
+	}
+	ctx := context.FromT(t).WithScenarioFilepath(filepath.Join(dir, "scenario.yaml"))
+	ctx, res, err := req.Invoke(ctx)
+	if err != nil {
+		t.Fatalf("failed to invoke: %s", err)
+	}
+	resp, ok := res.(response)
+	if !ok {
+		t.Fatalf("failed to convert from %T to response", res)
+	}
+	if diff := cmp.Diff(map[string]interface{}{
+		"name":        "scenarigo",
+		"filename":    "avatar.png",
+		"contentType": "image/png",
+		"content":     "PNG",
+	}, resp.Body); diff != "" {
+		t.Errorf("differs (-want +got):\n%s", diff)
+	}
+	dump, ok := ctx.Request().(*RequestExtractor)
+	if !ok {
+		t.Fatalf("unexpected request type: %T", ctx.Request())
+	}
+	if diff := cmp.Diff([]marshaler.MultipartPartDump{
+		{Name: "file", Filename: "avatar.png", ContentType: "image/png", Size: 3},
+		{Name: "name", Value: "scenarigo"},
+	}, dump.Body); diff != "" {
+		t.Errorf("request dump differs (-want +got):\n%s", diff)
+	}
+	if ct := dump.Header.(http.Header).Get("Content-Type"); !strings.HasPrefix(ct, "multipart/form-data; boundary=") {
+		t.Errorf("unexpected Content-Type: %s", ct)
+	}
+}
+
+func TestRequest_Invoke_BodyFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		contentType := req.Header.Get("Content-Type")
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = io.Copy(w, req.Body)
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"body.json": `{"id": "{{vars.id}}", "tags": ["a", "b"]}`,
+		"body.txt":  "id={{vars.id}}",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			req        *Request
+			expectBody interface{}
+		}{
+			"JSON": {
+				req: &Request{
+					Method:   http.MethodPost,
+					URL:      srv.URL,
+					BodyFile: "body.json",
+				},
+				expectBody: map[string]interface{}{
+					"id":   "1",
+					"tags": []interface{}{"a", "b"},
+				},
+			},
+			"text": {
+				req: &Request{
+					Method: http.MethodPost,
+					URL:    srv.URL,
+					Header: map[string]string{
+						"Content-Type": "text/plain",
+					},
+					BodyFile: "{{vars.dir}}/body.txt",
+				},
+				expectBody: "id=1",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				ctx := context.FromT(t).
+					WithScenarioFilepath(filepath.Join(dir, "scenario.yaml")).
+					WithVars(map[string]string{"id": "1", "dir": dir})
+				ctx, res, err := test.req.Invoke(ctx)
+				if err != nil {
+					t.Fatalf("failed to invoke: %s", err)
+				}
+				resp, ok := res.(response)
+				if !ok {
+					t.Fatalf("failed to convert from %T to response", res)
+				}
+				if diff := cmp.Diff(test.expectBody, resp.Body); diff != "" {
+					t.Errorf("differs (-want +got):\n%s", diff)
+				}
+				dump, ok := ctx.Request().(*RequestExtractor)
+				if !ok {
+					t.Fatalf("unexpected request type: %T", ctx.Request())
+				}
+				if dump.Body == nil {
+					t.Error("request body is not dumped")
+				}
+				if test.req.BodyFile == "" {
+					t.Error("request is modified")
+				}
+			})
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			req    *Request
+			expect string
+		}{
+			"with body": {
+				req: &Request{
+					URL:      srv.URL,
+					Body:     "body",
+					BodyFile: "body.json",
+				},
+				expect: ".bodyFile: body and bodyFile can't be specified at the same time",
+			},
+			"not found": {
+				req: &Request{
+					URL:      srv.URL,
+					BodyFile: "not-found.json",
+				},
+				expect: ".bodyFile: failed to read file",
+			},
+			"invalid template": {
+				req: &Request{
+					URL:      srv.URL,
+					BodyFile: "{{",
+				},
+				expect: ".bodyFile: failed to execute template",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				ctx := context.FromT(t).WithScenarioFilepath(filepath.Join(dir, "scenario.yaml"))
+				_, _, err := test.req.Invoke(ctx)
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if got := err.Error(); !strings.Contains(got, test.expect) {
+					t.Errorf("%q doesn't contain %q", got, test.expect)
+				}
+			})
+		}
+	})
+}
+
+func TestRequest_Invoke_Proto(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var in testpb.EchoRequest
+		if err := proto.Unmarshal(b, &in); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		out, err := proto.Marshal(&testpb.EchoResponse{
+			MessageId:   in.GetMessageId(),
+			MessageBody: in.GetMessageBody(),
+			UserType:    testpb.UserType_STAFF,
+		})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", req.URL.Query().Get("contentType"))
+		_, _ = w.Write(out)
+	}))
+	t.Cleanup(srv.Close)
+
+	expectBody := map[string]interface{}{
+		"messageId":   "1",
+		"messageBody": "hello",
+		"userType":    "STAFF",
+	}
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			req *Request
+		}{
+			"message options": {
+				req: &Request{
+					Method: http.MethodPost,
+					URL:    srv.URL + "?contentType=application/x-protobuf",
+					Header: map[string]string{
+						"Content-Type": "application/x-protobuf",
+					},
+					Body: map[string]interface{}{
+						"messageId":   "{{vars.id}}",
+						"messageBody": "hello",
+					},
+					Proto: &ProtoOption{
+						ProtoOption: grpc.ProtoOption{
+							Files: []string{"../../testdata/proto/test/test.proto"},
+						},
+						Request:  "scenarigo.testdata.test.EchoRequest",
+						Response: "scenarigo.testdata.test.EchoResponse",
+					},
+				},
+			},
+			"proto parameters": {
+				req: &Request{
+					Method: http.MethodPost,
+					URL:    srv.URL + "?" + url.Values{"contentType": {"application/x-protobuf; proto=scenarigo.testdata.test.EchoResponse"}}.Encode(),
+					Header: map[string]string{
+						"Content-Type": "application/x-protobuf; proto=scenarigo.testdata.test.EchoRequest",
+					},
+					Body: map[string]interface{}{
+						"messageId":   "{{vars.id}}",
+						"messageBody": "hello",
+					},
+					Proto: &ProtoOption{
+						ProtoOption: grpc.ProtoOption{
+							Imports: []string{"../../testdata/proto"},
+							Files:   []string{"test/test.proto"},
+						},
+					},
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				ctx := context.FromT(t).WithVars(map[string]string{"id": "1"})
+				_, res, err := test.req.Invoke(ctx)
+				if err != nil {
+					t.Fatalf("failed to invoke: %s", err)
+				}
+				resp, ok := res.(response)
+				if !ok {
+					t.Fatalf("failed to convert from %T to response", res)
+				}
+				if got, expect := resp.StatusCode, http.StatusOK; got != expect {
+					t.Fatalf("expect status code %d but got %d", expect, got)
+				}
+				if diff := cmp.Diff(expectBody, resp.Body); diff != "" {
+					t.Errorf("differs (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			req    *Request
+			expect string
+		}{
+			"file not found": {
+				req: &Request{
+					URL: srv.URL,
+					Proto: &ProtoOption{
+						ProtoOption: grpc.ProtoOption{
+							Files: []string{"not-found.proto"},
+						},
+					},
+				},
+				expect: ".proto: failed to compile:",
+			},
+			"unknown message type": {
+				req: &Request{
+					Method: http.MethodPost,
+					URL:    srv.URL,
+					Header: map[string]string{
+						"Content-Type": "application/x-protobuf",
+					},
+					Body: map[string]interface{}{
+						"messageId": "1",
+					},
+					Proto: &ProtoOption{
+						ProtoOption: grpc.ProtoOption{
+							Files: []string{"../../testdata/proto/test/test.proto"},
+						},
+						Request: "scenarigo.testdata.test.Unknown",
+					},
+				},
+				expect: `.body: failed to marshal request body as application/x-protobuf: failed to find message type "scenarigo.testdata.test.Unknown"`,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, _, err := test.req.Invoke(context.FromT(t))
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if !strings.Contains(err.Error(), test.expect) {
+					t.Errorf("expect error %q but got %q", test.expect, err)
+				}
+			})
+		}
+	})
+}
+
+func TestRequest_Invoke_Save(t *testing.T) {
+	body := "{\n  \"items\": [\"a\", \"b\"]\n}\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			save   *artifact.SaveOption
+			expect string
+		}{
+			"raw body": {
+				save: &artifact.SaveOption{
+					Path: "response.json",
+				},
+				expect: body,
+			},
+			"queried value": {
+				save: &artifact.SaveOption{
+					Path:  "response.json",
+					Value: "{{response.body.items}}",
+				},
+				expect: `["a","b"]`,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				dir := t.TempDir()
+				ctx := context.FromT(t).WithArtifactsDir(dir)
+				req := &Request{
+					URL:  srv.URL,
+					Save: test.save,
+				}
+				ctx, _, err := req.Invoke(ctx)
+				if err != nil {
+					t.Fatalf("failed to invoke: %s", err)
+				}
+				path, err := ctx.ExecuteTemplate("{{response.artifact}}")
+				if err != nil {
+					t.Fatalf("failed to execute template: %s", err)
+				}
+				if got, expect := path, filepath.Join(dir, "response.json"); got != expect {
+					t.Errorf("expect artifact %q but got %q", expect, got)
+				}
+				b, err := os.ReadFile(filepath.Join(dir, "response.json"))
+				if err != nil {
+					t.Fatalf("failed to read file: %s", err)
+				}
+				if got, expect := string(b), test.expect; got != expect {
+					t.Errorf("expect %q but got %q", expect, got)
+				}
+			})
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		ctx := context.FromT(t).WithArtifactsDir(t.TempDir())
+		req := &Request{
+			URL: srv.URL,
+			Save: &artifact.SaveOption{
+				Path: "../response.json",
+			},
+		}
+		_, _, err := req.Invoke(ctx)
+		if err == nil {
+			t.Fatal("no error")
+		}
+		if expect := ".save.path: path must be a relative path in the artifacts directory"; !strings.Contains(err.Error(), expect) {
+			t.Errorf("expect error %q but got %q", expect, err)
+		}
+	})
+}
+
+func setClientOption(t *testing.T, option string) {
+	t.Helper()
+	if err := httpProtocol.UnmarshalOption([]byte(option)); err != nil {
+		t.Fatalf("failed to set option: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := httpProtocol.UnmarshalOption(nil); err != nil {
+			t.Fatalf("failed to reset option: %s", err)
+		}
+	})
+}
+
+func TestRequest_Invoke_TLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	setClientOption(t, "client:\n  tls:\n    skip: true")
+
+	ctx, res, err := (&Request{URL: srv.URL}).Invoke(context.FromT(t))
+	if err != nil {
+		t.Fatalf("failed to invoke: %s", err)
+	}
+	resp, ok := res.(response)
+	if !ok {
+		t.Fatalf("failed to convert from %T to response", res)
+	}
+	if resp.TLS == nil {
+		t.Fatal("no TLS state")
+	}
+	if got, expect := resp.TLS.Version, "TLS 1.3"; got != expect {
+		t.Errorf("expect version %q but got %q", expect, got)
+	}
+	if got, expect := len(resp.TLS.Certificates), 1; got != expect {
+		t.Fatalf("expect %d certificates but got %d", expect, got)
+	}
+	if diff := cmp.Diff([]string{"example.com", "*.example.com"}, resp.TLS.Certificates[0].DNSNames); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+
+	var expect Expect
+	if err := yaml.UnmarshalWithOptions([]byte(`
+tls:
+  version: TLS 1.3
+  certificates:
+  - issuer: '{{$ == "O=Acme Co"}}'
+    expiresIn: '{{assert.greaterThan(duration("336h"))}}'
+`), &expect, yaml.UseOrderedMap()); err != nil {
+		t.Fatalf("failed to unmarshal: %s", err)
+	}
+	assertion, err := expect.Build(ctx)
+	if err != nil {
+		t.Fatalf("failed to build assertion: %s", err)
+	}
+	if err := assertion.Assert(res); err != nil {
+		t.Errorf("got assertion error: %s", err)
+	}
+}
+
 func TestRequest_Invoke_Log(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		m := http.NewServeMux()
@@ -605,6 +1425,10 @@ func TestRequest_Invoke_Log(t *testing.T) {
             - scenarigo/v1.0.0
           body:
             message: hey
+        curl:
+          curl -X POST 'http://127.0.0.1:12345/echo?query=hello' \
+            -H 'User-Agent: scenarigo/v1.0.0' \
+            --data-binary $'{"message": "hey"}\n'
         response:
           status: 200 OK
           statusCode: 200
@@ -662,7 +1486,7 @@ func TestRequest_buildRequest(t *testing.T) {
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			ctx := context.FromT(t)
-			req, body, err := test.req.buildRequest(ctx)
+			req, body, err := test.req.buildRequest(ctx, nil)
 			if err != nil {
 				t.Fatalf("unexpected error: %s", err)
 			}
@@ -0,0 +1,208 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/scenarigo/scenarigo/errors"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	sigV4DateFormat = "20060102"
+)
+
+// SigV4Option represents an option for the AWS Signature Version 4 request signing.
+type SigV4Option struct {
+	Region          string `yaml:"region,omitempty"`
+	Service         string `yaml:"service,omitempty"`
+	AccessKeyID     string `yaml:"accessKeyID,omitempty"`
+	SecretAccessKey string `yaml:"secretAccessKey,omitempty"`
+	// SessionToken is the token for the temporary security credentials.
+	SessionToken string `yaml:"sessionToken,omitempty"`
+}
+
+// sigV4Now returns the signing time. It is replaced in the tests.
+var sigV4Now = time.Now
+
+// sigV4IgnoredHeaders are the headers which are not signed since they may be changed after signing.
+var sigV4IgnoredHeaders = map[string]struct{}{
+	"authorization":   {},
+	"user-agent":      {},
+	"x-amzn-trace-id": {},
+	"expect":          {},
+	"content-length":  {},
+}
+
+var sigV4SignatureRegexp = regexp.MustCompile(`Signature=[0-9a-f]+`)
+
+func (o *SigV4Option) validate() error {
+	if o.Region == "" {
+		return errors.ErrorPath("region", "region is required")
+	}
+	if o.Service == "" {
+		return errors.ErrorPath("service", "service is required")
+	}
+	if o.AccessKeyID == "" {
+		return errors.ErrorPath("accessKeyID", "access key ID is required")
+	}
+	if o.SecretAccessKey == "" {
+		return errors.ErrorPath("secretAccessKey", "secret access key is required")
+	}
+	return nil
+}
+
+// sign sets the Authorization header which contains the signature of req and returns the header names which contain the credentials.
+func (o *SigV4Option) sign(req *http.Request) ([]string, error) {
+	payloadHash, err := sigV4PayloadHash(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t := sigV4Now().UTC()
+	req.Header.Set("X-Amz-Date", t.Format(sigV4TimeFormat))
+	credentialHeaders := []string{"Authorization"}
+	if o.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", o.SessionToken)
+		credentialHeaders = append(credentialHeaders, "X-Amz-Security-Token")
+	}
+	if o.Service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	canonicalHeaders, signedHeaders := sigV4CanonicalHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		sigV4CanonicalURI(req.URL, o.Service),
+		sigV4CanonicalQuery(req.URL),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{t.Format(sigV4DateFormat), o.Region, o.Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		t.Format(sigV4TimeFormat),
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := []byte("AWS4" + o.SecretAccessKey)
+	for _, s := range []string{t.Format(sigV4DateFormat), o.Region, o.Service, "aws4_request"} {
+		key = hmacSHA256(key, s)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", sigV4Algorithm+
+		" Credential="+o.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+signature,
+	)
+	return credentialHeaders, nil
+}
+
+func sigV4PayloadHash(req *http.Request) (string, error) {
+	if req.GetBody == nil {
+		return hashHex(nil), nil
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get request body")
+	}
+	defer rc.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", errors.Wrap(err, "failed to read request body")
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// sigV4CanonicalURI returns the URI-encoded path.
+// The path is encoded twice except for S3.
+func sigV4CanonicalURI(u *url.URL, service string) string {
+	p := u.EscapedPath()
+	if p == "" {
+		p = "/"
+	}
+	if service == "s3" {
+		return p
+	}
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = sigV4Escape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+func sigV4CanonicalQuery(u *url.URL) string {
+	query := u.Query()
+	params := make([]string, 0, len(query))
+	for k, vs := range query {
+		for _, v := range vs {
+			params = append(params, sigV4Escape(k)+"="+sigV4Escape(v))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+func sigV4CanonicalHeaders(req *http.Request) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{
+		"host": host,
+	}
+	names := []string{"host"}
+	for k, vs := range req.Header {
+		name := strings.ToLower(k)
+		if _, ok := sigV4IgnoredHeaders[name]; ok {
+			continue
+		}
+		values := make([]string, len(vs))
+		for i, v := range vs {
+			values[i] = strings.Join(strings.Fields(v), " ")
+		}
+		headers[name] = strings.Join(values, ",")
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":" + headers[name] + "\n")
+	}
+	return b.String(), strings.Join(names, ";")
+}
+
+// sigV4Escape escapes s except the unreserved characters of RFC 3986.
+func sigV4Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// redactSigV4Signature redacts the signature of the Authorization header value.
+// The credential scope and the signed headers are kept for debugging.
+func redactSigV4Signature(v string) string {
+	return sigV4SignatureRegexp.ReplaceAllString(v, "Signature="+redacted)
+}
+
+func hashHex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, s string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
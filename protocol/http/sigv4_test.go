@@ -0,0 +1,122 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scenarigo/scenarigo/context"
+)
+
+func setSigV4Now(t *testing.T, now time.Time) {
+	t.Helper()
+	orig := sigV4Now
+	sigV4Now = func() time.Time { return now }
+	t.Cleanup(func() { sigV4Now = orig })
+}
+
+// The test cases are taken from the AWS Signature Version 4 test suite.
+func TestSigV4Option_Sign(t *testing.T) {
+	setSigV4Now(t, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	opt := &SigV4Option{
+		Region:          "us-east-1",
+		Service:         "service",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	tests := map[string]struct {
+		url    string
+		expect string
+	}{
+		"get-vanilla": {
+			url:    "https://example.amazonaws.com/",
+			expect: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		"get-vanilla-query-order-key-case": {
+			url:    "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			expect: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %s", err)
+			}
+			req.Header.Set("User-Agent", "scenarigo")
+			names, err := opt.sign(req)
+			if err != nil {
+				t.Fatalf("failed to sign: %s", err)
+			}
+			if got := req.Header.Get("Authorization"); got != test.expect {
+				t.Errorf("expect %q but got %q", test.expect, got)
+			}
+			if got, expect := strings.Join(names, ","), "Authorization"; got != expect {
+				t.Errorf("expect %q but got %q", expect, got)
+			}
+		})
+	}
+}
+
+func TestRequest_Invoke_SigV4(t *testing.T) {
+	setSigV4Now(t, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	m := http.NewServeMux()
+	m.HandleFunc("/echo", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Authorization", req.Header.Get("Authorization"))
+		w.Header().Set("X-Token", req.Header.Get("X-Amz-Security-Token"))
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(m)
+	t.Cleanup(srv.Close)
+
+	ctx := context.FromT(t).WithVars(map[string]string{
+		"accessKeyID":     "AKIDEXAMPLE",
+		"secretAccessKey": "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	})
+	req := &Request{
+		Method: http.MethodPost,
+		URL:    srv.URL + "/echo",
+		Header: map[string]string{"Content-Type": "application/json"},
+		Body:   map[string]string{"message": "hello"},
+		Auth: &AuthOption{SigV4: &SigV4Option{
+			Region:          "ap-northeast-1",
+			Service:         "execute-api",
+			AccessKeyID:     "{{vars.accessKeyID}}",
+			SecretAccessKey: "{{vars.secretAccessKey}}",
+			SessionToken:    "SESSION_TOKEN",
+		}},
+	}
+	ctx, res, err := req.Invoke(ctx)
+	if err != nil {
+		t.Fatalf("failed to invoke: %s", err)
+	}
+	resp, ok := res.(response)
+	if !ok {
+		t.Fatalf("failed to convert from %T to response", res)
+	}
+	auth := http.Header(resp.Header).Get("X-Authorization")
+	prefix := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/ap-northeast-1/execute-api/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-security-token, Signature="
+	if !strings.HasPrefix(auth, prefix) {
+		t.Fatalf("expect %q has prefix %q", auth, prefix)
+	}
+	if got, expect := http.Header(resp.Header).Get("X-Token"), "SESSION_TOKEN"; got != expect {
+		t.Errorf("expect %q but got %q", expect, got)
+	}
+
+	dump, ok := ctx.Request().(*RequestExtractor)
+	if !ok {
+		t.Fatalf("failed to convert from %T to *RequestExtractor", ctx.Request())
+	}
+	h, ok := dump.Header.(http.Header)
+	if !ok {
+		t.Fatalf("failed to convert from %T to http.Header", dump.Header)
+	}
+	if got, expect := h.Get("Authorization"), prefix+"*****"; got != expect {
+		t.Errorf("expect %q but got %q", expect, got)
+	}
+	if got, expect := h.Get("X-Amz-Security-Token"), "*****"; got != expect {
+		t.Errorf("expect %q but got %q", expect, got)
+	}
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/scenarigo/scenarigo/errors"
+	"github.com/scenarigo/scenarigo/schema"
+)
+
+const mediaTypeEventStream = "text/event-stream"
+
+// SSEOption represents an option to read the Server-Sent Events stream.
+// Reading events stops when one of the conditions is satisfied or the stream is closed.
+type SSEOption struct {
+	// Count is the number of events to read.
+	Count int `yaml:"count,omitempty"`
+	// Until is the event type which terminates reading. The terminating event is included in the events.
+	Until string `yaml:"until,omitempty"`
+	// Timeout is the time limit for reading events (e.g., 10s).
+	// The events received before the timeout are returned without an error.
+	Timeout *schema.Duration `yaml:"timeout,omitempty"`
+}
+
+// sseEvent represents an event of Server-Sent Events.
+type sseEvent struct {
+	event string
+	id    *string
+	data  []string
+	retry *int
+}
+
+// value returns the event as a map which has event, id, data, and retry keys.
+// The data is decoded as JSON if possible, otherwise it is a string.
+func (e *sseEvent) value() map[string]interface{} {
+	v := map[string]interface{}{
+		"event": e.event,
+	}
+	if v["event"] == "" {
+		v["event"] = "message"
+	}
+	if e.id != nil {
+		v["id"] = *e.id
+	}
+	if e.data != nil {
+		v["data"] = decodeEventData(strings.Join(e.data, "\n"))
+	}
+	if e.retry != nil {
+		v["retry"] = *e.retry
+	}
+	return v
+}
+
+func isEventStream(header http.Header) bool {
+	mt, _, err := mime.ParseMediaType(strings.Trim(header.Get("Content-Type"), " "))
+	if err != nil {
+		return false
+	}
+	return mt == mediaTypeEventStream
+}
+
+type sseReader struct {
+	count   int
+	until   string
+	timeout time.Duration
+}
+
+func (o *SSEOption) build() (*sseReader, error) {
+	r := &sseReader{}
+	if o == nil {
+		return r, nil
+	}
+	if o.Count < 0 {
+		return nil, errors.ErrorPathf("count", "count must be greater than or equal to 0 but got %d", o.Count)
+	}
+	r.count = o.Count
+	r.until = o.Until
+	if o.Timeout != nil {
+		r.timeout = time.Duration(*o.Timeout)
+	}
+	return r, nil
+}
+
+// read reads events from the stream body.
+// Each event is a map which has event, id, data, and retry keys.
+// The cancel function is called on timeout to interrupt the blocking read.
+// It must cancel the request instead of closing the body since closing a body wrapped by the decoders of Content-Encoding doesn't interrupt the read.
+func (sr *sseReader) read(body io.Reader, cancel func()) ([]interface{}, error) {
+	var timedOut atomic.Bool
+	if sr.timeout > 0 {
+		t := time.AfterFunc(sr.timeout, func() {
+			timedOut.Store(true)
+			cancel()
+		})
+		defer t.Stop()
+	}
+
+	events := []interface{}{}
+	r := bufio.NewReader(body)
+	var (
+		ev       = &sseEvent{}
+		dispatch bool // whether the event has any field
+	)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+			if errors.Is(err, io.EOF) || timedOut.Load() {
+				return events, nil
+			}
+			return nil, errors.Errorf("failed to read event stream: %s", err)
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		// an empty line dispatches the event
+		if line == "" {
+			if dispatch {
+				v := ev.value()
+				events = append(events, v)
+				if (sr.count > 0 && len(events) >= sr.count) || (sr.until != "" && v["event"] == sr.until) {
+					return events, nil
+				}
+			}
+			ev, dispatch = &sseEvent{}, false
+			continue
+		}
+		if strings.HasPrefix(line, ":") { // comment
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			ev.event = value
+		case "data":
+			ev.data = append(ev.data, value)
+		case "id":
+			ev.id = &value
+		case "retry":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			ev.retry = &n
+		default: // ignore unknown fields
+			continue
+		}
+		dispatch = true
+	}
+}
+
+func decodeEventData(s string) interface{} {
+	d := json.NewDecoder(bytes.NewReader([]byte(s)))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err == nil && !d.More() {
+		return v
+	}
+	return s
+}
@@ -0,0 +1,254 @@
+package http
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/internal/artifact"
+	"github.com/scenarigo/scenarigo/internal/ptr"
+	"github.com/scenarigo/scenarigo/schema"
+)
+
+func TestSSEReader_Read(t *testing.T) {
+	stream := strings.Join([]string{
+		": comment",
+		"",
+		"data: hello",
+		"",
+		"event: update",
+		"id: 1",
+		`data: {"id": 1,`,
+		`data:  "name": "foo"}`,
+		"",
+		"retry: 3000",
+		"unknown: field",
+		"",
+		"event: done\r",
+		"data",
+		"\r",
+		"data: not dispatched",
+	}, "\n")
+	tests := map[string]struct {
+		option *SSEOption
+		expect []interface{}
+	}{
+		"read all": {
+			expect: []interface{}{
+				map[string]interface{}{"event": "message", "data": "hello"},
+				map[string]interface{}{"event": "update", "id": "1", "data": map[string]interface{}{"id": json.Number("1"), "name": "foo"}},
+				map[string]interface{}{"event": "message", "retry": 3000},
+				map[string]interface{}{"event": "done", "data": ""},
+			},
+		},
+		"count": {
+			option: &SSEOption{Count: 2},
+			expect: []interface{}{
+				map[string]interface{}{"event": "message", "data": "hello"},
+				map[string]interface{}{"event": "update", "id": "1", "data": map[string]interface{}{"id": json.Number("1"), "name": "foo"}},
+			},
+		},
+		"until": {
+			option: &SSEOption{Until: "update"},
+			expect: []interface{}{
+				map[string]interface{}{"event": "message", "data": "hello"},
+				map[string]interface{}{"event": "update", "id": "1", "data": map[string]interface{}{"id": json.Number("1"), "name": "foo"}},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sr, err := test.option.build()
+			if err != nil {
+				t.Fatalf("failed to build: %s", err)
+			}
+			got, err := sr.read(strings.NewReader(stream), func() {})
+			if err != nil {
+				t.Fatalf("failed to read: %s", err)
+			}
+			if diff := cmp.Diff(test.expect, got); diff != "" {
+				t.Errorf("differs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSSEOption_Build(t *testing.T) {
+	tests := map[string]struct {
+		option *SSEOption
+		expect string
+	}{
+		"invalid count": {
+			option: &SSEOption{Count: -1},
+			expect: ".count: count must be greater than or equal to 0 but got -1",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := test.option.build()
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if got := err.Error(); !strings.Contains(got, test.expect) {
+				t.Errorf("%q doesn't contain %q", got, test.expect)
+			}
+		})
+	}
+}
+
+func TestRequest_Invoke_SSE(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		var (
+			out   io.Writer = w
+			flush           = w.(http.Flusher).Flush
+		)
+		if req.URL.Query().Has("gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			gw := gzip.NewWriter(w)
+			out = gw
+			flush = func() {
+				_ = gw.Flush()
+				w.(http.Flusher).Flush()
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+		for i := range 3 {
+			fmt.Fprintf(out, "event: notification\nid: %d\ndata: {\"count\": %d}\n\n", i, i)
+			flush()
+		}
+		fmt.Fprint(out, "event: done\ndata: bye\n\n")
+		flush()
+		if req.URL.Query().Has("close") {
+			return
+		}
+		// keep the stream open
+		<-req.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+
+	tests := map[string]struct {
+		query  string
+		option *SSEOption
+		expect string
+	}{
+		"count": {
+			option: &SSEOption{Count: 2},
+			expect: `
+- event: notification
+  id: "0"
+  data:
+    count: 0
+- event: notification
+  id: "1"
+  data:
+    count: 1
+`,
+		},
+		"until": {
+			option: &SSEOption{Until: "done"},
+			expect: `
+- event: notification
+  id: "0"
+- event: notification
+  id: "1"
+- event: notification
+  id: "2"
+- event: done
+  data: bye
+`,
+		},
+		"timeout": {
+			option: &SSEOption{Timeout: ptr.To(schema.Duration(100 * time.Millisecond))},
+			expect: `
+- {}
+- {}
+- {}
+- event: done
+`,
+		},
+		"timeout with gzip": {
+			query:  "?gzip",
+			option: &SSEOption{Timeout: ptr.To(schema.Duration(100 * time.Millisecond))},
+			expect: `
+- {}
+- {}
+- {}
+- event: done
+`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			ctx, res, err := (&Request{URL: srv.URL + test.query, SSE: test.option}).Invoke(context.FromT(t))
+			if err != nil {
+				t.Fatalf("failed to invoke: %s", err)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Fatalf("too slow: %s", elapsed)
+			}
+			var body interface{}
+			if err := yaml.UnmarshalWithOptions([]byte(test.expect), &body, yaml.UseOrderedMap()); err != nil {
+				t.Fatalf("failed to unmarshal: %s", err)
+			}
+			assertion, err := (&Expect{Body: body}).Build(ctx)
+			if err != nil {
+				t.Fatalf("failed to build assertion: %s", err)
+			}
+			if err := assertion.Assert(res); err != nil {
+				t.Errorf("unexpected events: %s", err)
+			}
+		})
+	}
+
+	t.Run("save", func(t *testing.T) {
+		// the raw stream is saved instead of the parsed events
+		dir := t.TempDir()
+		req := &Request{
+			URL:  srv.URL + "?close",
+			SSE:  &SSEOption{Until: "done"},
+			Save: &artifact.SaveOption{Path: "events.txt"},
+		}
+		if _, _, err := req.Invoke(context.FromT(t).WithArtifactsDir(dir)); err != nil {
+			t.Fatalf("failed to invoke: %s", err)
+		}
+		b, err := os.ReadFile(filepath.Join(dir, "events.txt"))
+		if err != nil {
+			t.Fatalf("failed to read file: %s", err)
+		}
+		if expect := "event: notification\nid: 0\ndata: {\"count\": 0}\n\n"; !strings.HasPrefix(string(b), expect) {
+			t.Errorf("expect file starts with %q but got %q", expect, b)
+		}
+		if expect := "event: done\ndata: bye\n\n"; !strings.HasSuffix(string(b), expect) {
+			t.Errorf("expect file ends with %q but got %q", expect, b)
+		}
+	})
+
+	t.Run("without option", func(t *testing.T) {
+		// the events are not parsed unless the sse option is specified
+		_, res, err := (&Request{URL: srv.URL + "?close"}).Invoke(context.FromT(t))
+		if err != nil {
+			t.Fatalf("failed to invoke: %s", err)
+		}
+		body, ok := res.(response).Body.([]byte)
+		if !ok {
+			t.Fatalf("expect raw body but got %T", res.(response).Body)
+		}
+		if expect := "event: done\ndata: bye\n\n"; !strings.HasSuffix(string(body), expect) {
+			t.Errorf("expect body ends with %q but got %q", expect, body)
+		}
+	})
+}
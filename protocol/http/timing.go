@@ -0,0 +1,123 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/scenarigo/scenarigo/reporter"
+)
+
+// Timing represents the time spent on each phase of a request.
+// The phases which didn't happen (e.g., DNS lookup of a reused connection) are zero.
+// If the request is redirected, DNS, Connect, and TLSHandshake are the ones of the last request.
+type Timing struct {
+	DNS          time.Duration `yaml:"dns"`
+	Connect      time.Duration `yaml:"connect"`
+	TLSHandshake time.Duration `yaml:"tlsHandshake"`
+	// TTFB is the time from the start of the request until the first byte of the response is received.
+	TTFB time.Duration `yaml:"ttfb"`
+	// Total is the time from the start of the request until the response body is read completely.
+	Total time.Duration `yaml:"total"`
+}
+
+func (t *Timing) report() *reporter.ReportTiming {
+	return &reporter.ReportTiming{
+		DNS:          reporter.TestDuration(t.DNS),
+		Connect:      reporter.TestDuration(t.Connect),
+		TLSHandshake: reporter.TestDuration(t.TLSHandshake),
+		TTFB:         reporter.TestDuration(t.TTFB),
+		Total:        reporter.TestDuration(t.Total),
+	}
+}
+
+// timingRecorder records the time of each phase of a request by net/http/httptrace.
+type timingRecorder struct {
+	m            sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	gotConn      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	end          time.Time
+}
+
+func newTimingRecorder() *timingRecorder {
+	return &timingRecorder{
+		start: time.Now(),
+	}
+}
+
+func (r *timingRecorder) set(t *time.Time) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	*t = time.Now()
+}
+
+// clientTrace returns the hooks to record the time.
+func (r *timingRecorder) clientTrace() *httptrace.ClientTrace {
+	//nolint:exhaustruct
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			// reset the phases of the previous request on redirect
+			r.m.Lock()
+			defer r.m.Unlock()
+			r.dnsStart, r.dnsDone = time.Time{}, time.Time{}
+			r.connectStart, r.connectDone = time.Time{}, time.Time{}
+			r.tlsStart, r.tlsDone = time.Time{}, time.Time{}
+			r.gotConn, r.wroteRequest, r.firstByte = time.Time{}, time.Time{}, time.Time{}
+		},
+		DNSStart: func(httptrace.DNSStartInfo) { r.set(&r.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { r.set(&r.dnsDone) },
+		ConnectStart: func(string, string) {
+			r.m.Lock()
+			defer r.m.Unlock()
+			// dialing multiple addresses concurrently (Happy Eyeballs) calls ConnectStart several times
+			if r.connectStart.IsZero() {
+				r.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				r.set(&r.connectDone)
+			}
+		},
+		TLSHandshakeStart:    func() { r.set(&r.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { r.set(&r.tlsDone) },
+		GotConn:              func(httptrace.GotConnInfo) { r.set(&r.gotConn) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { r.set(&r.wroteRequest) },
+		GotFirstResponseByte: func() { r.set(&r.firstByte) },
+	}
+}
+
+// finish records the end of the request.
+func (r *timingRecorder) finish() {
+	r.set(&r.end)
+}
+
+// timing returns the recorded time.
+func (r *timingRecorder) timing() *Timing {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return &Timing{
+		DNS:          between(r.dnsStart, r.dnsDone),
+		Connect:      between(r.connectStart, r.connectDone),
+		TLSHandshake: between(r.tlsStart, r.tlsDone),
+		TTFB:         between(r.start, r.firstByte),
+		Total:        between(r.start, r.end),
+	}
+}
+
+// between returns the duration from start to end. It returns zero if either of them is not recorded.
+func between(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start)
+}
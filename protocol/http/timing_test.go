@@ -0,0 +1,95 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/reporter"
+)
+
+func TestRequest_Invoke_Timing(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	setClientOption(t, "client:\n  tls:\n    skip: true")
+
+	// use the host name to resolve it
+	url := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+
+	var (
+		root   reporter.Reporter
+		timing *Timing
+		result interface{}
+		err    error
+	)
+	reporter.Run(func(r reporter.Reporter) {
+		root = r
+		r.Run("file.yaml", func(r reporter.Reporter) {
+			r.Run("scenario", func(r reporter.Reporter) {
+				r.Run("step", func(r reporter.Reporter) {
+					var ctx *context.Context
+					ctx, _, err = (&Request{URL: url}).Invoke(context.New(r))
+					if err != nil {
+						return
+					}
+					timing = ctx.Response().(*ResponseExtractor).Timing
+					result, err = ctx.ExecuteTemplate(`{{response.timing.ttfb <= response.timing.total}}`)
+				})
+			})
+		})
+	}, reporter.WithWriter(io.Discard))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if timing == nil {
+		t.Fatal("timing is nil")
+	}
+	if timing.DNS <= 0 {
+		t.Errorf("DNS should be positive: %s", timing.DNS)
+	}
+	if timing.Connect <= 0 {
+		t.Errorf("Connect should be positive: %s", timing.Connect)
+	}
+	if timing.TLSHandshake <= 0 {
+		t.Errorf("TLSHandshake should be positive: %s", timing.TLSHandshake)
+	}
+	if timing.TTFB < timing.DNS+timing.Connect+timing.TLSHandshake {
+		t.Errorf("TTFB %s should include the other phases: %+v", timing.TTFB, timing)
+	}
+	if timing.Total < timing.TTFB {
+		t.Errorf("Total %s should be greater than or equal to TTFB %s", timing.Total, timing.TTFB)
+	}
+	if result != true {
+		t.Errorf("failed to extract the timing from the response: got %v", result)
+	}
+
+	report, err := reporter.GenerateTestReport(root)
+	if err != nil {
+		t.Fatalf("failed to generate report: %s", err)
+	}
+	got := report.Files[0].Scenarios[0].Steps[0].Timing
+	if got == nil {
+		t.Fatal("timing is not reported")
+	}
+	if *got != *timing.report() {
+		t.Errorf("expect %+v but got %+v", timing.report(), got)
+	}
+}
+
+func TestTimingRecorder_NotRecorded(t *testing.T) {
+	r := newTimingRecorder()
+	r.finish()
+	timing := r.timing()
+	if timing.DNS != 0 || timing.Connect != 0 || timing.TLSHandshake != 0 || timing.TTFB != 0 {
+		t.Errorf("unrecorded phases should be zero: %+v", timing)
+	}
+	if timing.Total < 0 {
+		t.Errorf("Total should not be negative: %s", timing.Total)
+	}
+}
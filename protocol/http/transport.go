@@ -0,0 +1,87 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http2"
+
+	"github.com/scenarigo/scenarigo/internal/netutil"
+)
+
+// h2cRoundTripper sends the requests to http:// URLs with HTTP/2 over cleartext (prior knowledge).
+// The other requests are sent by the base transport.
+type h2cRoundTripper struct {
+	h2c  *http2.Transport
+	base http.RoundTripper
+}
+
+func newH2CRoundTripper(base http.RoundTripper, dial netutil.DialFunc) *h2cRoundTripper {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return &h2cRoundTripper{
+		//nolint:exhaustruct
+		h2c: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dial(ctx, network, addr)
+			},
+		},
+		base: base,
+	}
+}
+
+// RoundTrip implements http.RoundTripper interface.
+func (rt *h2cRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "http" {
+		return rt.h2c.RoundTrip(req)
+	}
+	return rt.base.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the transports.
+func (rt *h2cRoundTripper) CloseIdleConnections() {
+	rt.h2c.CloseIdleConnections()
+	closeIdleConnections(rt.base)
+}
+
+// unixSocketTransport returns a copy of base which connects to the Unix domain socket regardless of the host of the URL.
+// The host of the URL is still used as the Host header and the server name of TLS.
+func unixSocketTransport(base *http.Transport, socket string) *http.Transport {
+	t := base.Clone()
+	t.Proxy = nil
+	t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", socket)
+	}
+	return t
+}
+
+// resolverTransport returns a copy of base which connects to the addresses overridden by resolver.
+func resolverTransport(base *http.Transport, resolver netutil.Resolver) *http.Transport {
+	t := base.Clone()
+	t.DialContext = resolver.DialContext(base.DialContext)
+	return t
+}
+
+// hostPort returns the host and port of u to connect. If u doesn't have a port, the default port of the scheme is used.
+func hostPort(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+func closeIdleConnections(rt http.RoundTripper) {
+	if c, ok := rt.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
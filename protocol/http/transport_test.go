@@ -0,0 +1,266 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/internal/netutil"
+)
+
+func TestRequest_Invoke_Socket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "app.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+	srv := httptest.NewUnstartedServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Host", req.Host)
+		w.Header().Set("X-Proto", req.Proto)
+		w.WriteHeader(http.StatusOK)
+	}), &http2.Server{}))
+	srv.Listener = l
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			option      string
+			socket      string
+			expectProto string
+		}{
+			"HTTP/1.1": {
+				socket:      socket,
+				expectProto: "HTTP/1.1",
+			},
+			"template": {
+				socket:      `{{vars.socket}}`,
+				expectProto: "HTTP/1.1",
+			},
+			"h2c": {
+				option:      "client:\n  transport:\n    h2c: true",
+				socket:      socket,
+				expectProto: "HTTP/2.0",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				setClientOption(t, test.option)
+				ctx := context.FromT(t).WithVars(map[string]string{"socket": socket})
+				_, res, err := (&Request{URL: "http://example.com/", Socket: test.socket}).Invoke(ctx)
+				if err != nil {
+					t.Fatalf("failed to invoke: %s", err)
+				}
+				resp, ok := res.(response)
+				if !ok {
+					t.Fatalf("failed to convert from %T to response", res)
+				}
+				if got, expect := resp.StatusCode, http.StatusOK; got != expect {
+					t.Errorf("expect status code %d but got %d", expect, got)
+				}
+				if got, expect := http.Header(resp.Header).Get("X-Host"), "example.com"; got != expect {
+					t.Errorf("expect host %q but got %q", expect, got)
+				}
+				if got, expect := http.Header(resp.Header).Get("X-Proto"), test.expectProto; got != expect {
+					t.Errorf("expect protocol %q but got %q", expect, got)
+				}
+			})
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			req    *Request
+			expect string
+		}{
+			"not found": {
+				req: &Request{
+					URL:    "http://example.com/",
+					Socket: filepath.Join(t.TempDir(), "not-found.sock"),
+				},
+				expect: "failed to send request",
+			},
+			"invalid template": {
+				req: &Request{
+					URL:    "http://example.com/",
+					Socket: "{{",
+				},
+				expect: ".socket: failed to execute template",
+			},
+			"not string": {
+				req: &Request{
+					URL:    "http://example.com/",
+					Socket: "{{1}}",
+				},
+				expect: ".socket: socket must be string but got int64",
+			},
+			"with custom client": {
+				req: &Request{
+					Client: "{{vars.client}}",
+					URL:    "http://example.com/",
+					Socket: socket,
+				},
+				expect: ".socket: socket can't be used with the custom client",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, _, err := test.req.Invoke(context.FromT(t))
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if got := err.Error(); !strings.Contains(got, test.expect) {
+					t.Errorf("%q doesn't contain %q", got, test.expect)
+				}
+			})
+		}
+	})
+}
+
+func TestRequest_Invoke_H2C(t *testing.T) {
+	srv := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Proto", req.Proto)
+		w.WriteHeader(http.StatusOK)
+	}), &http2.Server{}))
+	t.Cleanup(srv.Close)
+
+	tests := map[string]struct {
+		option      string
+		expectProto string
+	}{
+		"disabled": {
+			expectProto: "HTTP/1.1",
+		},
+		"enabled": {
+			option:      "client:\n  transport:\n    h2c: true",
+			expectProto: "HTTP/2.0",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			setClientOption(t, test.option)
+			_, res, err := (&Request{URL: srv.URL}).Invoke(context.FromT(t))
+			if err != nil {
+				t.Fatalf("failed to invoke: %s", err)
+			}
+			resp, ok := res.(response)
+			if !ok {
+				t.Fatalf("failed to convert from %T to response", res)
+			}
+			if got, expect := http.Header(resp.Header).Get("X-Proto"), test.expectProto; got != expect {
+				t.Errorf("expect protocol %q but got %q", expect, got)
+			}
+		})
+	}
+}
+
+func TestRequest_Invoke_Resolve(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Host", req.Host)
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	tlsSrv := httptest.NewTLSServer(handler)
+	t.Cleanup(tlsSrv.Close)
+	setClientOption(t, "client:\n  tls:\n    skip: true")
+
+	tests := map[string]struct {
+		url              string
+		resolver         netutil.Resolver
+		expectHost       string
+		expectResolved   string
+		expectServerName string
+	}{
+		"HTTP": {
+			url: "http://api.example.com/",
+			resolver: netutil.Resolver{
+				"api.example.com:80": srv.Listener.Addr().String(),
+			},
+			expectHost:     "api.example.com",
+			expectResolved: srv.Listener.Addr().String(),
+		},
+		"HTTPS": {
+			url: "https://api.example.com/",
+			resolver: netutil.Resolver{
+				"api.example.com:443": tlsSrv.Listener.Addr().String(),
+			},
+			expectHost:       "api.example.com",
+			expectResolved:   tlsSrv.Listener.Addr().String(),
+			expectServerName: "api.example.com",
+		},
+		"not matched": {
+			url: srv.URL,
+			resolver: netutil.Resolver{
+				"api.example.com:80": "127.0.0.1:1",
+			},
+			expectHost: srv.Listener.Addr().String(),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, res, err := (&Request{URL: test.url}).Invoke(context.FromT(t).WithResolver(test.resolver))
+			if err != nil {
+				t.Fatalf("failed to invoke: %s", err)
+			}
+			resp, ok := res.(response)
+			if !ok {
+				t.Fatalf("failed to convert from %T to response", res)
+			}
+			if got, expect := http.Header(resp.Header).Get("X-Host"), test.expectHost; got != expect {
+				t.Errorf("expect host %q but got %q", expect, got)
+			}
+			if test.expectServerName != "" {
+				if resp.TLS == nil {
+					t.Fatal("no TLS state")
+				}
+				if got, expect := resp.TLS.ServerName, test.expectServerName; got != expect {
+					t.Errorf("expect server name %q but got %q", expect, got)
+				}
+			}
+			dump, ok := ctx.Request().(*RequestExtractor)
+			if !ok {
+				t.Fatalf("failed to convert from %T to *RequestExtractor", ctx.Request())
+			}
+			if got, expect := dump.ResolvedAddress, test.expectResolved; got != expect {
+				t.Errorf("expect resolved address %q but got %q", expect, got)
+			}
+		})
+	}
+}
+
+func TestRequest_Invoke_Resolve_ReuseConnection(t *testing.T) {
+	var conns atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	t.Cleanup(srv.Close)
+	setClientOption(t, "client:\n  timeout: 10s")
+
+	for range 3 {
+		// the resolver is created for each request in the same way as the scenario runner
+		resolver := netutil.Resolver{
+			"api.example.com:80": srv.Listener.Addr().String(),
+		}
+		if _, _, err := (&Request{URL: "http://api.example.com/"}).Invoke(context.FromT(t).WithResolver(resolver)); err != nil {
+			t.Fatalf("failed to invoke: %s", err)
+		}
+	}
+	if got := conns.Load(); got != 1 {
+		t.Errorf("expect the connection to be reused but got %d connections", got)
+	}
+}
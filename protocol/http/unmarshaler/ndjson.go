@@ -0,0 +1,71 @@
+package unmarshaler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+func init() {
+	for _, mediaType := range []string{"application/x-ndjson", "application/jsonl", "application/x-jsonlines"} {
+		if err := Register(&ndjsonUnmarshaler{mediaType: mediaType}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// ndjsonUnmarshaler unmarshals newline-delimited JSON (JSON Lines) into a list of values.
+// Empty lines are ignored.
+type ndjsonUnmarshaler struct {
+	mediaType string
+}
+
+// MediaType implements ResponseUnmarshaler interface.
+func (um *ndjsonUnmarshaler) MediaType() string {
+	return um.mediaType
+}
+
+// Unmarshal implements ResponseUnmarshaler interface.
+func (um *ndjsonUnmarshaler) Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		return errors.New("v must be a pointer")
+	}
+	if rv.IsNil() {
+		return errors.New("v is nil")
+	}
+	rv = rv.Elem()
+	if !rv.CanSet() {
+		return errors.New("v is not settable")
+	}
+
+	values := []interface{}{}
+	r := bufio.NewReader(bytes.NewReader(data))
+	for i := 1; ; i++ {
+		line, err := r.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if len(bytes.TrimSpace(line)) > 0 {
+			d := json.NewDecoder(bytes.NewReader(line))
+			d.UseNumber()
+			var x interface{}
+			if err := d.Decode(&x); err != nil {
+				return fmt.Errorf("line %d: %w", i, err)
+			}
+			if d.More() {
+				return fmt.Errorf("line %d: multiple values in a line", i)
+			}
+			values = append(values, x)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+	rv.Set(reflect.ValueOf(values))
+	return nil
+}
@@ -0,0 +1,88 @@
+package unmarshaler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNDJSONUnmarshaler_MediaType(t *testing.T) {
+	for _, mediaType := range []string{"application/x-ndjson", "application/jsonl", "application/x-jsonlines"} {
+		if got, expect := Get(mediaType).MediaType(), mediaType; got != expect {
+			t.Fatalf("expect %q but got %q", expect, got)
+		}
+	}
+}
+
+func TestNDJSONUnmarshaler_Unmarshal(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			data   string
+			expect interface{}
+		}{
+			"records": {
+				data: `{"id": 1, "name": "foo"}
+{"id": 2, "name": "bar"}
+`,
+				expect: []interface{}{
+					map[string]interface{}{"id": json.Number("1"), "name": "foo"},
+					map[string]interface{}{"id": json.Number("2"), "name": "bar"},
+				},
+			},
+			"no trailing newline and empty lines": {
+				data: "\r\n[1, 2]\r\n\r\n\"text\"\r\nnull",
+				expect: []interface{}{
+					[]interface{}{json.Number("1"), json.Number("2")},
+					"text",
+					nil,
+				},
+			},
+			"empty": {
+				data:   "\n",
+				expect: []interface{}{},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				um := ndjsonUnmarshaler{mediaType: "application/x-ndjson"}
+				var got interface{}
+				if err := um.Unmarshal([]byte(test.data), &got); err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(test.expect, got); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+		}
+	})
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			data   string
+			expect string
+		}{
+			"invalid JSON": {
+				data:   "{\"id\": 1}\n{\"id\":\n",
+				expect: "line 2: unexpected EOF",
+			},
+			"multiple values in a line": {
+				data:   `{"id": 1} {"id": 2}`,
+				expect: "line 1: multiple values in a line",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				um := ndjsonUnmarshaler{mediaType: "application/x-ndjson"}
+				var got interface{}
+				err := um.Unmarshal([]byte(test.data), &got)
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if !strings.Contains(err.Error(), test.expect) {
+					t.Errorf("%q doesn't contain %q", err.Error(), test.expect)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package unmarshaler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	grpcproto "github.com/scenarigo/scenarigo/protocol/grpc/proto"
+)
+
+func init() {
+	for _, mediaType := range []string{"application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf"} {
+		if err := Register(&protobufUnmarshaler{mediaType: mediaType}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// protobufUnmarshaler unmarshals the binary wire format of a message into the JSON representation of the message.
+// If the message type is unknown, the data is unmarshaled as just a sequence of bytes.
+type protobufUnmarshaler struct {
+	mediaType string
+}
+
+// MediaType implements ResponseUnmarshaler interface.
+func (um *protobufUnmarshaler) MediaType() string {
+	return um.mediaType
+}
+
+// Unmarshal implements ResponseUnmarshaler interface.
+func (um *protobufUnmarshaler) Unmarshal(data []byte, v interface{}) error {
+	return um.UnmarshalWithOptions(data, v, &UnmarshalOptions{})
+}
+
+// UnmarshalWithOptions implements ResponseUnmarshalerWithOptions interface.
+func (um *protobufUnmarshaler) UnmarshalWithOptions(data []byte, v interface{}, opts *UnmarshalOptions) error {
+	name := opts.ProtoMessage
+	if name == "" {
+		if _, params, err := mime.ParseMediaType(opts.ContentType); err == nil {
+			name = params["proto"]
+		}
+	}
+	if name == "" {
+		return Default.Unmarshal(data, v)
+	}
+
+	types := opts.ProtoTypes
+	if types == nil {
+		types = grpcproto.NewTypeResolver()
+	}
+	mt, err := types.FindMessageByName(protoreflect.FullName(name))
+	if err != nil {
+		return fmt.Errorf("failed to find message type %q: %w", name, err)
+	}
+	msg := mt.New().Interface()
+	if err := (proto.UnmarshalOptions{Resolver: types}).Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("failed to unmarshal as %s: %w", name, err)
+	}
+	b, err := (protojson.MarshalOptions{Resolver: types}).Marshal(msg)
+	if err != nil {
+		return err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	return d.Decode(v)
+}
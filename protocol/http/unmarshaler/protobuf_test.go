@@ -0,0 +1,102 @@
+package unmarshaler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+
+	testpb "github.com/scenarigo/scenarigo/testdata/gen/pb/test"
+)
+
+func TestProtobuf_UnmarshalWithOptions(t *testing.T) {
+	um := protobufUnmarshaler{mediaType: "application/x-protobuf"}
+	data, err := proto.Marshal(&testpb.EchoResponse{
+		MessageId:   "1",
+		MessageBody: "hello",
+		UserType:    testpb.UserType_CUSTOMER,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			opts   *UnmarshalOptions
+			expect interface{}
+		}{
+			"message option": {
+				opts: &UnmarshalOptions{
+					ContentType:  "application/x-protobuf",
+					ProtoMessage: "scenarigo.testdata.test.EchoResponse",
+				},
+				expect: map[string]interface{}{
+					"messageId":   "1",
+					"messageBody": "hello",
+					"userType":    "CUSTOMER",
+				},
+			},
+			"proto parameter": {
+				opts: &UnmarshalOptions{
+					ContentType: "application/x-protobuf; proto=scenarigo.testdata.test.EchoResponse",
+				},
+				expect: map[string]interface{}{
+					"messageId":   "1",
+					"messageBody": "hello",
+					"userType":    "CUSTOMER",
+				},
+			},
+			"unknown message type": {
+				opts: &UnmarshalOptions{
+					ContentType: "application/x-protobuf",
+				},
+				expect: data,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				var got interface{}
+				if err := um.UnmarshalWithOptions(data, &got, test.opts); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if diff := cmp.Diff(test.expect, got); diff != "" {
+					t.Errorf("differs (-want +got):\n%s", diff)
+				}
+			})
+		}
+	})
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			data   []byte
+			opts   *UnmarshalOptions
+			expect string
+		}{
+			"not found": {
+				data: data,
+				opts: &UnmarshalOptions{
+					ProtoMessage: "scenarigo.testdata.test.Unknown",
+				},
+				expect: `failed to find message type "scenarigo.testdata.test.Unknown"`,
+			},
+			"invalid data": {
+				data: []byte("invalid"),
+				opts: &UnmarshalOptions{
+					ProtoMessage: "scenarigo.testdata.test.EchoResponse",
+				},
+				expect: "failed to unmarshal as scenarigo.testdata.test.EchoResponse",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				var got interface{}
+				err := um.UnmarshalWithOptions(test.data, &got, test.opts)
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if !strings.Contains(err.Error(), test.expect) {
+					t.Errorf("expect error %q but got %q", test.expect, err)
+				}
+			})
+		}
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"sync"
 
 	"github.com/pkg/errors"
+
+	grpcproto "github.com/scenarigo/scenarigo/protocol/grpc/proto"
 )
 
 // Default is the default response unmarshaler.
@@ -50,3 +52,22 @@ type ResponseUnmarshaler interface {
 	MediaType() string
 	Unmarshal(data []byte, v interface{}) error
 }
+
+// UnmarshalOptions represents options to unmarshal the HTTP response body.
+type UnmarshalOptions struct {
+	// ContentType is the value of the Content-Type header of the response.
+	ContentType string
+	// ProtoMessage is the full name of the message type to unmarshal the body as protocol buffers.
+	// If it is empty, the proto parameter of ContentType is used.
+	ProtoMessage string
+	// ProtoTypes resolves the message types of protocol buffers.
+	// If it is nil, the types linked into the binary (e.g., the well-known types) are used.
+	ProtoTypes *grpcproto.TypeResolver
+}
+
+// ResponseUnmarshalerWithOptions is the interface that unmarshals the HTTP response body with options.
+// If the unmarshaler implements this interface, UnmarshalWithOptions is used instead of Unmarshal.
+type ResponseUnmarshalerWithOptions interface {
+	ResponseUnmarshaler
+	UnmarshalWithOptions(data []byte, v interface{}, opts *UnmarshalOptions) error
+}
@@ -0,0 +1,134 @@
+package unmarshaler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-encoding"
+)
+
+func init() {
+	for _, mediaType := range []string{"application/xml", "text/xml", "application/soap+xml"} {
+		if err := Register(&xmlUnmarshaler{mediaType: mediaType}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+const (
+	xmlAttributePrefix = "@"
+	xmlTextKey         = "#text"
+)
+
+// xmlUnmarshaler unmarshals XML into a tree which consists of map[string]interface{}, []interface{}, and string.
+//
+// The root element is converted to a map which has a single key of its name.
+// An element which has neither attributes nor child elements is converted to its text.
+// Otherwise, an element is converted to a map whose keys are attribute names with "@" prefix, child element names, and "#text" for the text.
+// Child elements with the same name are converted to a list.
+// Namespace prefixes and declarations are omitted.
+type xmlUnmarshaler struct {
+	mediaType string
+}
+
+// MediaType implements ResponseUnmarshaler interface.
+func (um *xmlUnmarshaler) MediaType() string {
+	return um.mediaType
+}
+
+// Unmarshal implements ResponseUnmarshaler interface.
+func (um *xmlUnmarshaler) Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		return errors.New("v must be a pointer")
+	}
+	if rv.IsNil() {
+		return errors.New("v is nil")
+	}
+	rv = rv.Elem()
+	if !rv.CanSet() {
+		return errors.New("v is not settable")
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(data))
+	d.CharsetReader = func(label string, r io.Reader) (io.Reader, error) {
+		// the response body may be already decoded by the charset parameter of Content-Type
+		if utf8.Valid(data) {
+			return r, nil
+		}
+		enc := encoding.GetEncoding(label)
+		if enc == nil {
+			return nil, fmt.Errorf("unknown charset %q", label)
+		}
+		return enc.NewDecoder().Reader(r), nil
+	}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return errors.New("no root element")
+			}
+			return err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			elem, err := decodeXMLElement(d, start)
+			if err != nil {
+				return err
+			}
+			rv.Set(reflect.ValueOf(map[string]interface{}{
+				start.Name.Local: elem,
+			}))
+			return nil
+		}
+	}
+}
+
+func decodeXMLElement(d *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	m := map[string]interface{}{}
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+			continue
+		}
+		m[xmlAttributePrefix+attr.Name.Local] = attr.Value
+	}
+	var text strings.Builder
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(d, t)
+			if err != nil {
+				return nil, err
+			}
+			name := t.Name.Local
+			switch v := m[name].(type) {
+			case nil:
+				m[name] = child
+			case []interface{}:
+				m[name] = append(v, child)
+			default:
+				m[name] = []interface{}{v, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(m) == 0 {
+				return s, nil
+			}
+			if s != "" {
+				m[xmlTextKey] = s
+			}
+			return m, nil
+		}
+	}
+}
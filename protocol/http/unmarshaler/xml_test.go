@@ -0,0 +1,127 @@
+package unmarshaler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestXMLUnmarshaler_MediaType(t *testing.T) {
+	for _, mediaType := range []string{"application/xml", "text/xml", "application/soap+xml"} {
+		if got, expect := Get(mediaType).MediaType(), mediaType; got != expect {
+			t.Fatalf("expect %q but got %q", expect, got)
+		}
+	}
+}
+
+func TestXMLUnmarshaler_Unmarshal(t *testing.T) {
+	sjis, err := japanese.ShiftJIS.NewEncoder().String(`<?xml version="1.0" encoding="Shift_JIS"?><message>こんにちは</message>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Run("success", func(t *testing.T) {
+		tests := map[string]struct {
+			data   string
+			expect interface{}
+		}{
+			"text": {
+				data: `<?xml version="1.0" encoding="UTF-8"?><message>hello</message>`,
+				expect: map[string]interface{}{
+					"message": "hello",
+				},
+			},
+			"empty": {
+				data: `<message/>`,
+				expect: map[string]interface{}{
+					"message": "",
+				},
+			},
+			"attributes and children": {
+				data: `
+<user id="1" active="true">
+  <name>alice</name>
+  <role>admin</role>
+  <role>editor</role>
+  <note lang="en">hello</note>
+</user>`,
+				expect: map[string]interface{}{
+					"user": map[string]interface{}{
+						"@id":     "1",
+						"@active": "true",
+						"name":    "alice",
+						"role":    []interface{}{"admin", "editor"},
+						"note": map[string]interface{}{
+							"@lang": "en",
+							"#text": "hello",
+						},
+					},
+				},
+			},
+			"namespaces": {
+				data: `
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns="http://example.com/">
+  <soap:Body>
+    <GetUserResponse><Name>alice</Name></GetUserResponse>
+  </soap:Body>
+</soap:Envelope>`,
+				expect: map[string]interface{}{
+					"Envelope": map[string]interface{}{
+						"Body": map[string]interface{}{
+							"GetUserResponse": map[string]interface{}{
+								"Name": "alice",
+							},
+						},
+					},
+				},
+			},
+			"Shift_JIS": {
+				data: sjis,
+				expect: map[string]interface{}{
+					"message": "こんにちは",
+				},
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				um := xmlUnmarshaler{mediaType: "application/xml"}
+				var got interface{}
+				if err := um.Unmarshal([]byte(test.data), &got); err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(test.expect, got); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+		}
+	})
+	t.Run("failure", func(t *testing.T) {
+		tests := map[string]struct {
+			data   string
+			expect string
+		}{
+			"empty": {
+				data:   "",
+				expect: "no root element",
+			},
+			"invalid": {
+				data:   "<message>hello</msg>",
+				expect: "element <message> closed by </msg>",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				um := xmlUnmarshaler{mediaType: "application/xml"}
+				var got interface{}
+				err := um.Unmarshal([]byte(test.data), &got)
+				if err == nil {
+					t.Fatal("no error")
+				}
+				if !strings.Contains(err.Error(), test.expect) {
+					t.Errorf("%q doesn't contain %q", err.Error(), test.expect)
+				}
+			})
+		}
+	})
+}
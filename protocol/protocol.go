@@ -2,6 +2,8 @@
 package protocol
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
 
@@ -15,6 +17,9 @@ import (
 var (
 	m        sync.Mutex
 	registry = map[string]Protocol{}
+
+	usersMu sync.Mutex
+	users   int
 )
 
 // Register registers the protocol to the registry.
@@ -45,6 +50,47 @@ func Get(name string) Protocol {
 	return p
 }
 
+// Acquire marks the protocols as in use until the returned function is called.
+// The resources cached by the protocols are shared among the concurrent users (e.g., runners),
+// so they are closed by CloseAll when the last user releases them.
+func Acquire() (release func() error) {
+	usersMu.Lock()
+	defer usersMu.Unlock()
+	users++
+	var once sync.Once
+	return func() error {
+		var err error
+		once.Do(func() {
+			usersMu.Lock()
+			defer usersMu.Unlock()
+			users--
+			if users == 0 {
+				err = CloseAll()
+			}
+		})
+		return err
+	}
+}
+
+// CloseAll closes all registered protocols which implement Closer.
+func CloseAll() error {
+	m.Lock()
+	ps := make([]Protocol, 0, len(registry))
+	for _, p := range registry {
+		ps = append(ps, p)
+	}
+	m.Unlock()
+	var errs []error
+	for _, p := range ps {
+		if c, ok := p.(Closer); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close %s protocol: %w", p.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Protocol is the interface that creates Invoker and AssertionBuilder from YAML.
 type Protocol interface {
 	Name() string
@@ -53,6 +99,13 @@ type Protocol interface {
 	UnmarshalExpect([]byte) (AssertionBuilder, error)
 }
 
+// RootDirOptionUnmarshaler is the interface implemented by the protocols which have file paths in the options.
+// UnmarshalOptionWithRootDir is called instead of UnmarshalOption with the root directory of the configuration
+// to resolve the relative paths in the options.
+type RootDirOptionUnmarshaler interface {
+	UnmarshalOptionWithRootDir(b []byte, root string) error
+}
+
 // Invoker is the interface that sends the request and returns response sent from the server.
 type Invoker interface {
 	Invoke(*context.Context) (*context.Context, interface{}, error)
@@ -63,6 +116,12 @@ type AssertionBuilder interface {
 	Build(*context.Context) (assert.Assertion, error)
 }
 
+// Closer is the interface that releases the resources held by the protocol such as cached connections.
+// Close is called when all runners using the protocol have finished.
+type Closer interface {
+	Close() error
+}
+
 // QueryOptionsProvider is the interface that provides custom querying options.
 type QueryOptionsProvider interface {
 	QueryOptions() []query.Option
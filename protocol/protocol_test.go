@@ -0,0 +1,43 @@
+package protocol
+
+import (
+	"testing"
+)
+
+type closerProtocol struct {
+	Protocol
+	closed int
+}
+
+func (p *closerProtocol) Name() string { return "closer" }
+
+func (p *closerProtocol) Close() error {
+	p.closed++
+	return nil
+}
+
+func TestAcquire(t *testing.T) {
+	p := &closerProtocol{}
+	Register(p)
+	t.Cleanup(func() {
+		Unregister(p.Name())
+	})
+
+	release1 := Acquire()
+	release2 := Acquire()
+	if err := release1(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := release1(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got := p.closed; got != 0 {
+		t.Fatalf("closed while in use: closed %d times", got)
+	}
+	if err := release2(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, expect := p.closed, 1; got != expect {
+		t.Fatalf("expect closed %d times but got %d", expect, got)
+	}
+}
@@ -11,9 +11,17 @@ type logRecorder struct {
 	infoIdxs  []int
 	errorIdxs []int
 	skipIdx   *int
+	attrs     []attribute
+	timing    *ReportTiming
 	replacer  LogReplacer
 }
 
+// attribute represents a named value which is added to the test report.
+type attribute struct {
+	name  string
+	value string
+}
+
 func (r *logRecorder) spawn() *logRecorder {
 	r.m.Lock()
 	defer r.m.Unlock()
@@ -29,6 +37,9 @@ func (r *logRecorder) setReplacer(rep LogReplacer) {
 	for i, s := range r.strs {
 		r.strs[i] = r.replacer.ReplaceAll(s)
 	}
+	for i, a := range r.attrs {
+		r.attrs[i].value = r.replacer.ReplaceAll(a.value)
+	}
 }
 
 func (r *logRecorder) log(s string) {
@@ -62,6 +73,42 @@ func (r *logRecorder) skip(s string) {
 	r.skipIdx = &i
 }
 
+func (r *logRecorder) attr(name, value string) {
+	if r.replacer != nil {
+		value = r.replacer.ReplaceAll(value)
+	}
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.attrs = append(r.attrs, attribute{name: name, value: value})
+}
+
+func (r *logRecorder) attributes() []attribute {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if len(r.attrs) == 0 {
+		return nil
+	}
+	attrs := make([]attribute, len(r.attrs))
+	copy(attrs, r.attrs)
+	return attrs
+}
+
+func (r *logRecorder) setTiming(t *ReportTiming) {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.timing = t
+}
+
+func (r *logRecorder) getTiming() *ReportTiming {
+	r.m.Lock()
+	defer r.m.Unlock()
+	if r.timing == nil {
+		return nil
+	}
+	t := *r.timing
+	return &t
+}
+
 func (r *logRecorder) all() []string {
 	r.m.Lock()
 	defer r.m.Unlock()
@@ -122,6 +169,10 @@ func (r *logRecorder) append(s *logRecorder) {
 	defer s.m.Unlock()
 	cc := len(r.strs)
 	r.strs = append(r.strs, s.strs...)
+	r.attrs = append(r.attrs, s.attrs...)
+	if s.timing != nil {
+		r.timing = s.timing
+	}
 	for _, idx := range s.infoIdxs {
 		r.infoIdxs = append(r.infoIdxs, idx+cc)
 	}
@@ -47,7 +47,9 @@ func GenerateTestReport(r Reporter) (*TestReport, error) {
 						Error: logs.errorLogs(),
 						Skip:  logs.skipLog(),
 					},
-					SubSteps: generateSubStepReports(step),
+					Attributes: reportAttributes(logs),
+					Timing:     logs.getTiming(),
+					SubSteps:   generateSubStepReports(step),
 				}
 				scenarioReport.Steps = append(scenarioReport.Steps, stepReport)
 			}
@@ -75,7 +77,24 @@ func generateSubStepReports(r Reporter) []SubStepReport {
 				Error: logs.errorLogs(),
 				Skip:  logs.skipLog(),
 			},
-			SubSteps: generateSubStepReports(child),
+			Attributes: reportAttributes(logs),
+			Timing:     logs.getTiming(),
+			SubSteps:   generateSubStepReports(child),
+		}
+	}
+	return reports
+}
+
+func reportAttributes(logs *logRecorder) []ReportAttribute {
+	attrs := logs.attributes()
+	if len(attrs) == 0 {
+		return nil
+	}
+	reports := make([]ReportAttribute, len(attrs))
+	for i, attr := range attrs {
+		reports[i] = ReportAttribute{
+			Name:  attr.name,
+			Value: attr.value,
 		}
 	}
 	return reports
@@ -157,12 +176,13 @@ type ScenarioReport struct {
 }
 
 type xmlScenarioReport struct {
-	Name      string                   `xml:"name,attr,omitempty"`
-	File      string                   `xml:"file,attr,omitempty"`
-	Duration  TestDuration             `xml:"time,attr"`
-	Failure   *xmlScenarioReportDetail `xml:"failure,omitempty"`
-	Skipped   *xmlScenarioReportDetail `xml:"skipped,omitempty"`
-	SystemOut *xmlCDATA                `xml:"system-out,omitempty"`
+	Name       string                   `xml:"name,attr,omitempty"`
+	File       string                   `xml:"file,attr,omitempty"`
+	Duration   TestDuration             `xml:"time,attr"`
+	Properties *xmlProperties           `xml:"properties,omitempty"`
+	Failure    *xmlScenarioReportDetail `xml:"failure,omitempty"`
+	Skipped    *xmlScenarioReportDetail `xml:"skipped,omitempty"`
+	SystemOut  *xmlCDATA                `xml:"system-out,omitempty"`
 }
 
 type xmlScenarioReportDetail struct {
@@ -174,6 +194,15 @@ type xmlCDATA struct {
 	CDATA string `xml:",cdata"`
 }
 
+type xmlProperties struct {
+	Properties []xmlProperty `xml:"property"`
+}
+
+type xmlProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
 // MarshalXML implements xml.Marshaler interface.
 func (r ScenarioReport) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	xr := &xmlScenarioReport{
@@ -181,6 +210,11 @@ func (r ScenarioReport) MarshalXML(e *xml.Encoder, start xml.StartElement) error
 		File:     r.File,
 		Duration: r.Duration,
 	}
+	if props := xmlStepProperties(r.Steps); len(props) > 0 {
+		xr.Properties = &xmlProperties{
+			Properties: props,
+		}
+	}
 	switch r.Result {
 	case TestResultFailed:
 		for _, step := range r.Steps {
@@ -214,13 +248,34 @@ func (r ScenarioReport) MarshalXML(e *xml.Encoder, start xml.StartElement) error
 	return e.EncodeElement(xr, start)
 }
 
+// xmlStepProperties returns the attributes of the steps and their sub steps as properties.
+func xmlStepProperties(steps []StepReport) []xmlProperty {
+	var props []xmlProperty
+	for _, step := range steps {
+		props = appendXMLProperties(props, step.Attributes, step.SubSteps)
+	}
+	return props
+}
+
+func appendXMLProperties(props []xmlProperty, attrs []ReportAttribute, subSteps []SubStepReport) []xmlProperty {
+	for _, attr := range attrs {
+		props = append(props, xmlProperty(attr))
+	}
+	for _, s := range subSteps {
+		props = appendXMLProperties(props, s.Attributes, s.SubSteps)
+	}
+	return props
+}
+
 // StepReport represents a result report of a test scenario step.
 type StepReport struct {
-	Name     string          `json:"name"`
-	Result   TestResult      `json:"result"`
-	Duration TestDuration    `json:"duration"`
-	Logs     ReportLogs      `json:"logs"`
-	SubSteps []SubStepReport `json:"subSteps,omitempty"`
+	Name       string            `json:"name"`
+	Result     TestResult        `json:"result"`
+	Duration   TestDuration      `json:"duration"`
+	Logs       ReportLogs        `json:"logs"`
+	Attributes []ReportAttribute `json:"attributes,omitempty"`
+	Timing     *ReportTiming     `json:"timing,omitempty"`
+	SubSteps   []SubStepReport   `json:"subSteps,omitempty"`
 }
 
 type ReportLogs struct {
@@ -229,13 +284,31 @@ type ReportLogs struct {
 	Skip  *string  `json:"skip,omitempty"`
 }
 
+// ReportAttribute represents a named value of a step such as the curl command of the request.
+type ReportAttribute struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ReportTiming represents the time spent on each phase of the request sent in a step.
+// The phases which didn't happen (e.g., DNS lookup of a reused connection) are zero.
+type ReportTiming struct {
+	DNS          TestDuration `json:"dns,omitempty"`
+	Connect      TestDuration `json:"connect,omitempty"`
+	TLSHandshake TestDuration `json:"tlsHandshake,omitempty"`
+	TTFB         TestDuration `json:"ttfb"`
+	Total        TestDuration `json:"total"`
+}
+
 // SubStepReport represents a result report of a test scenario sub step.
 type SubStepReport struct {
-	Name     string          `json:"name"`
-	Result   TestResult      `json:"result"`
-	Duration TestDuration    `json:"duration"`
-	Logs     ReportLogs      `json:"logs"`
-	SubSteps []SubStepReport `json:"subSteps,omitempty"`
+	Name       string            `json:"name"`
+	Result     TestResult        `json:"result"`
+	Duration   TestDuration      `json:"duration"`
+	Logs       ReportLogs        `json:"logs"`
+	Attributes []ReportAttribute `json:"attributes,omitempty"`
+	Timing     *ReportTiming     `json:"timing,omitempty"`
+	SubSteps   []SubStepReport   `json:"subSteps,omitempty"`
 }
 
 // TestResult represents a test result.
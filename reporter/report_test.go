@@ -114,6 +114,88 @@ func TestGenerateTestReport(t *testing.T) {
 					},
 				},
 			},
+			"has attributes": {
+				f: func(r Reporter) {
+					r.Run("file1.yaml", func(r Reporter) {
+						r.Run("scenario1", func(r Reporter) {
+							r.Run("step1", func(r Reporter) {
+								SetLogReplacer(r, replacer("SECRET", "*****"))
+								AddAttribute(r, "curl", "curl -H 'Authorization: Bearer SECRET' http://localhost")
+							})
+						})
+					})
+				},
+				expect: &TestReport{
+					Result: TestResultPassed,
+					Files: []ScenarioFileReport{
+						{
+							Name:   "file1.yaml",
+							Result: TestResultPassed,
+							Scenarios: []ScenarioReport{
+								{
+									Name:   "scenario1",
+									File:   "file1.yaml",
+									Result: TestResultPassed,
+									Steps: []StepReport{
+										{
+											Name:   "step1",
+											Result: TestResultPassed,
+											Attributes: []ReportAttribute{
+												{
+													Name:  "curl",
+													Value: "curl -H 'Authorization: Bearer *****' http://localhost",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"has timing": {
+				f: func(r Reporter) {
+					r.Run("file1.yaml", func(r Reporter) {
+						r.Run("scenario1", func(r Reporter) {
+							r.Run("step1", func(r Reporter) {
+								SetTiming(r, &ReportTiming{
+									DNS:   TestDuration(time.Millisecond),
+									TTFB:  TestDuration(3 * time.Millisecond),
+									Total: TestDuration(5 * time.Millisecond),
+								})
+							})
+						})
+					})
+				},
+				expect: &TestReport{
+					Result: TestResultPassed,
+					Files: []ScenarioFileReport{
+						{
+							Name:   "file1.yaml",
+							Result: TestResultPassed,
+							Scenarios: []ScenarioReport{
+								{
+									Name:   "scenario1",
+									File:   "file1.yaml",
+									Result: TestResultPassed,
+									Steps: []StepReport{
+										{
+											Name:   "step1",
+											Result: TestResultPassed,
+											Timing: &ReportTiming{
+												DNS:   TestDuration(time.Millisecond),
+												TTFB:  TestDuration(3 * time.Millisecond),
+												Total: TestDuration(5 * time.Millisecond),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 		}
 		for name, test := range tests {
 			t.Run(name, func(t *testing.T) {
@@ -412,6 +494,12 @@ func TestTestReport_MarshalXML(t *testing.T) {
 												"error",
 											},
 										},
+										Attributes: []ReportAttribute{
+											{
+												Name:  "curl",
+												Value: "curl -X GET 'http://localhost'",
+											},
+										},
 									},
 									{
 										Name:   "skipped step",
@@ -41,6 +41,8 @@ type Reporter interface {
 	runWithRetry(string, func(t Reporter), RetryPolicy) bool
 	setNoFailurePropagation()
 	setLogReplacer(LogReplacer)
+	addAttribute(name, value string)
+	setTiming(t *ReportTiming)
 
 	// for test reports
 	getName() string
@@ -93,6 +95,17 @@ func SetLogReplacer(r Reporter, rep LogReplacer) {
 	r.setLogReplacer(rep)
 }
 
+// AddAttribute adds a named value (e.g., the curl command of the request) to the test report of r.
+// The value is modified by the log replacer as well as the logs.
+func AddAttribute(r Reporter, name, value string) {
+	r.addAttribute(name, value)
+}
+
+// SetTiming sets the time spent on each phase of the request sent in the test to the test report of r.
+func SetTiming(r Reporter, t *ReportTiming) {
+	r.setTiming(t)
+}
+
 // reporter is an implementation of Reporter that
 // records its mutations for later inspection in tests.
 type reporter struct {
@@ -516,6 +529,14 @@ func (r *reporter) setLogReplacer(rep LogReplacer) {
 	r.logs.setReplacer(rep)
 }
 
+func (r *reporter) addAttribute(name, value string) {
+	r.logs.attr(name, value)
+}
+
+func (r *reporter) setTiming(t *ReportTiming) {
+	r.logs.setTiming(t)
+}
+
 func (r *reporter) getName() string {
 	return r.name
 }
@@ -18,7 +18,10 @@ import (
 	"github.com/scenarigo/scenarigo/context"
 	"github.com/scenarigo/scenarigo/errors"
 	"github.com/scenarigo/scenarigo/internal/filepathutil"
+	"github.com/scenarigo/scenarigo/internal/har"
+	"github.com/scenarigo/scenarigo/internal/netutil"
 	"github.com/scenarigo/scenarigo/plugin"
+	"github.com/scenarigo/scenarigo/protocol"
 	"github.com/scenarigo/scenarigo/protocol/grpc"
 	"github.com/scenarigo/scenarigo/protocol/http"
 	"github.com/scenarigo/scenarigo/reporter"
@@ -30,6 +33,9 @@ func init() {
 	grpc.Register()
 }
 
+// defaultArtifactsDir is the directory in the root directory to save the files by the steps.
+const defaultArtifactsDir = "artifacts"
+
 // Runner represents a test runner.
 type Runner struct {
 	vars            map[string]any
@@ -37,12 +43,14 @@ type Runner struct {
 	pluginDir       *string
 	plugins         schema.OrderedMap[string, schema.PluginConfig]
 	protocols       schema.ProtocolOptions
+	resolver        netutil.Resolver
 	scenarioFiles   []string
 	scenarioReaders []io.Reader
 	enabledColor    bool
 	rootDir         string
 	inputConfig     schema.InputConfig
 	reportConfig    schema.ReportConfig
+	artifactsDir    string
 	configNode      ast.Node
 }
 
@@ -93,11 +101,19 @@ func WithConfig(config *schema.Config) func(*Runner) error {
 		}
 		r.plugins = config.Plugins
 		r.protocols = config.Protocols
+		if len(config.Resolve) > 0 {
+			resolver, err := netutil.NewResolver(config.Resolve)
+			if err != nil {
+				return errors.WithPath(err, "resolve")
+			}
+			r.resolver = resolver
+		}
 		if config.Output.Colored != nil {
 			r.enabledColor = *config.Output.Colored
 		}
 		r.inputConfig = config.Input
 		r.reportConfig = config.Output.Report
+		r.artifactsDir = config.Output.Artifacts.Dir
 		r.configNode = config.Node
 		return nil
 	}
@@ -237,6 +253,30 @@ func (r *Runner) Run(ctx *context.Context) {
 		ctx = ctx.WithPluginDir(*r.pluginDir)
 	}
 	ctx = ctx.WithEnabledColor(r.enabledColor)
+	ctx = ctx.WithResolver(r.resolver)
+	artifactsDir := r.artifactsDir
+	if artifactsDir == "" {
+		artifactsDir = defaultArtifactsDir
+	}
+	ctx = ctx.WithArtifactsDir(filepathutil.From(r.rootDir, artifactsDir))
+
+	if r.reportConfig.HAR.Filename != "" {
+		rec := har.NewRecorder()
+		ctx = ctx.WithHARRecorder(rec)
+		defer func() {
+			if err := r.writeHAR(rec); err != nil {
+				ctx.Reporter().Errorf("failed to write HAR file: %s", err)
+			}
+		}()
+	}
+
+	// release the resources cached by protocols across steps (e.g., gRPC connections)
+	release := protocol.Acquire()
+	defer func() {
+		if err := release(); err != nil {
+			ctx.Reporter().Errorf("failed to close protocols: %s", err)
+		}
+	}()
 
 	// open plugins
 	pluginDir := r.rootDir
@@ -269,7 +309,7 @@ func (r *Runner) Run(ctx *context.Context) {
 		return
 	}
 
-	if err := r.protocols.Set(); err != nil {
+	if err := r.protocols.SetWithRootDir(r.rootDir); err != nil {
 		ctx.Reporter().Error(err)
 		teardown(ctx)
 		return
@@ -322,6 +362,19 @@ FILE_LOOP:
 	teardown(ctx)
 }
 
+func (r *Runner) writeHAR(rec *har.Recorder) error {
+	path := filepathutil.From(r.rootDir, r.reportConfig.HAR.Filename)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return rec.Write(f)
+}
+
 // CreateTestReport creates test reports.
 func (r *Runner) CreateTestReport(rptr reporter.Reporter) error {
 	if r.reportConfig.JSON.Filename == "" && r.reportConfig.JUnit.Filename == "" {
@@ -3,6 +3,7 @@ package scenarigo
 import (
 	"bytes"
 	gocontext "context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,6 +18,8 @@ import (
 	"github.com/sergi/go-diff/diffmatchpatch"
 
 	"github.com/scenarigo/scenarigo/context"
+	"github.com/scenarigo/scenarigo/internal/har"
+	"github.com/scenarigo/scenarigo/internal/netutil"
 	"github.com/scenarigo/scenarigo/internal/testutil"
 	"github.com/scenarigo/scenarigo/reporter"
 	"github.com/scenarigo/scenarigo/schema"
@@ -267,6 +270,10 @@ scenarios:
                     - scenarigo/v1.0.0
                   body:
                     message: VAR1 {{secrets.sec1}} VAR2 {{secrets.sec2}} VAR3 {{secrets.sec3}} var_not_found {{secrets.sec4}}
+                curl:
+                  curl -X POST 'http://127.0.0.1:12345/echo' \
+                    -H 'User-Agent: scenarigo/v1.0.0' \
+                    --data-binary $'{"message": "VAR1 {{secrets.sec1}} VAR2 {{secrets.sec2}} VAR3 {{secrets.sec3}} var_not_found {{secrets.sec4}}"}\n'
                 response:
                   status: 200 OK
                   statusCode: 200
@@ -440,6 +447,20 @@ func TestWithConfig(t *testing.T) {
 				},
 			},
 		},
+		"resolve": {
+			config: &schema.Config{
+				Resolve: map[string]string{
+					"api.example.com:443": "127.0.0.1",
+				},
+			},
+			expect: &Runner{
+				scenarioFiles: []string{},
+				resolver: netutil.Resolver{
+					"api.example.com:443": "127.0.0.1:443",
+				},
+				rootDir: wd,
+			},
+		},
 		"output colored": {
 			config: &schema.Config{
 				Output: schema.OutputConfig{
@@ -463,6 +484,9 @@ func TestWithConfig(t *testing.T) {
 							Filename: "report.json",
 						},
 					},
+					Artifacts: schema.ArtifactsConfig{
+						Dir: "artifacts",
+					},
 				},
 			},
 			expect: &Runner{
@@ -475,7 +499,8 @@ func TestWithConfig(t *testing.T) {
 						Filename: "report.json",
 					},
 				},
-				rootDir: wd,
+				artifactsDir: "artifacts",
+				rootDir:      wd,
 			},
 		},
 	}
@@ -501,6 +526,96 @@ func TestWithConfig(t *testing.T) {
 	}
 }
 
+func TestRunner_Run_HAR(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.Copy(w, r.Body)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	t.Setenv("TEST_ADDR", srv.URL)
+
+	dir := t.TempDir()
+	config := parseConfig(t, `
+schemaVersion: config/v1
+secrets:
+  token: '{{"TOKEN"}}'
+output:
+  report:
+    har:
+      filename: reports/report.har
+`)
+	config.Root = dir
+	runner, err := NewRunner(
+		WithConfig(config),
+		WithScenariosFromReader(strings.NewReader(`
+title: /echo
+steps:
+- title: POST /echo
+  protocol: http
+  request:
+    method: POST
+    url: "{{env.TEST_ADDR}}/echo"
+    header:
+      Authorization: 'Bearer {{secrets.token}}'
+    body:
+      message: hello
+  expect:
+    code: 200
+  bind:
+    secrets:
+      message: '{{response.message}}'
+`)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if ok := reporter.Run(func(rptr reporter.Reporter) {
+		runner.Run(context.New(rptr))
+	}, reporter.WithWriter(&b)); !ok {
+		t.Fatalf("scenario failed:\n%s", b.String())
+	}
+
+	f, err := os.Open(filepath.Join(dir, "reports", "report.har"))
+	if err != nil {
+		t.Fatalf("failed to open HAR file: %s", err)
+	}
+	defer f.Close()
+	var got har.HAR
+	if err := json.NewDecoder(f).Decode(&got); err != nil {
+		t.Fatalf("failed to decode HAR file: %s", err)
+	}
+	if got, expect := got.Log.Version, "1.2"; got != expect {
+		t.Errorf("expect version %q but got %q", expect, got)
+	}
+	if got := len(got.Log.Entries); got != 1 {
+		t.Fatalf("expect 1 entry but got %d", got)
+	}
+	entry := got.Log.Entries[0]
+	if got, expect := entry.Request.URL, srv.URL+"/echo"; got != expect {
+		t.Errorf("expect URL %q but got %q", expect, got)
+	}
+	var auth string
+	for _, h := range entry.Request.Headers {
+		if h.Name == "Authorization" {
+			auth = h.Value
+		}
+	}
+	if expect := "Bearer {{secrets.token}}"; auth != expect {
+		t.Errorf("expect Authorization %q but got %q", expect, auth)
+	}
+	if got, expect := entry.Response.Status, http.StatusOK; got != expect {
+		t.Errorf("expect status %d but got %d", expect, got)
+	}
+	// the secrets bound from the response are also masked
+	if got, expect := entry.Response.Content.Text, "{\"message\": \"{{secrets.message}}\"}\n"; got != expect {
+		t.Errorf("expect response body %q but got %q", expect, got)
+	}
+}
+
 func TestWriteTestReport(t *testing.T) {
 	tmp := t.TempDir()
 	tests := map[string]struct {
@@ -18,6 +18,10 @@ func RunScenario(ctx *context.Context, s *schema.Scenario) *context.Context {
 	ctx = ctx.WithScenarioFilepath(s.Filepath())
 	steps := context.NewSteps()
 	ctx = ctx.WithSteps(steps)
+	if ctx.Cookies() == nil {
+		// included scenarios share the cookie jar with the parent scenario
+		ctx = ctx.WithCookies(context.NewCookies())
+	}
 
 	var setups setupFuncList
 	if s.Plugins != nil {
@@ -2,6 +2,8 @@ package scenarigo
 
 import (
 	"bytes"
+	"net/http"
+	"net/url"
 	"os"
 	"testing"
 
@@ -45,6 +47,51 @@ steps:
 	}
 }
 
+func TestRunScenario_Context_Cookies(t *testing.T) {
+	path := createTempScenario(t, `
+steps:
+  - ref: '{{plugins.setCookie}}'
+  - ref: '{{plugins.getCookie}}'
+  `)
+	sceanrios, err := schema.LoadScenarios(path)
+	if err != nil {
+		t.Fatalf("failed to load scenario: %s", err)
+	}
+	if len(sceanrios) != 1 {
+		t.Fatalf("unexpected scenario length: %d", len(sceanrios))
+	}
+
+	var (
+		got string
+		log bytes.Buffer
+	)
+	ok := reporter.Run(func(rptr reporter.Reporter) {
+		ctx := context.New(rptr).WithPlugins(map[string]interface{}{
+			"setCookie": plugin.StepFunc(func(ctx *context.Context, step *schema.Step) *context.Context {
+				ctx.Cookies().SetCookies(&url.URL{Scheme: "http", Host: "example.com"}, []*http.Cookie{
+					{Name: "session_id", Value: "xxx"},
+				})
+				return ctx
+			}),
+			"getCookie": plugin.StepFunc(func(ctx *context.Context, step *schema.Step) *context.Context {
+				v, err := ctx.ExecuteTemplate("{{cookies.session_id}}")
+				if err != nil {
+					ctx.Reporter().Fatal(err)
+				}
+				got, _ = v.(string)
+				return ctx
+			}),
+		})
+		RunScenario(ctx, sceanrios[0])
+	}, reporter.WithWriter(&log))
+	if !ok {
+		t.Fatalf("scenario failed:\n%s", log.String())
+	}
+	if got != "xxx" {
+		t.Errorf("invalid cookie: %q", got)
+	}
+}
+
 func createTempScenario(t *testing.T, scenario string) string {
 	t.Helper()
 	f, err := os.CreateTemp(t.TempDir(), "*.yaml")
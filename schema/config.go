@@ -14,6 +14,7 @@ import (
 
 	"github.com/scenarigo/scenarigo/errors"
 	"github.com/scenarigo/scenarigo/internal/filepathutil"
+	"github.com/scenarigo/scenarigo/internal/netutil"
 	"github.com/scenarigo/scenarigo/protocol"
 )
 
@@ -26,6 +27,7 @@ type Config struct {
 	PluginDirectory string                           `yaml:"pluginDirectory,omitempty"`
 	Plugins         OrderedMap[string, PluginConfig] `yaml:"plugins,omitempty"`
 	Protocols       ProtocolOptions                  `yaml:"protocols,omitempty"`
+	Resolve         map[string]string                `yaml:"resolve,omitempty"`
 	Input           InputConfig                      `yaml:"input,omitempty"`
 	Output          OutputConfig                     `yaml:"output,omitempty"`
 
@@ -69,6 +71,12 @@ func (opts *ProtocolOptions) UnmarshalYAML(b []byte) error {
 
 // Set sets protocol options.
 func (opts ProtocolOptions) Set() error {
+	return opts.SetWithRootDir("")
+}
+
+// SetWithRootDir sets protocol options.
+// The relative paths in the options are resolved against root if the protocol implements protocol.RootDirOptionUnmarshaler.
+func (opts ProtocolOptions) SetWithRootDir(root string) error {
 	for _, o := range (OrderedMap[string, any])(opts).ToSlice() {
 		p := protocol.Get(o.Key)
 		errPath := fmt.Sprintf("protocols.%s", o.Key)
@@ -85,6 +93,12 @@ func (opts ProtocolOptions) Set() error {
 			}
 			b = v
 		}
+		if u, ok := p.(protocol.RootDirOptionUnmarshaler); ok && root != "" {
+			if err := u.UnmarshalOptionWithRootDir(b, root); err != nil {
+				return errors.WrapPath(err, errPath, "failed to unmarshal YAML")
+			}
+			continue
+		}
 		if err := p.UnmarshalOption(b); err != nil {
 			return errors.WrapPath(err, errPath, "failed to unmarshal YAML")
 		}
@@ -111,16 +125,18 @@ type YTTConfig struct {
 
 // OutputConfig represents an output configuration.
 type OutputConfig struct {
-	Verbose bool         `yaml:"verbose,omitempty"`
-	Colored *bool        `yaml:"colored,omitempty"`
-	Summary bool         `yaml:"summary,omitempty"`
-	Report  ReportConfig `yaml:"report,omitempty"`
+	Verbose   bool            `yaml:"verbose,omitempty"`
+	Colored   *bool           `yaml:"colored,omitempty"`
+	Summary   bool            `yaml:"summary,omitempty"`
+	Report    ReportConfig    `yaml:"report,omitempty"`
+	Artifacts ArtifactsConfig `yaml:"artifacts,omitempty"`
 }
 
 // ReportConfig represents a report configuration.
 type ReportConfig struct {
 	JSON  JSONReportConfig  `yaml:"json,omitempty"`
 	JUnit JUnitReportConfig `yaml:"junit,omitempty"`
+	HAR   HARReportConfig   `yaml:"har,omitempty"`
 }
 
 // JSONReportConfig represents a JSON report configuration.
@@ -133,6 +149,17 @@ type JUnitReportConfig struct {
 	Filename string `yaml:"filename,omitempty"`
 }
 
+// HARReportConfig represents a HAR (HTTP Archive) report configuration.
+type HARReportConfig struct {
+	Filename string `yaml:"filename,omitempty"`
+}
+
+// ArtifactsConfig represents a configuration of the files saved by the steps.
+type ArtifactsConfig struct {
+	// Dir is the directory to save the files. The default is "artifacts" in the root directory.
+	Dir string `yaml:"dir,omitempty"`
+}
+
 // LoadConfig loads a configuration from path.
 func LoadConfig(path string) (*Config, error) {
 	r, err := os.OpenFile(path, os.O_RDONLY, 0o400)
@@ -220,6 +247,12 @@ func validate(c *Config) error {
 			}
 		}
 	}
+	if _, err := netutil.NewResolver(c.Resolve); err != nil {
+		errs = append(errs, errors.WithNodeAndColored(
+			errors.WithPath(err, "resolve"),
+			c.Node, !color.NoColor,
+		))
+	}
 	return errors.Errors(errs...)
 }
 
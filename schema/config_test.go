@@ -101,6 +101,9 @@ func TestLoadConfig(t *testing.T) {
 							},
 						},
 					},
+					Resolve: map[string]string{
+						"api.example.com:443": "127.0.0.1:8443",
+					},
 					Input: InputConfig{
 						Excludes: []Regexp{
 							{
@@ -127,6 +130,12 @@ func TestLoadConfig(t *testing.T) {
 							JUnit: JUnitReportConfig{
 								Filename: "junit.xml",
 							},
+							HAR: HARReportConfig{
+								Filename: "report.har",
+							},
+						},
+						Artifacts: ArtifactsConfig{
+							Dir: "artifacts",
 						},
 					},
 					Root:     filepath.Join(wd, "testdata/config"),
@@ -206,6 +215,15 @@ func TestLoadConfig(t *testing.T) {
        3 |   foo.so:
     >  4 |     src: invalid
                     ^
+`,
+			},
+			"invalid resolve": {
+				path: "testdata/config/invalid-resolve.yaml",
+				expect: `1 error occurred: invalid host "api.example.com": must be host:port
+       1 | schemaVersion: config/v1
+       2 | resolve:
+    >  3 |   api.example.com: 127.0.0.1
+                            ^
 `,
 			},
 		}
@@ -227,6 +245,7 @@ func TestProtocolOptions_Set(t *testing.T) {
 	tests := map[string]struct {
 		protocol    protocol.Protocol
 		opts        *ProtocolOptions
+		root        string
 		expect      protocol.Protocol
 		expectError string
 	}{
@@ -250,6 +269,56 @@ func TestProtocolOptions_Set(t *testing.T) {
 				opts: true,
 			},
 		},
+		"without root directory": {
+			protocol: &testRootDirProtocol{
+				testProtocol: testProtocol{
+					name: "test",
+				},
+			},
+			opts: &ProtocolOptions{
+				idx: map[string]int{
+					"test": 0,
+				},
+				items: []OrderedMapItem[string, any]{
+					{
+						Key:   "test",
+						Value: RawMessage([]byte("true")),
+					},
+				},
+			},
+			expect: &testRootDirProtocol{
+				testProtocol: testProtocol{
+					name: "test",
+					opts: true,
+				},
+			},
+		},
+		"with root directory": {
+			protocol: &testRootDirProtocol{
+				testProtocol: testProtocol{
+					name: "test",
+				},
+			},
+			opts: &ProtocolOptions{
+				idx: map[string]int{
+					"test": 0,
+				},
+				items: []OrderedMapItem[string, any]{
+					{
+						Key:   "test",
+						Value: RawMessage([]byte("true")),
+					},
+				},
+			},
+			root: "/path/to/root",
+			expect: &testRootDirProtocol{
+				testProtocol: testProtocol{
+					name: "test",
+					opts: true,
+				},
+				root: "/path/to/root",
+			},
+		},
 		"unknown protocol": {
 			opts: &ProtocolOptions{
 				idx: map[string]int{
@@ -291,7 +360,12 @@ func TestProtocolOptions_Set(t *testing.T) {
 				})
 			}
 
-			err := test.opts.Set()
+			var err error
+			if test.root == "" {
+				err = test.opts.Set()
+			} else {
+				err = test.opts.SetWithRootDir(test.root)
+			}
 			if err != nil {
 				if test.expectError == "" {
 					t.Fatalf("unexpected error: %s", err)
@@ -304,9 +378,19 @@ func TestProtocolOptions_Set(t *testing.T) {
 			if test.expectError != "" {
 				t.Fatal("no error")
 			}
-			if diff := cmp.Diff(test.expect, test.protocol, cmp.AllowUnexported(testProtocol{})); diff != "" {
+			if diff := cmp.Diff(test.expect, test.protocol, cmp.AllowUnexported(testProtocol{}, testRootDirProtocol{})); diff != "" {
 				t.Errorf("differs (-want +got):\n%s", diff)
 			}
 		})
 	}
 }
+
+type testRootDirProtocol struct {
+	testProtocol
+	root string
+}
+
+func (p *testRootDirProtocol) UnmarshalOptionWithRootDir(b []byte, root string) error {
+	p.root = root
+	return p.UnmarshalOption(b)
+}
@@ -16,7 +16,6 @@ import (
 	"github.com/scenarigo/scenarigo/assert"
 	"github.com/scenarigo/scenarigo/context"
 	"github.com/scenarigo/scenarigo/protocol"
-	"github.com/scenarigo/scenarigo/protocol/http"
 	"github.com/sergi/go-diff/diffmatchpatch"
 )
 
@@ -641,8 +640,54 @@ func TestMarshalYAML(t *testing.T) {
 	}
 }
 
+// httpProtocol decodes the request and the expect into structs in the same way as the http protocol.
+// The http protocol can't be used in this package since it depends on this package.
+type httpProtocol struct {
+	testProtocol
+}
+
+func (p *httpProtocol) UnmarshalRequest(b []byte) (protocol.Invoker, error) {
+	var r httpRequest
+	if err := yaml.UnmarshalWithOptions(b, &r, yaml.Strict()); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (p *httpProtocol) UnmarshalExpect(b []byte) (protocol.AssertionBuilder, error) {
+	var e httpExpect
+	if err := yaml.UnmarshalWithOptions(b, &e, yaml.UseOrderedMap(), yaml.Strict()); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+type httpRequest struct {
+	Method string      `yaml:"method,omitempty"`
+	URL    string      `yaml:"url,omitempty"`
+	Body   interface{} `yaml:"body,omitempty"`
+}
+
+func (r *httpRequest) Invoke(ctx *context.Context) (*context.Context, interface{}, error) {
+	return ctx, nil, nil
+}
+
+type httpExpect struct {
+	Code string `yaml:"code,omitempty"`
+}
+
+func (e *httpExpect) Build(ctx *context.Context) (assert.Assertion, error) {
+	return assert.Build(ctx.RequestContext(), e)
+}
+
 func Test_Issue304(t *testing.T) {
-	http.Register()
+	p := &httpProtocol{
+		testProtocol: testProtocol{
+			name: "http",
+		},
+	}
+	protocol.Register(p)
+	defer protocol.Unregister(p.Name())
 	yml := `title: get scenarigo repository
 steps:
 - title: expect 404
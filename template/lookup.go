@@ -58,17 +58,24 @@ func buildQuery(q *query.Query, node ast.Node) (*query.Query, error) {
 		return q.Key(n.Sel.Name), nil
 	case *ast.IndexExpr:
 		i, ok := n.Index.(*ast.BasicLit)
-		if !ok || i.Kind != token.INT {
+		if !ok {
+			return nil, errors.Errorf(`expected int or string but "%T"`, n.Index)
+		}
+		q, err = buildQuery(q, n.X)
+		if err != nil {
+			return nil, err
+		}
+		// a string index is a key which can't be written as an identifier (e.g., {{a["@id"]}})
+		if i.Kind == token.STRING {
+			return q.Key(i.Value), nil
+		}
+		if i.Kind != token.INT {
 			return nil, errors.Errorf(`expected int but "%s"`, i.Kind.String())
 		}
 		idx, err := strconv.Atoi(i.Value)
 		if err != nil {
 			return nil, errors.Errorf(`expected int but "%s"`, i.Value)
 		}
-		q, err = buildQuery(q, n.X)
-		if err != nil {
-			return nil, err
-		}
 		return q.Index(idx), nil
 	}
 	return nil, errors.Errorf(`unknown node "%T"`, node)
@@ -92,6 +92,15 @@ func TestTemplate_Execute(t *testing.T) {
 			},
 			expect: "ok",
 		},
+		"query from data by string index": {
+			str: `{{a["@id"]}}`,
+			data: map[string]map[string]string{
+				"a": {
+					"@id": "ok",
+				},
+			},
+			expect: "ok",
+		},
 
 		"function call": {
 			str: `{{f("ok")}}`,
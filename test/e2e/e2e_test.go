@@ -182,6 +182,7 @@ func runMockServer(t *testing.T, filename string) func(*testing.T) {
 	if err := yaml.NewDecoder(f, yaml.Strict()).Decode(&config); err != nil {
 		t.Fatal(err)
 	}
+	config.BaseDir = filepath.Dir(filename)
 	var b bytes.Buffer
 	l := logger.NewLogger(log.New(&b, "", log.LstdFlags), logger.LogLevelAll)
 	srv, err := mock.NewServer(&config, l)
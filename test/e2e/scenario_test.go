@@ -13,6 +13,8 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -30,7 +32,9 @@ import (
 	"github.com/scenarigo/scenarigo/context"
 	"github.com/scenarigo/scenarigo/protocol"
 	"github.com/scenarigo/scenarigo/reporter"
+	"github.com/scenarigo/scenarigo/schema"
 	"github.com/scenarigo/scenarigo/testdata/gen/pb/test"
+	"github.com/scenarigo/scenarigo/version"
 )
 
 type testProtocol struct {
@@ -427,6 +431,12 @@ func TestRunner_GenerateTestReport(t *testing.T) {
 			cmp.FilterValues(func(_, _ reporter.ReportLogs) bool {
 				return true
 			}, cmp.Ignore()),
+			cmp.FilterValues(func(_, _ []reporter.ReportAttribute) bool {
+				return true
+			}, cmp.Ignore()),
+			cmp.FilterValues(func(_, _ *reporter.ReportTiming) bool {
+				return true
+			}, cmp.Ignore()),
 		); diff != "" {
 			t.Errorf("result mismatch (-want +got):\n%s", diff)
 		}
@@ -436,6 +446,81 @@ func TestRunner_GenerateTestReport(t *testing.T) {
 	}
 }
 
+func TestRunner_CurlAttribute(t *testing.T) {
+	teardown := startHTTPServer(t)
+	defer teardown()
+
+	r, err := scenarigo.NewRunner(scenarigo.WithScenarios("testdata/scenarios/curl.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var b bytes.Buffer
+	ok := reporter.Run(func(rptr reporter.Reporter) {
+		r.Run(context.New(rptr))
+
+		report, err := reporter.GenerateTestReport(rptr)
+		if err != nil {
+			t.Fatalf("failed to generate report: %s", err)
+		}
+		expect := []reporter.ReportAttribute{
+			{
+				Name: "curl",
+				Value: fmt.Sprintf(`curl -X POST '%s/echo' \
+  -H 'Authorization: Bearer XXXXX' \
+  -H 'User-Agent: scenarigo/%s' \
+  --data-binary $'{"message": "hello"}\n'`, os.Getenv("TEST_ADDR"), version.String()),
+			},
+		}
+		if diff := cmp.Diff(expect, report.Files[0].Scenarios[0].Steps[0].Attributes); diff != "" {
+			t.Errorf("attributes mismatch (-want +got):\n%s", diff)
+		}
+	}, reporter.WithWriter(&b))
+	if !ok {
+		t.Fatalf("scenario failed:\n%s", b.String())
+	}
+}
+
+func TestRunner_SaveArtifact(t *testing.T) {
+	teardown := startHTTPServer(t)
+	defer teardown()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "echo.json")
+	r, err := scenarigo.NewRunner(scenarigo.WithConfig(&schema.Config{
+		SchemaVersion: "config/v1",
+		Vars: map[string]any{
+			"expect": path,
+		},
+		Scenarios: []string{
+			"testdata/scenarios/save.yaml",
+		},
+		Output: schema.OutputConfig{
+			Artifacts: schema.ArtifactsConfig{
+				Dir: dir,
+			},
+		},
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var b bytes.Buffer
+	ok := reporter.Run(func(rptr reporter.Reporter) {
+		r.Run(context.New(rptr))
+	}, reporter.WithWriter(&b))
+	if !ok {
+		t.Fatalf("scenario failed:\n%s", b.String())
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %s", err)
+	}
+	if expect := `{"id":"","message":"hello"}`; string(got) != expect {
+		t.Errorf("expect %q but got %q", expect, got)
+	}
+}
+
 func startHTTPServer(t *testing.T) func() {
 	t.Helper()
 	token := "XXXXX"
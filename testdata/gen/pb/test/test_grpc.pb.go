@@ -105,3 +105,258 @@ var Test_ServiceDesc = grpc.ServiceDesc{
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "test/test.proto",
 }
+
+const (
+	StreamTest_ServerStreamingEcho_FullMethodName = "/scenarigo.testdata.test.StreamTest/ServerStreamingEcho"
+	StreamTest_ClientStreamingEcho_FullMethodName = "/scenarigo.testdata.test.StreamTest/ClientStreamingEcho"
+	StreamTest_BidiStreamingEcho_FullMethodName   = "/scenarigo.testdata.test.StreamTest/BidiStreamingEcho"
+)
+
+// StreamTestClient is the client API for StreamTest service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StreamTestClient interface {
+	ServerStreamingEcho(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (StreamTest_ServerStreamingEchoClient, error)
+	ClientStreamingEcho(ctx context.Context, opts ...grpc.CallOption) (StreamTest_ClientStreamingEchoClient, error)
+	BidiStreamingEcho(ctx context.Context, opts ...grpc.CallOption) (StreamTest_BidiStreamingEchoClient, error)
+}
+
+type streamTestClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStreamTestClient(cc grpc.ClientConnInterface) StreamTestClient {
+	return &streamTestClient{cc}
+}
+
+func (c *streamTestClient) ServerStreamingEcho(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (StreamTest_ServerStreamingEchoClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StreamTest_ServiceDesc.Streams[0], StreamTest_ServerStreamingEcho_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamTestServerStreamingEchoClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type StreamTest_ServerStreamingEchoClient interface {
+	Recv() (*EchoResponse, error)
+	grpc.ClientStream
+}
+
+type streamTestServerStreamingEchoClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamTestServerStreamingEchoClient) Recv() (*EchoResponse, error) {
+	m := new(EchoResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamTestClient) ClientStreamingEcho(ctx context.Context, opts ...grpc.CallOption) (StreamTest_ClientStreamingEchoClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StreamTest_ServiceDesc.Streams[1], StreamTest_ClientStreamingEcho_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamTestClientStreamingEchoClient{stream}
+	return x, nil
+}
+
+type StreamTest_ClientStreamingEchoClient interface {
+	Send(*EchoRequest) error
+	CloseAndRecv() (*EchoResponse, error)
+	grpc.ClientStream
+}
+
+type streamTestClientStreamingEchoClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamTestClientStreamingEchoClient) Send(m *EchoRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamTestClientStreamingEchoClient) CloseAndRecv() (*EchoResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(EchoResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamTestClient) BidiStreamingEcho(ctx context.Context, opts ...grpc.CallOption) (StreamTest_BidiStreamingEchoClient, error) {
+	stream, err := c.cc.NewStream(ctx, &StreamTest_ServiceDesc.Streams[2], StreamTest_BidiStreamingEcho_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamTestBidiStreamingEchoClient{stream}
+	return x, nil
+}
+
+type StreamTest_BidiStreamingEchoClient interface {
+	Send(*EchoRequest) error
+	Recv() (*EchoResponse, error)
+	grpc.ClientStream
+}
+
+type streamTestBidiStreamingEchoClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamTestBidiStreamingEchoClient) Send(m *EchoRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamTestBidiStreamingEchoClient) Recv() (*EchoResponse, error) {
+	m := new(EchoResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StreamTestServer is the server API for StreamTest service.
+// All implementations should embed UnimplementedStreamTestServer
+// for forward compatibility
+type StreamTestServer interface {
+	ServerStreamingEcho(*EchoRequest, StreamTest_ServerStreamingEchoServer) error
+	ClientStreamingEcho(StreamTest_ClientStreamingEchoServer) error
+	BidiStreamingEcho(StreamTest_BidiStreamingEchoServer) error
+}
+
+// UnimplementedStreamTestServer should be embedded to have forward compatible implementations.
+type UnimplementedStreamTestServer struct {
+}
+
+func (UnimplementedStreamTestServer) ServerStreamingEcho(*EchoRequest, StreamTest_ServerStreamingEchoServer) error {
+	return status.Errorf(codes.Unimplemented, "method ServerStreamingEcho not implemented")
+}
+func (UnimplementedStreamTestServer) ClientStreamingEcho(StreamTest_ClientStreamingEchoServer) error {
+	return status.Errorf(codes.Unimplemented, "method ClientStreamingEcho not implemented")
+}
+func (UnimplementedStreamTestServer) BidiStreamingEcho(StreamTest_BidiStreamingEchoServer) error {
+	return status.Errorf(codes.Unimplemented, "method BidiStreamingEcho not implemented")
+}
+
+// UnsafeStreamTestServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StreamTestServer will
+// result in compilation errors.
+type UnsafeStreamTestServer interface {
+	mustEmbedUnimplementedStreamTestServer()
+}
+
+func RegisterStreamTestServer(s grpc.ServiceRegistrar, srv StreamTestServer) {
+	s.RegisterService(&StreamTest_ServiceDesc, srv)
+}
+
+func _StreamTest_ServerStreamingEcho_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EchoRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StreamTestServer).ServerStreamingEcho(m, &streamTestServerStreamingEchoServer{stream})
+}
+
+type StreamTest_ServerStreamingEchoServer interface {
+	Send(*EchoResponse) error
+	grpc.ServerStream
+}
+
+type streamTestServerStreamingEchoServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamTestServerStreamingEchoServer) Send(m *EchoResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _StreamTest_ClientStreamingEcho_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamTestServer).ClientStreamingEcho(&streamTestClientStreamingEchoServer{stream})
+}
+
+type StreamTest_ClientStreamingEchoServer interface {
+	SendAndClose(*EchoResponse) error
+	Recv() (*EchoRequest, error)
+	grpc.ServerStream
+}
+
+type streamTestClientStreamingEchoServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamTestClientStreamingEchoServer) SendAndClose(m *EchoResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamTestClientStreamingEchoServer) Recv() (*EchoRequest, error) {
+	m := new(EchoRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _StreamTest_BidiStreamingEcho_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamTestServer).BidiStreamingEcho(&streamTestBidiStreamingEchoServer{stream})
+}
+
+type StreamTest_BidiStreamingEchoServer interface {
+	Send(*EchoResponse) error
+	Recv() (*EchoRequest, error)
+	grpc.ServerStream
+}
+
+type streamTestBidiStreamingEchoServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamTestBidiStreamingEchoServer) Send(m *EchoResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamTestBidiStreamingEchoServer) Recv() (*EchoRequest, error) {
+	m := new(EchoRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StreamTest_ServiceDesc is the grpc.ServiceDesc for StreamTest service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StreamTest_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "scenarigo.testdata.test.StreamTest",
+	HandlerType: (*StreamTestServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ServerStreamingEcho",
+			Handler:       _StreamTest_ServerStreamingEcho_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ClientStreamingEcho",
+			Handler:       _StreamTest_ClientStreamingEcho_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "BidiStreamingEcho",
+			Handler:       _StreamTest_BidiStreamingEcho_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "test/test.proto",
+}
@@ -9,6 +9,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	grpc "google.golang.org/grpc"
+	metadata "google.golang.org/grpc/metadata"
 )
 
 // MockTestClient is a mock of TestClient interface.
@@ -126,3 +127,970 @@ func (mr *MockUnsafeTestServerMockRecorder) mustEmbedUnimplementedTestServer() *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedTestServer", reflect.TypeOf((*MockUnsafeTestServer)(nil).mustEmbedUnimplementedTestServer))
 }
+
+// MockStreamTestClient is a mock of StreamTestClient interface.
+type MockStreamTestClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamTestClientMockRecorder
+}
+
+// MockStreamTestClientMockRecorder is the mock recorder for MockStreamTestClient.
+type MockStreamTestClientMockRecorder struct {
+	mock *MockStreamTestClient
+}
+
+// NewMockStreamTestClient creates a new mock instance.
+func NewMockStreamTestClient(ctrl *gomock.Controller) *MockStreamTestClient {
+	mock := &MockStreamTestClient{ctrl: ctrl}
+	mock.recorder = &MockStreamTestClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamTestClient) EXPECT() *MockStreamTestClientMockRecorder {
+	return m.recorder
+}
+
+// BidiStreamingEcho mocks base method.
+func (m *MockStreamTestClient) BidiStreamingEcho(ctx context.Context, opts ...grpc.CallOption) (StreamTest_BidiStreamingEchoClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BidiStreamingEcho", varargs...)
+	ret0, _ := ret[0].(StreamTest_BidiStreamingEchoClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BidiStreamingEcho indicates an expected call of BidiStreamingEcho.
+func (mr *MockStreamTestClientMockRecorder) BidiStreamingEcho(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BidiStreamingEcho", reflect.TypeOf((*MockStreamTestClient)(nil).BidiStreamingEcho), varargs...)
+}
+
+// ClientStreamingEcho mocks base method.
+func (m *MockStreamTestClient) ClientStreamingEcho(ctx context.Context, opts ...grpc.CallOption) (StreamTest_ClientStreamingEchoClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ClientStreamingEcho", varargs...)
+	ret0, _ := ret[0].(StreamTest_ClientStreamingEchoClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClientStreamingEcho indicates an expected call of ClientStreamingEcho.
+func (mr *MockStreamTestClientMockRecorder) ClientStreamingEcho(ctx interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientStreamingEcho", reflect.TypeOf((*MockStreamTestClient)(nil).ClientStreamingEcho), varargs...)
+}
+
+// ServerStreamingEcho mocks base method.
+func (m *MockStreamTestClient) ServerStreamingEcho(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (StreamTest_ServerStreamingEchoClient, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ServerStreamingEcho", varargs...)
+	ret0, _ := ret[0].(StreamTest_ServerStreamingEchoClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServerStreamingEcho indicates an expected call of ServerStreamingEcho.
+func (mr *MockStreamTestClientMockRecorder) ServerStreamingEcho(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerStreamingEcho", reflect.TypeOf((*MockStreamTestClient)(nil).ServerStreamingEcho), varargs...)
+}
+
+// MockStreamTest_ServerStreamingEchoClient is a mock of StreamTest_ServerStreamingEchoClient interface.
+type MockStreamTest_ServerStreamingEchoClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamTest_ServerStreamingEchoClientMockRecorder
+}
+
+// MockStreamTest_ServerStreamingEchoClientMockRecorder is the mock recorder for MockStreamTest_ServerStreamingEchoClient.
+type MockStreamTest_ServerStreamingEchoClientMockRecorder struct {
+	mock *MockStreamTest_ServerStreamingEchoClient
+}
+
+// NewMockStreamTest_ServerStreamingEchoClient creates a new mock instance.
+func NewMockStreamTest_ServerStreamingEchoClient(ctrl *gomock.Controller) *MockStreamTest_ServerStreamingEchoClient {
+	mock := &MockStreamTest_ServerStreamingEchoClient{ctrl: ctrl}
+	mock.recorder = &MockStreamTest_ServerStreamingEchoClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamTest_ServerStreamingEchoClient) EXPECT() *MockStreamTest_ServerStreamingEchoClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockStreamTest_ServerStreamingEchoClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockStreamTest_ServerStreamingEchoClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockStreamTest_ServerStreamingEchoClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamTest_ServerStreamingEchoClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockStreamTest_ServerStreamingEchoClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockStreamTest_ServerStreamingEchoClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockStreamTest_ServerStreamingEchoClient) Recv() (*EchoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*EchoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockStreamTest_ServerStreamingEchoClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockStreamTest_ServerStreamingEchoClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockStreamTest_ServerStreamingEchoClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoClient)(nil).RecvMsg), m)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockStreamTest_ServerStreamingEchoClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockStreamTest_ServerStreamingEchoClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockStreamTest_ServerStreamingEchoClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockStreamTest_ServerStreamingEchoClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoClient)(nil).Trailer))
+}
+
+// MockStreamTest_ClientStreamingEchoClient is a mock of StreamTest_ClientStreamingEchoClient interface.
+type MockStreamTest_ClientStreamingEchoClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamTest_ClientStreamingEchoClientMockRecorder
+}
+
+// MockStreamTest_ClientStreamingEchoClientMockRecorder is the mock recorder for MockStreamTest_ClientStreamingEchoClient.
+type MockStreamTest_ClientStreamingEchoClientMockRecorder struct {
+	mock *MockStreamTest_ClientStreamingEchoClient
+}
+
+// NewMockStreamTest_ClientStreamingEchoClient creates a new mock instance.
+func NewMockStreamTest_ClientStreamingEchoClient(ctrl *gomock.Controller) *MockStreamTest_ClientStreamingEchoClient {
+	mock := &MockStreamTest_ClientStreamingEchoClient{ctrl: ctrl}
+	mock.recorder = &MockStreamTest_ClientStreamingEchoClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamTest_ClientStreamingEchoClient) EXPECT() *MockStreamTest_ClientStreamingEchoClientMockRecorder {
+	return m.recorder
+}
+
+// CloseAndRecv mocks base method.
+func (m *MockStreamTest_ClientStreamingEchoClient) CloseAndRecv() (*EchoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAndRecv")
+	ret0, _ := ret[0].(*EchoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseAndRecv indicates an expected call of CloseAndRecv.
+func (mr *MockStreamTest_ClientStreamingEchoClientMockRecorder) CloseAndRecv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAndRecv", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoClient)(nil).CloseAndRecv))
+}
+
+// CloseSend mocks base method.
+func (m *MockStreamTest_ClientStreamingEchoClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockStreamTest_ClientStreamingEchoClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockStreamTest_ClientStreamingEchoClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamTest_ClientStreamingEchoClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockStreamTest_ClientStreamingEchoClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockStreamTest_ClientStreamingEchoClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoClient)(nil).Header))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockStreamTest_ClientStreamingEchoClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockStreamTest_ClientStreamingEchoClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoClient)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockStreamTest_ClientStreamingEchoClient) Send(arg0 *EchoRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockStreamTest_ClientStreamingEchoClientMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockStreamTest_ClientStreamingEchoClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockStreamTest_ClientStreamingEchoClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockStreamTest_ClientStreamingEchoClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockStreamTest_ClientStreamingEchoClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoClient)(nil).Trailer))
+}
+
+// MockStreamTest_BidiStreamingEchoClient is a mock of StreamTest_BidiStreamingEchoClient interface.
+type MockStreamTest_BidiStreamingEchoClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamTest_BidiStreamingEchoClientMockRecorder
+}
+
+// MockStreamTest_BidiStreamingEchoClientMockRecorder is the mock recorder for MockStreamTest_BidiStreamingEchoClient.
+type MockStreamTest_BidiStreamingEchoClientMockRecorder struct {
+	mock *MockStreamTest_BidiStreamingEchoClient
+}
+
+// NewMockStreamTest_BidiStreamingEchoClient creates a new mock instance.
+func NewMockStreamTest_BidiStreamingEchoClient(ctrl *gomock.Controller) *MockStreamTest_BidiStreamingEchoClient {
+	mock := &MockStreamTest_BidiStreamingEchoClient{ctrl: ctrl}
+	mock.recorder = &MockStreamTest_BidiStreamingEchoClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamTest_BidiStreamingEchoClient) EXPECT() *MockStreamTest_BidiStreamingEchoClientMockRecorder {
+	return m.recorder
+}
+
+// CloseSend mocks base method.
+func (m *MockStreamTest_BidiStreamingEchoClient) CloseSend() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSend")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseSend indicates an expected call of CloseSend.
+func (mr *MockStreamTest_BidiStreamingEchoClientMockRecorder) CloseSend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSend", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoClient)(nil).CloseSend))
+}
+
+// Context mocks base method.
+func (m *MockStreamTest_BidiStreamingEchoClient) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamTest_BidiStreamingEchoClientMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoClient)(nil).Context))
+}
+
+// Header mocks base method.
+func (m *MockStreamTest_BidiStreamingEchoClient) Header() (metadata.MD, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Header")
+	ret0, _ := ret[0].(metadata.MD)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Header indicates an expected call of Header.
+func (mr *MockStreamTest_BidiStreamingEchoClientMockRecorder) Header() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Header", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoClient)(nil).Header))
+}
+
+// Recv mocks base method.
+func (m *MockStreamTest_BidiStreamingEchoClient) Recv() (*EchoResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*EchoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockStreamTest_BidiStreamingEchoClientMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoClient)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockStreamTest_BidiStreamingEchoClient) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockStreamTest_BidiStreamingEchoClientMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoClient)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockStreamTest_BidiStreamingEchoClient) Send(arg0 *EchoRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockStreamTest_BidiStreamingEchoClientMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoClient)(nil).Send), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockStreamTest_BidiStreamingEchoClient) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockStreamTest_BidiStreamingEchoClientMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoClient)(nil).SendMsg), m)
+}
+
+// Trailer mocks base method.
+func (m *MockStreamTest_BidiStreamingEchoClient) Trailer() metadata.MD {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trailer")
+	ret0, _ := ret[0].(metadata.MD)
+	return ret0
+}
+
+// Trailer indicates an expected call of Trailer.
+func (mr *MockStreamTest_BidiStreamingEchoClientMockRecorder) Trailer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trailer", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoClient)(nil).Trailer))
+}
+
+// MockStreamTestServer is a mock of StreamTestServer interface.
+type MockStreamTestServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamTestServerMockRecorder
+}
+
+// MockStreamTestServerMockRecorder is the mock recorder for MockStreamTestServer.
+type MockStreamTestServerMockRecorder struct {
+	mock *MockStreamTestServer
+}
+
+// NewMockStreamTestServer creates a new mock instance.
+func NewMockStreamTestServer(ctrl *gomock.Controller) *MockStreamTestServer {
+	mock := &MockStreamTestServer{ctrl: ctrl}
+	mock.recorder = &MockStreamTestServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamTestServer) EXPECT() *MockStreamTestServerMockRecorder {
+	return m.recorder
+}
+
+// BidiStreamingEcho mocks base method.
+func (m *MockStreamTestServer) BidiStreamingEcho(arg0 StreamTest_BidiStreamingEchoServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BidiStreamingEcho", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BidiStreamingEcho indicates an expected call of BidiStreamingEcho.
+func (mr *MockStreamTestServerMockRecorder) BidiStreamingEcho(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BidiStreamingEcho", reflect.TypeOf((*MockStreamTestServer)(nil).BidiStreamingEcho), arg0)
+}
+
+// ClientStreamingEcho mocks base method.
+func (m *MockStreamTestServer) ClientStreamingEcho(arg0 StreamTest_ClientStreamingEchoServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientStreamingEcho", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClientStreamingEcho indicates an expected call of ClientStreamingEcho.
+func (mr *MockStreamTestServerMockRecorder) ClientStreamingEcho(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientStreamingEcho", reflect.TypeOf((*MockStreamTestServer)(nil).ClientStreamingEcho), arg0)
+}
+
+// ServerStreamingEcho mocks base method.
+func (m *MockStreamTestServer) ServerStreamingEcho(arg0 *EchoRequest, arg1 StreamTest_ServerStreamingEchoServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServerStreamingEcho", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ServerStreamingEcho indicates an expected call of ServerStreamingEcho.
+func (mr *MockStreamTestServerMockRecorder) ServerStreamingEcho(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerStreamingEcho", reflect.TypeOf((*MockStreamTestServer)(nil).ServerStreamingEcho), arg0, arg1)
+}
+
+// MockUnsafeStreamTestServer is a mock of UnsafeStreamTestServer interface.
+type MockUnsafeStreamTestServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnsafeStreamTestServerMockRecorder
+}
+
+// MockUnsafeStreamTestServerMockRecorder is the mock recorder for MockUnsafeStreamTestServer.
+type MockUnsafeStreamTestServerMockRecorder struct {
+	mock *MockUnsafeStreamTestServer
+}
+
+// NewMockUnsafeStreamTestServer creates a new mock instance.
+func NewMockUnsafeStreamTestServer(ctrl *gomock.Controller) *MockUnsafeStreamTestServer {
+	mock := &MockUnsafeStreamTestServer{ctrl: ctrl}
+	mock.recorder = &MockUnsafeStreamTestServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnsafeStreamTestServer) EXPECT() *MockUnsafeStreamTestServerMockRecorder {
+	return m.recorder
+}
+
+// mustEmbedUnimplementedStreamTestServer mocks base method.
+func (m *MockUnsafeStreamTestServer) mustEmbedUnimplementedStreamTestServer() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "mustEmbedUnimplementedStreamTestServer")
+}
+
+// mustEmbedUnimplementedStreamTestServer indicates an expected call of mustEmbedUnimplementedStreamTestServer.
+func (mr *MockUnsafeStreamTestServerMockRecorder) mustEmbedUnimplementedStreamTestServer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "mustEmbedUnimplementedStreamTestServer", reflect.TypeOf((*MockUnsafeStreamTestServer)(nil).mustEmbedUnimplementedStreamTestServer))
+}
+
+// MockStreamTest_ServerStreamingEchoServer is a mock of StreamTest_ServerStreamingEchoServer interface.
+type MockStreamTest_ServerStreamingEchoServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamTest_ServerStreamingEchoServerMockRecorder
+}
+
+// MockStreamTest_ServerStreamingEchoServerMockRecorder is the mock recorder for MockStreamTest_ServerStreamingEchoServer.
+type MockStreamTest_ServerStreamingEchoServerMockRecorder struct {
+	mock *MockStreamTest_ServerStreamingEchoServer
+}
+
+// NewMockStreamTest_ServerStreamingEchoServer creates a new mock instance.
+func NewMockStreamTest_ServerStreamingEchoServer(ctrl *gomock.Controller) *MockStreamTest_ServerStreamingEchoServer {
+	mock := &MockStreamTest_ServerStreamingEchoServer{ctrl: ctrl}
+	mock.recorder = &MockStreamTest_ServerStreamingEchoServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamTest_ServerStreamingEchoServer) EXPECT() *MockStreamTest_ServerStreamingEchoServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockStreamTest_ServerStreamingEchoServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamTest_ServerStreamingEchoServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoServer)(nil).Context))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockStreamTest_ServerStreamingEchoServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockStreamTest_ServerStreamingEchoServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockStreamTest_ServerStreamingEchoServer) Send(arg0 *EchoResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockStreamTest_ServerStreamingEchoServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockStreamTest_ServerStreamingEchoServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockStreamTest_ServerStreamingEchoServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockStreamTest_ServerStreamingEchoServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockStreamTest_ServerStreamingEchoServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockStreamTest_ServerStreamingEchoServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockStreamTest_ServerStreamingEchoServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockStreamTest_ServerStreamingEchoServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockStreamTest_ServerStreamingEchoServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockStreamTest_ServerStreamingEchoServer)(nil).SetTrailer), arg0)
+}
+
+// MockStreamTest_ClientStreamingEchoServer is a mock of StreamTest_ClientStreamingEchoServer interface.
+type MockStreamTest_ClientStreamingEchoServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamTest_ClientStreamingEchoServerMockRecorder
+}
+
+// MockStreamTest_ClientStreamingEchoServerMockRecorder is the mock recorder for MockStreamTest_ClientStreamingEchoServer.
+type MockStreamTest_ClientStreamingEchoServerMockRecorder struct {
+	mock *MockStreamTest_ClientStreamingEchoServer
+}
+
+// NewMockStreamTest_ClientStreamingEchoServer creates a new mock instance.
+func NewMockStreamTest_ClientStreamingEchoServer(ctrl *gomock.Controller) *MockStreamTest_ClientStreamingEchoServer {
+	mock := &MockStreamTest_ClientStreamingEchoServer{ctrl: ctrl}
+	mock.recorder = &MockStreamTest_ClientStreamingEchoServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamTest_ClientStreamingEchoServer) EXPECT() *MockStreamTest_ClientStreamingEchoServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockStreamTest_ClientStreamingEchoServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamTest_ClientStreamingEchoServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoServer)(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockStreamTest_ClientStreamingEchoServer) Recv() (*EchoRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*EchoRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockStreamTest_ClientStreamingEchoServerMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoServer)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockStreamTest_ClientStreamingEchoServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockStreamTest_ClientStreamingEchoServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoServer)(nil).RecvMsg), m)
+}
+
+// SendAndClose mocks base method.
+func (m *MockStreamTest_ClientStreamingEchoServer) SendAndClose(arg0 *EchoResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendAndClose", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendAndClose indicates an expected call of SendAndClose.
+func (mr *MockStreamTest_ClientStreamingEchoServerMockRecorder) SendAndClose(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendAndClose", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoServer)(nil).SendAndClose), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockStreamTest_ClientStreamingEchoServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockStreamTest_ClientStreamingEchoServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockStreamTest_ClientStreamingEchoServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockStreamTest_ClientStreamingEchoServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockStreamTest_ClientStreamingEchoServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockStreamTest_ClientStreamingEchoServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockStreamTest_ClientStreamingEchoServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockStreamTest_ClientStreamingEchoServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockStreamTest_ClientStreamingEchoServer)(nil).SetTrailer), arg0)
+}
+
+// MockStreamTest_BidiStreamingEchoServer is a mock of StreamTest_BidiStreamingEchoServer interface.
+type MockStreamTest_BidiStreamingEchoServer struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamTest_BidiStreamingEchoServerMockRecorder
+}
+
+// MockStreamTest_BidiStreamingEchoServerMockRecorder is the mock recorder for MockStreamTest_BidiStreamingEchoServer.
+type MockStreamTest_BidiStreamingEchoServerMockRecorder struct {
+	mock *MockStreamTest_BidiStreamingEchoServer
+}
+
+// NewMockStreamTest_BidiStreamingEchoServer creates a new mock instance.
+func NewMockStreamTest_BidiStreamingEchoServer(ctrl *gomock.Controller) *MockStreamTest_BidiStreamingEchoServer {
+	mock := &MockStreamTest_BidiStreamingEchoServer{ctrl: ctrl}
+	mock.recorder = &MockStreamTest_BidiStreamingEchoServerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamTest_BidiStreamingEchoServer) EXPECT() *MockStreamTest_BidiStreamingEchoServerMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockStreamTest_BidiStreamingEchoServer) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockStreamTest_BidiStreamingEchoServerMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoServer)(nil).Context))
+}
+
+// Recv mocks base method.
+func (m *MockStreamTest_BidiStreamingEchoServer) Recv() (*EchoRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*EchoRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *MockStreamTest_BidiStreamingEchoServerMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoServer)(nil).Recv))
+}
+
+// RecvMsg mocks base method.
+func (m_2 *MockStreamTest_BidiStreamingEchoServer) RecvMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "RecvMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecvMsg indicates an expected call of RecvMsg.
+func (mr *MockStreamTest_BidiStreamingEchoServerMockRecorder) RecvMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecvMsg", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoServer)(nil).RecvMsg), m)
+}
+
+// Send mocks base method.
+func (m *MockStreamTest_BidiStreamingEchoServer) Send(arg0 *EchoResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockStreamTest_BidiStreamingEchoServerMockRecorder) Send(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoServer)(nil).Send), arg0)
+}
+
+// SendHeader mocks base method.
+func (m *MockStreamTest_BidiStreamingEchoServer) SendHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendHeader indicates an expected call of SendHeader.
+func (mr *MockStreamTest_BidiStreamingEchoServerMockRecorder) SendHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendHeader", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoServer)(nil).SendHeader), arg0)
+}
+
+// SendMsg mocks base method.
+func (m_2 *MockStreamTest_BidiStreamingEchoServer) SendMsg(m any) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "SendMsg", m)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendMsg indicates an expected call of SendMsg.
+func (mr *MockStreamTest_BidiStreamingEchoServerMockRecorder) SendMsg(m interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMsg", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoServer)(nil).SendMsg), m)
+}
+
+// SetHeader mocks base method.
+func (m *MockStreamTest_BidiStreamingEchoServer) SetHeader(arg0 metadata.MD) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHeader", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHeader indicates an expected call of SetHeader.
+func (mr *MockStreamTest_BidiStreamingEchoServerMockRecorder) SetHeader(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHeader", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoServer)(nil).SetHeader), arg0)
+}
+
+// SetTrailer mocks base method.
+func (m *MockStreamTest_BidiStreamingEchoServer) SetTrailer(arg0 metadata.MD) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTrailer", arg0)
+}
+
+// SetTrailer indicates an expected call of SetTrailer.
+func (mr *MockStreamTest_BidiStreamingEchoServerMockRecorder) SetTrailer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTrailer", reflect.TypeOf((*MockStreamTest_BidiStreamingEchoServer)(nil).SetTrailer), arg0)
+}